@@ -41,4 +41,5 @@ func TestFluent(t *testing.T) {
 		t.Error(err)
 	}
 	t.Log(api.Json())
+	t.Log(api.Yaml())
 }
@@ -4,7 +4,10 @@ go 1.23
 
 toolchain go1.23.2
 
-require github.com/MaiMee1/go-apispec/oas v0.0.0-20241020170502-f9ed6293e7ae
+require (
+	github.com/MaiMee1/go-apispec/oas v0.0.0-20241020170502-f9ed6293e7ae
+	gopkg.in/yaml.v3 v3.0.1
+)
 
 require (
 	github.com/gabriel-vasile/mimetype v1.4.6 // indirect
@@ -17,3 +20,5 @@ require (
 	golang.org/x/sys v0.26.0 // indirect
 	golang.org/x/text v0.19.0 // indirect
 )
+
+replace github.com/MaiMee1/go-apispec/oas => ../oas
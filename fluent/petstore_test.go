@@ -134,7 +134,7 @@ func TestFluent_PetStore(t *testing.T) {
 			operation.WithResponse(http.StatusBadRequest, "Invalid tag value"),
 			operation.WithSecurity(security.Scheme("petstore_auth", "write:pets", "read:pets")),
 		),
-		specs.WithOperation("getPetById", http.MethodGet, "/pet/{pedId}",
+		specs.WithOperation("getPetById", http.MethodGet, "/pet/{petId}",
 			operation.WithSummary("Find pet by ID"),
 			operation.WithDescription("Returns a single pet"),
 			operation.WithTags("pet"),
@@ -150,7 +150,7 @@ func TestFluent_PetStore(t *testing.T) {
 			operation.WithSecurity(security.Scheme("api_key")),
 			operation.WithSecurity(security.Scheme("petstore_auth", "write:pets", "read:pets")),
 		),
-		specs.WithOperation("updatePetWithForm", http.MethodPost, "/pet/{pedId}",
+		specs.WithOperation("updatePetWithForm", http.MethodPost, "/pet/{petId}",
 			operation.WithSummary("Updates a pet in the store with form data"),
 			operation.WithTags("pet"),
 			operation.WithParams(
@@ -161,7 +161,7 @@ func TestFluent_PetStore(t *testing.T) {
 			operation.WithResponse(http.StatusBadRequest, "Invalid input"),
 			operation.WithSecurity(security.Scheme("petstore_auth", "write:pets", "read:pets")),
 		),
-		specs.WithOperation("deletePet", http.MethodDelete, "/pet/{pedId}",
+		specs.WithOperation("deletePet", http.MethodDelete, "/pet/{petId}",
 			operation.WithSummary("Deletes a pet"),
 			operation.WithDescription("deletes a pet"),
 			operation.WithTags("pet"),
@@ -172,7 +172,7 @@ func TestFluent_PetStore(t *testing.T) {
 			operation.WithResponse(http.StatusBadRequest, "Invalid pet value"),
 			operation.WithSecurity(security.Scheme("petstore_auth", "write:pets", "read:pets")),
 		),
-		specs.WithOperation("uploadFile", http.MethodPost, "/pet/{pedId}/uploadImage",
+		specs.WithOperation("uploadFile", http.MethodPost, "/pet/{petId}/uploadImage",
 			operation.WithSummary("uploads an image"),
 			operation.WithTags("pet"),
 			operation.WithParams(
@@ -1,10 +1,13 @@
 package specs
 
 import (
+	"bytes"
 	"encoding/json"
 	"fmt"
 	"slices"
 
+	"gopkg.in/yaml.v3"
+
 	"github.com/MaiMee1/go-apispec/oas/jsonschema"
 	"github.com/MaiMee1/go-apispec/oas/v3"
 )
@@ -46,6 +49,19 @@ func (api *API) Json() string {
 	return string(b)
 }
 
+func (api *API) Yaml() string {
+	var buf bytes.Buffer
+	enc := yaml.NewEncoder(&buf)
+	enc.SetIndent(2)
+	if err := enc.Encode(api.document); err != nil {
+		panic(err)
+	}
+	if err := enc.Close(); err != nil {
+		panic(err)
+	}
+	return buf.String()
+}
+
 func WithSchemaDefinitions(definitions map[string]*oas.Schema) Option {
 	return optionFunc(func(api *API) {
 		if api.document.Components.Schemas == nil {
@@ -0,0 +1,103 @@
+package ecma
+
+import (
+	"slices"
+	"unicode"
+)
+
+// charSet is a set of code points made of sorted, non-overlapping and non-adjacent ranges.
+type charSet []runeRange
+
+type runeRange struct {
+	lo, hi rune
+}
+
+func newCharSet(ranges ...runeRange) charSet {
+	s := charSet(slices.Clone(ranges))
+	slices.SortFunc(s, func(a, b runeRange) int { return int(a.lo - b.lo) })
+	merged := s[:0]
+	for _, r := range s {
+		if n := len(merged); n > 0 && r.lo <= merged[n-1].hi+1 {
+			merged[n-1].hi = max(merged[n-1].hi, r.hi)
+			continue
+		}
+		merged = append(merged, r)
+	}
+	return merged
+}
+
+func single(r rune) charSet {
+	return charSet{{r, r}}
+}
+
+// tableSet returns the code points of t.
+func tableSet(t *unicode.RangeTable) charSet {
+	var ranges []runeRange
+	for _, r := range t.R16 {
+		ranges = appendStride(ranges, rune(r.Lo), rune(r.Hi), rune(r.Stride))
+	}
+	for _, r := range t.R32 {
+		ranges = appendStride(ranges, rune(r.Lo), rune(r.Hi), rune(r.Stride))
+	}
+	return newCharSet(ranges...)
+}
+
+func appendStride(ranges []runeRange, lo, hi, stride rune) []runeRange {
+	if stride == 1 {
+		return append(ranges, runeRange{lo, hi})
+	}
+	for r := lo; r <= hi; r += stride {
+		ranges = append(ranges, runeRange{r, r})
+	}
+	return ranges
+}
+
+func (s charSet) union(t charSet) charSet {
+	return newCharSet(append(slices.Clone(s), t...)...)
+}
+
+func (s charSet) complement() charSet {
+	var c charSet
+	next := rune(0)
+	for _, r := range s {
+		if r.lo > next {
+			c = append(c, runeRange{next, r.lo - 1})
+		}
+		next = r.hi + 1
+	}
+	if next <= unicode.MaxRune {
+		c = append(c, runeRange{next, unicode.MaxRune})
+	}
+	return c
+}
+
+func (s charSet) contains(r rune) bool {
+	_, found := slices.BinarySearchFunc(s, r, func(rr runeRange, r rune) int {
+		switch {
+		case rr.hi < r:
+			return -1
+		case rr.lo > r:
+			return 1
+		default:
+			return 0
+		}
+	})
+	return found
+}
+
+var (
+	digitSet = charSet{{'0', '9'}}
+	wordSet  = newCharSet(runeRange{'0', '9'}, runeRange{'A', 'Z'}, runeRange{'_', '_'}, runeRange{'a', 'z'})
+	// WhiteSpace and LineTerminator: https://tc39.es/ecma262/#sec-white-space
+	spaceSet = tableSet(unicode.Zs).union(newCharSet(
+		runeRange{'\t', '\r'}, // tab, line feed, vertical tab, form feed, carriage return
+		runeRange{'\u2028', '\u2029'},
+		runeRange{'\ufeff', '\ufeff'},
+	))
+	lineTerminatorSet = newCharSet(runeRange{'\n', '\n'}, runeRange{'\r', '\r'}, runeRange{'\u2028', '\u2029'})
+	dotSet            = lineTerminatorSet.complement()
+)
+
+func isWordChar(r rune) bool {
+	return wordSet.contains(r)
+}
@@ -0,0 +1,271 @@
+package ecma
+
+import (
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// machine is the state of matching an input by backtracking.
+type machine struct {
+	input []rune
+	caps  []int // start and end of each capturing group, -1 if not participating
+}
+
+// node is a parsed term of a pattern, which matches the input at i and calls k with the end of each match until k
+// returns true, in the order of preference of ECMA-262.
+type node interface {
+	match(m *machine, i int, k func(j int) bool) bool
+}
+
+type charNode struct {
+	set charSet
+}
+
+func (n charNode) match(m *machine, i int, k func(j int) bool) bool {
+	return i < len(m.input) && n.set.contains(m.input[i]) && k(i+1)
+}
+
+type seqNode []node
+
+func (n seqNode) match(m *machine, i int, k func(j int) bool) bool {
+	if len(n) == 0 {
+		return k(i)
+	}
+	return n[0].match(m, i, func(j int) bool {
+		return n[1:].match(m, j, k)
+	})
+}
+
+type altNode []node
+
+func (n altNode) match(m *machine, i int, k func(j int) bool) bool {
+	for _, alt := range n {
+		if alt.match(m, i, k) {
+			return true
+		}
+	}
+	return false
+}
+
+type assertKind uint8
+
+const (
+	beginAssert assertKind = iota
+	endAssert
+	wordBoundaryAssert
+	notWordBoundaryAssert
+)
+
+type assertNode assertKind
+
+func (n assertNode) match(m *machine, i int, k func(j int) bool) bool {
+	var ok bool
+	switch assertKind(n) {
+	case beginAssert:
+		ok = i == 0
+	case endAssert:
+		ok = i == len(m.input)
+	case wordBoundaryAssert, notWordBoundaryAssert:
+		before := i > 0 && isWordChar(m.input[i-1])
+		after := i < len(m.input) && isWordChar(m.input[i])
+		ok = (before != after) == (assertKind(n) == wordBoundaryAssert)
+	}
+	return ok && k(i)
+}
+
+type groupNode struct {
+	index int // of the capturing group, 0 if not capturing
+	node  node
+}
+
+func (n groupNode) match(m *machine, i int, k func(j int) bool) bool {
+	if n.index == 0 {
+		return n.node.match(m, i, k)
+	}
+	start, end := m.caps[2*n.index], m.caps[2*n.index+1]
+	if n.node.match(m, i, func(j int) bool {
+		s, e := m.caps[2*n.index], m.caps[2*n.index+1]
+		m.caps[2*n.index], m.caps[2*n.index+1] = i, j
+		if k(j) {
+			return true
+		}
+		m.caps[2*n.index], m.caps[2*n.index+1] = s, e
+		return false
+	}) {
+		return true
+	}
+	m.caps[2*n.index], m.caps[2*n.index+1] = start, end
+	return false
+}
+
+type backrefNode struct {
+	index int
+}
+
+func (n backrefNode) match(m *machine, i int, k func(j int) bool) bool {
+	start, end := m.caps[2*n.index], m.caps[2*n.index+1]
+	if start < 0 {
+		return k(i) // a group not participating matches the empty string
+	}
+	length := end - start
+	if i+length > len(m.input) {
+		return false
+	}
+	for j := 0; j < length; j++ {
+		if m.input[start+j] != m.input[i+j] {
+			return false
+		}
+	}
+	return k(i + length)
+}
+
+type lookNode struct {
+	node   node
+	behind bool
+	negate bool
+}
+
+func (n lookNode) match(m *machine, i int, k func(j int) bool) bool {
+	saved := slices.Clone(m.caps)
+	matched := false
+	if n.behind {
+		for start := i; start >= 0 && !matched; start-- {
+			matched = n.node.match(m, start, func(j int) bool { return j == i })
+		}
+	} else {
+		matched = n.node.match(m, i, func(int) bool { return true })
+	}
+	// lookarounds are atomic, and only positive ones keep their captures
+	if matched != n.negate {
+		if n.negate {
+			copy(m.caps, saved)
+		}
+		if k(i) {
+			return true
+		}
+	}
+	copy(m.caps, saved)
+	return false
+}
+
+type repeatNode struct {
+	node     node
+	min, max int // max is -1 if unbounded
+	greedy   bool
+	caps     [2]int // first and last index of capturing groups in node, reset on each iteration
+}
+
+func (n repeatNode) match(m *machine, i int, k func(j int) bool) bool {
+	return n.matchFrom(m, i, 0, k)
+}
+
+func (n repeatNode) matchFrom(m *machine, i int, count int, k func(j int) bool) bool {
+	if n.max >= 0 && count >= n.max {
+		return k(i)
+	}
+	lo, hi := 2*n.caps[0], 2*n.caps[1]+2
+	if n.caps[0] == 0 {
+		lo, hi = 0, 0 // no capturing groups
+	}
+	iterate := func() bool {
+		saved := slices.Clone(m.caps[lo:hi])
+		for c := lo; c < hi; c++ {
+			m.caps[c] = -1
+		}
+		if n.node.match(m, i, func(j int) bool {
+			if j == i && count >= n.min {
+				return false // an iteration matching the empty string ends the repetition
+			}
+			return n.matchFrom(m, j, count+1, k)
+		}) {
+			return true
+		}
+		copy(m.caps[lo:hi], saved)
+		return false
+	}
+	switch {
+	case count < n.min:
+		return iterate()
+	case n.greedy:
+		return iterate() || k(i)
+	default:
+		return k(i) || iterate()
+	}
+}
+
+// re2 returns the translation of n to the syntax of package regexp, or false if n uses features it does not support.
+func re2(n node) (string, bool) {
+	b := &strings.Builder{}
+	ok := writeRE2(b, n)
+	return b.String(), ok
+}
+
+func writeRE2(b *strings.Builder, n node) bool {
+	switch n := n.(type) {
+	case charNode:
+		writeSet(b, n.set)
+	case seqNode:
+		b.WriteString("(?:")
+		for _, e := range n {
+			if !writeRE2(b, e) {
+				return false
+			}
+		}
+		b.WriteString(")")
+	case altNode:
+		b.WriteString("(?:")
+		for i, alt := range n {
+			if i > 0 {
+				b.WriteString("|")
+			}
+			if !writeRE2(b, alt) {
+				return false
+			}
+		}
+		b.WriteString(")")
+	case assertNode:
+		// without the multiline flag, ^ and $ of package regexp match at the beginning and end of text only
+		b.WriteString([]string{`^`, `$`, `\b`, `\B`}[n])
+	case groupNode:
+		b.WriteString("(?:")
+		if !writeRE2(b, n.node) {
+			return false
+		}
+		b.WriteString(")")
+	case repeatNode:
+		b.WriteString("(?:")
+		if !writeRE2(b, n.node) {
+			return false
+		}
+		b.WriteString(")")
+		if n.max < 0 {
+			fmt.Fprintf(b, "{%d,}", n.min)
+		} else {
+			fmt.Fprintf(b, "{%d,%d}", n.min, n.max)
+		}
+		if !n.greedy {
+			b.WriteString("?")
+		}
+	default:
+		// backreferences and lookarounds
+		return false
+	}
+	return true
+}
+
+func writeSet(b *strings.Builder, s charSet) {
+	if len(s) == 0 {
+		b.WriteString(`[^\x{0}-\x{10FFFF}]`)
+		return
+	}
+	b.WriteString("[")
+	for _, r := range s {
+		b.WriteString(`\x{` + strconv.FormatInt(int64(r.lo), 16) + `}`)
+		if r.hi != r.lo {
+			b.WriteString(`-\x{` + strconv.FormatInt(int64(r.hi), 16) + `}`)
+		}
+	}
+	b.WriteString("]")
+}
@@ -0,0 +1,532 @@
+package ecma
+
+import (
+	"fmt"
+	"strconv"
+	"unicode"
+)
+
+// parser parses the Pattern production of ECMA-262 with the "u" flag, see https://tc39.es/ecma262/#sec-patterns.
+type parser struct {
+	src   []rune
+	pos   int
+	ncap  int
+	names map[string]int
+
+	backrefs      []int    // indices of decimal backreferences, checked after parsing
+	namedBackrefs []string // names of named backreferences, checked after parsing
+	named         []*backrefNode
+}
+
+func (p *parser) parse() (node, error) {
+	n, err := p.disjunction()
+	if err != nil {
+		return nil, err
+	}
+	if p.pos < len(p.src) {
+		return nil, p.errorf("unmatched %q", p.src[p.pos])
+	}
+	for _, i := range p.backrefs {
+		if i > p.ncap {
+			return nil, fmt.Errorf("invalid backreference \\%d", i)
+		}
+	}
+	for i, name := range p.namedBackrefs {
+		index, ok := p.names[name]
+		if !ok {
+			return nil, fmt.Errorf("invalid named backreference \\k<%s>", name)
+		}
+		p.named[i].index = index
+	}
+	return n, nil
+}
+
+func (p *parser) errorf(format string, args ...interface{}) error {
+	return fmt.Errorf("at %d: %s", p.pos, fmt.Sprintf(format, args...))
+}
+
+func (p *parser) more() bool {
+	return p.pos < len(p.src)
+}
+
+func (p *parser) peek() rune {
+	if !p.more() {
+		return -1
+	}
+	return p.src[p.pos]
+}
+
+// lookingAt reports whether the source continues with s.
+func (p *parser) lookingAt(s string) bool {
+	rs := []rune(s)
+	if p.pos+len(rs) > len(p.src) {
+		return false
+	}
+	for i, r := range rs {
+		if p.src[p.pos+i] != r {
+			return false
+		}
+	}
+	return true
+}
+
+func (p *parser) disjunction() (node, error) {
+	var alts altNode
+	for {
+		alt, err := p.alternative()
+		if err != nil {
+			return nil, err
+		}
+		alts = append(alts, alt)
+		if p.peek() != '|' {
+			break
+		}
+		p.pos++
+	}
+	if len(alts) == 1 {
+		return alts[0], nil
+	}
+	return alts, nil
+}
+
+func (p *parser) alternative() (node, error) {
+	var seq seqNode
+	for p.more() && p.peek() != '|' && p.peek() != ')' {
+		term, err := p.term()
+		if err != nil {
+			return nil, err
+		}
+		seq = append(seq, term)
+	}
+	if len(seq) == 1 {
+		return seq[0], nil
+	}
+	return seq, nil
+}
+
+func (p *parser) term() (node, error) {
+	switch {
+	case p.lookingAt("^"):
+		p.pos++
+		return assertNode(beginAssert), nil
+	case p.lookingAt("$"):
+		p.pos++
+		return assertNode(endAssert), nil
+	case p.lookingAt(`\b`):
+		p.pos += 2
+		return assertNode(wordBoundaryAssert), nil
+	case p.lookingAt(`\B`):
+		p.pos += 2
+		return assertNode(notWordBoundaryAssert), nil
+	case p.lookingAt("(?="), p.lookingAt("(?!"), p.lookingAt("(?<="), p.lookingAt("(?<!"):
+		return p.lookaround()
+	}
+	first := p.ncap + 1
+	atom, err := p.atom()
+	if err != nil {
+		return nil, err
+	}
+	return p.quantifier(atom, first)
+}
+
+func (p *parser) lookaround() (node, error) {
+	p.pos += 2
+	n := lookNode{}
+	if p.peek() == '<' {
+		n.behind = true
+		p.pos++
+	}
+	n.negate = p.peek() == '!'
+	p.pos++
+	var err error
+	if n.node, err = p.disjunction(); err != nil {
+		return nil, err
+	}
+	if p.peek() != ')' {
+		return nil, p.errorf("missing )")
+	}
+	p.pos++
+	// assertions are not quantifiable with the "u" flag
+	if p.more() && isQuantifierStart(p.peek()) {
+		return nil, p.errorf("nothing to repeat")
+	}
+	return n, nil
+}
+
+func isQuantifierStart(r rune) bool {
+	return r == '*' || r == '+' || r == '?' || r == '{'
+}
+
+// quantifier parses an optional quantifier of atom, whose capturing groups are numbered from first.
+func (p *parser) quantifier(atom node, first int) (node, error) {
+	if !p.more() || !isQuantifierStart(p.peek()) {
+		return atom, nil
+	}
+	n := repeatNode{node: atom, max: -1, greedy: true}
+	switch p.peek() {
+	case '*':
+		p.pos++
+	case '+':
+		n.min = 1
+		p.pos++
+	case '?':
+		n.max = 1
+		p.pos++
+	case '{':
+		p.pos++
+		var ok bool
+		if n.min, ok = p.decimal(); !ok {
+			return nil, p.errorf("incomplete quantifier")
+		}
+		n.max = n.min
+		if p.peek() == ',' {
+			p.pos++
+			n.max = -1
+			if max, ok := p.decimal(); ok {
+				n.max = max
+			}
+		}
+		if p.peek() != '}' {
+			return nil, p.errorf("incomplete quantifier")
+		}
+		p.pos++
+		if n.max >= 0 && n.max < n.min {
+			return nil, p.errorf("numbers out of order in quantifier")
+		}
+	}
+	if p.peek() == '?' {
+		n.greedy = false
+		p.pos++
+	}
+	if p.ncap >= first {
+		n.caps = [2]int{first, p.ncap}
+	}
+	return n, nil
+}
+
+func (p *parser) decimal() (int, bool) {
+	start := p.pos
+	for p.more() && '0' <= p.peek() && p.peek() <= '9' {
+		p.pos++
+	}
+	if p.pos == start {
+		return 0, false
+	}
+	n, err := strconv.Atoi(string(p.src[start:p.pos]))
+	if err != nil {
+		n = int(^uint(0) >> 1) // out of range counts are effectively unbounded
+	}
+	return n, true
+}
+
+func (p *parser) atom() (node, error) {
+	r := p.peek()
+	switch r {
+	case '.':
+		p.pos++
+		return charNode{dotSet}, nil
+	case '(':
+		return p.group()
+	case '[':
+		set, err := p.class()
+		if err != nil {
+			return nil, err
+		}
+		return charNode{set}, nil
+	case '\\':
+		return p.atomEscape()
+	case '*', '+', '?', '{':
+		return nil, p.errorf("nothing to repeat")
+	case ')', ']', '}':
+		return nil, p.errorf("lone %q", r)
+	}
+	p.pos++
+	return charNode{single(r)}, nil
+}
+
+func (p *parser) group() (node, error) {
+	p.pos++
+	n := groupNode{}
+	switch {
+	case p.lookingAt("?:"):
+		p.pos += 2
+	case p.lookingAt("?<"):
+		p.pos += 2
+		name, err := p.groupName()
+		if err != nil {
+			return nil, err
+		}
+		if _, ok := p.names[name]; ok {
+			return nil, p.errorf("duplicate capture group name %q", name)
+		}
+		p.ncap++
+		n.index = p.ncap
+		p.names[name] = n.index
+	case p.lookingAt("?"):
+		return nil, p.errorf("invalid group")
+	default:
+		p.ncap++
+		n.index = p.ncap
+	}
+	var err error
+	if n.node, err = p.disjunction(); err != nil {
+		return nil, err
+	}
+	if p.peek() != ')' {
+		return nil, p.errorf("missing )")
+	}
+	p.pos++
+	return n, nil
+}
+
+// groupName parses a RegExpIdentifierName followed by '>'.
+func (p *parser) groupName() (string, error) {
+	start := p.pos
+	for p.more() && p.peek() != '>' {
+		r := p.peek()
+		if !(r == '$' || r == '_' || unicode.IsLetter(r) || (p.pos > start && (unicode.IsDigit(r) || unicode.Is(unicode.Mn, r) || unicode.Is(unicode.Mc, r) || unicode.Is(unicode.Pc, r)))) {
+			return "", p.errorf("invalid capture group name")
+		}
+		p.pos++
+	}
+	if !p.more() || p.pos == start {
+		return "", p.errorf("invalid capture group name")
+	}
+	name := string(p.src[start:p.pos])
+	p.pos++
+	return name, nil
+}
+
+func (p *parser) atomEscape() (node, error) {
+	p.pos++
+	if !p.more() {
+		return nil, p.errorf(`\ at end of pattern`)
+	}
+	r := p.peek()
+	switch {
+	case '1' <= r && r <= '9':
+		index, _ := p.decimal()
+		p.backrefs = append(p.backrefs, index)
+		return backrefNode{index}, nil
+	case r == 'k':
+		p.pos++
+		if p.peek() != '<' {
+			return nil, p.errorf("invalid named reference")
+		}
+		p.pos++
+		name, err := p.groupName()
+		if err != nil {
+			return nil, err
+		}
+		n := &backrefNode{}
+		p.namedBackrefs = append(p.namedBackrefs, name)
+		p.named = append(p.named, n)
+		return namedBackrefNode{n}, nil
+	}
+	if set, ok, err := p.classEscape(); ok || err != nil {
+		return charNode{set}, err
+	}
+	c, err := p.characterEscape(false)
+	if err != nil {
+		return nil, err
+	}
+	return charNode{single(c)}, nil
+}
+
+// namedBackrefNode is a backreference by name, whose index is set once all groups are parsed.
+type namedBackrefNode struct {
+	*backrefNode
+}
+
+func (n namedBackrefNode) match(m *machine, i int, k func(j int) bool) bool {
+	return n.backrefNode.match(m, i, k)
+}
+
+// classEscape parses a CharacterClassEscape after '\', returning false if there is none.
+func (p *parser) classEscape() (charSet, bool, error) {
+	r := p.peek()
+	var set charSet
+	switch r {
+	case 'd', 'D':
+		set = digitSet
+	case 's', 'S':
+		set = spaceSet
+	case 'w', 'W':
+		set = wordSet
+	case 'p', 'P':
+		p.pos++
+		if p.peek() != '{' {
+			return nil, false, p.errorf("invalid property name")
+		}
+		start := p.pos + 1
+		for p.more() && p.peek() != '}' {
+			p.pos++
+		}
+		if !p.more() {
+			return nil, false, p.errorf("invalid property name")
+		}
+		var err error
+		if set, err = property(string(p.src[start:p.pos])); err != nil {
+			return nil, false, p.errorf("%v", err)
+		}
+	default:
+		return nil, false, nil
+	}
+	p.pos++
+	if unicode.IsUpper(r) {
+		set = set.complement()
+	}
+	return set, true, nil
+}
+
+// characterEscape parses a CharacterEscape after '\', in a class if inClass.
+func (p *parser) characterEscape(inClass bool) (rune, error) {
+	r := p.peek()
+	p.pos++
+	switch r {
+	case 'f':
+		return '\f', nil
+	case 'n':
+		return '\n', nil
+	case 'r':
+		return '\r', nil
+	case 't':
+		return '\t', nil
+	case 'v':
+		return '\v', nil
+	case 'c':
+		if c := p.peek(); ('a' <= c && c <= 'z') || ('A' <= c && c <= 'Z') {
+			p.pos++
+			return c % 32, nil
+		}
+		return 0, p.errorf("invalid control escape")
+	case '0':
+		if c := p.peek(); '0' <= c && c <= '9' {
+			return 0, p.errorf("invalid decimal escape")
+		}
+		return 0, nil
+	case 'x':
+		if c, ok := p.hex(2); ok {
+			return c, nil
+		}
+		return 0, p.errorf("invalid escape")
+	case 'u':
+		return p.unicodeEscape()
+	case '^', '$', '\\', '.', '*', '+', '?', '(', ')', '[', ']', '{', '}', '|', '/':
+		return r, nil
+	case '-':
+		if inClass {
+			return r, nil
+		}
+	}
+	return 0, p.errorf("invalid escape \\%c", r)
+}
+
+func (p *parser) unicodeEscape() (rune, error) {
+	if p.peek() == '{' {
+		p.pos++
+		start := p.pos
+		for p.more() && p.peek() != '}' {
+			p.pos++
+		}
+		c, err := strconv.ParseUint(string(p.src[start:p.pos]), 16, 32)
+		if !p.more() || err != nil || c > unicode.MaxRune {
+			return 0, p.errorf("invalid unicode escape")
+		}
+		p.pos++
+		return rune(c), nil
+	}
+	c, ok := p.hex(4)
+	if !ok {
+		return 0, p.errorf("invalid unicode escape")
+	}
+	// a surrogate pair of escapes is a single code point
+	if 0xD800 <= c && c <= 0xDBFF && p.lookingAt(`\u`) {
+		pos := p.pos
+		p.pos += 2
+		if lo, ok := p.hex(4); ok && 0xDC00 <= lo && lo <= 0xDFFF {
+			return (c-0xD800)<<10 + (lo - 0xDC00) + 0x10000, nil
+		}
+		p.pos = pos
+	}
+	return c, nil
+}
+
+func (p *parser) hex(n int) (rune, bool) {
+	if p.pos+n > len(p.src) {
+		return 0, false
+	}
+	c, err := strconv.ParseUint(string(p.src[p.pos:p.pos+n]), 16, 32)
+	if err != nil {
+		return 0, false
+	}
+	p.pos += n
+	return rune(c), true
+}
+
+// class parses a CharacterClass.
+func (p *parser) class() (charSet, error) {
+	p.pos++
+	negate := false
+	if p.peek() == '^' {
+		negate = true
+		p.pos++
+	}
+	var set charSet
+	for p.peek() != ']' {
+		if !p.more() {
+			return nil, p.errorf("missing ]")
+		}
+		lo, loSet, err := p.classAtom()
+		if err != nil {
+			return nil, err
+		}
+		if p.peek() != '-' || p.pos+1 >= len(p.src) || p.src[p.pos+1] == ']' {
+			set = set.union(loSet)
+			continue
+		}
+		p.pos++
+		hi, _, err := p.classAtom()
+		if err != nil {
+			return nil, err
+		}
+		if lo < 0 || hi < 0 {
+			return nil, p.errorf("invalid character class range")
+		}
+		if lo > hi {
+			return nil, p.errorf("range out of order in character class")
+		}
+		set = set.union(charSet{{lo, hi}})
+	}
+	p.pos++
+	if negate {
+		set = set.complement()
+	}
+	return set, nil
+}
+
+// classAtom parses a ClassAtom, returning the character, or -1 if it is a class escape, and the set of the atom.
+func (p *parser) classAtom() (rune, charSet, error) {
+	r := p.peek()
+	p.pos++
+	if r != '\\' {
+		return r, single(r), nil
+	}
+	if !p.more() {
+		return 0, nil, p.errorf(`\ at end of pattern`)
+	}
+	if p.peek() == 'b' {
+		p.pos++
+		return '\b', single('\b'), nil
+	}
+	if set, ok, err := p.classEscape(); err != nil {
+		return 0, nil, err
+	} else if ok {
+		return -1, set, nil
+	}
+	c, err := p.characterEscape(true)
+	if err != nil {
+		return 0, nil, err
+	}
+	return c, single(c), nil
+}
@@ -0,0 +1,89 @@
+// Package ecma implements regular expressions of the ECMA-262 dialect with the "u" flag, as used by JSON Schema,
+// see https://json-schema.org/draft/2020-12/json-schema-core#section-6.4.
+//
+// Patterns are translated to the syntax of package regexp when possible, and otherwise matched by backtracking,
+// which supports lookaround assertions and backreferences.
+package ecma
+
+import (
+	"regexp"
+	"unicode/utf8"
+)
+
+// Regexp is a compiled ECMA-262 regular expression. It is safe for concurrent use.
+type Regexp struct {
+	expr string
+	re   *regexp.Regexp // translation of the expression, if any
+	prog node
+	ncap int
+}
+
+// Compile parses expr and returns a Regexp which matches strings as ECMA-262 RegExp.prototype.test with the "u"
+// flag, or a *SyntaxError.
+func Compile(expr string) (*Regexp, error) {
+	return compile(expr, true)
+}
+
+// MustCompile is like Compile but panics if expr cannot be parsed.
+func MustCompile(expr string) *Regexp {
+	r, err := Compile(expr)
+	if err != nil {
+		panic(`ecma: Compile(` + quote(expr) + `): ` + err.Error())
+	}
+	return r
+}
+
+func compile(expr string, translate bool) (*Regexp, error) {
+	p := &parser{src: []rune(expr), names: make(map[string]int)}
+	if !utf8.ValidString(expr) {
+		return nil, &SyntaxError{Expr: expr, Msg: "invalid UTF-8"}
+	}
+	prog, err := p.parse()
+	if err != nil {
+		return nil, &SyntaxError{Expr: expr, Msg: err.Error()}
+	}
+	r := &Regexp{expr: expr, prog: prog, ncap: p.ncap}
+	if translate {
+		if s, ok := re2(prog); ok {
+			// the translation may still exceed the limits of package regexp, such as on repetitions
+			r.re, _ = regexp.Compile(s)
+		}
+	}
+	return r, nil
+}
+
+// String returns the source text used to compile r.
+func (r *Regexp) String() string {
+	return r.expr
+}
+
+// MatchString reports whether s contains any match of r.
+func (r *Regexp) MatchString(s string) bool {
+	if r.re != nil {
+		return r.re.MatchString(s)
+	}
+	m := &machine{input: []rune(s), caps: make([]int, 2*(r.ncap+1))}
+	for start := 0; start <= len(m.input); start++ {
+		for i := range m.caps {
+			m.caps[i] = -1
+		}
+		if r.prog.match(m, start, func(int) bool { return true }) {
+			return true
+		}
+	}
+	return false
+}
+
+// SyntaxError is an expression which is not an ECMA-262 regular expression.
+type SyntaxError struct {
+	Expr string
+	Msg  string
+}
+
+func (e *SyntaxError) Error() string {
+	return "ecma: invalid regular expression " + quote(e.Expr) + ": " + e.Msg
+}
+
+func quote(s string) string {
+	return "`" + s + "`"
+}
@@ -0,0 +1,160 @@
+package ecma
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+)
+
+func TestRegexp_MatchString(t *testing.T) {
+	var testCases = []struct {
+		expr  string
+		input string
+		want  bool
+	}{
+		{`abc`, "xabcx", true},
+		{`^abc$`, "xabcx", false},
+		{`^abc$`, "abc\n", false},
+		{`^a.c$`, "a\nc", false},
+		{`^a.c$`, "aéc", true},
+		{`^\d+$`, "123", true},
+		{`^\d+$`, "١٢", false},
+		{`^\w+$`, "abc_1", true},
+		{`^\w+$`, "é", false},
+		{`^\s$`, " ", true},
+		{`^\s$`, "\ufeff", true},
+		{`^\S$`, " ", false},
+		{`^\p{Letter}+$`, "été", true},
+		{`^\p{L}+$`, "123", false},
+		{`^\p{Lu}$`, "A", true},
+		{`^\p{gc=Nd}+$`, "١٢", true},
+		{`^\p{Script=Greek}+$`, "αβ", true},
+		{`^\P{ASCII}$`, "a", false},
+		{`^\p{Any}$`, "\U0001F600", true},
+		{`^.$`, "\U0001F600", true},
+		{`^[\u{1F600}-\u{1F64F}]$`, "\U0001F600", true},
+		{`^😀$`, "\U0001F600", true},
+		{`^[^a-z]$`, "A", true},
+		{`^[\d-]+$`, "1-2", true},
+		{`^[\b]$`, "\b", true},
+		{`\bfoo\b`, "a foo b", true},
+		{`\Bfoo`, "afoo", true},
+		{`^\cJ$`, "\n", true},
+		{`^\x41B$`, "AB", true},
+		{`^a{2,3}$`, "aaaa", false},
+		{`^a{2,}?$`, "aaaa", true},
+		{`^(?:ab)*$`, "ababab", true},
+		{`^(a|ab)(c|bcd)$`, "abcd", true},
+		{`^(?=.*\d)(?=.*[a-z]).{8,}$`, "password1", true},
+		{`^(?=.*\d)(?=.*[a-z]).{8,}$`, "password", false},
+		{`^(?!foo).*$`, "foobar", false},
+		{`^(?!foo).*$`, "barfoo", true},
+		{`(?<=\$)\d+`, "$42", true},
+		{`(?<=\$)\d+`, "42", false},
+		{`(?<!\$)\b\d+`, "$42", false},
+		{`^(\w)\1$`, "aa", true},
+		{`^(\w)\1$`, "ab", false},
+		{`^(?<x>\w)\k<x>$`, "bb", true},
+		{`^(?<x>\w)\k<x>$`, "bc", false},
+		{`^(a)|\1b$`, "b", true},
+		{`^(?:(a)|b)\1$`, "b", true},
+		{`^(?:(a)|b)*\1$`, "ab", true},
+		{`^(?=(a+))a*b\1$`, "baaabac", false},
+		{`(?=(a+))a*b\1`, "baaabac", true},
+		{`^(a*)*$`, "aaaa", true},
+		{`^(a+|b)*?c$`, "abac", true},
+	}
+	for i, tt := range testCases {
+		t.Run(fmt.Sprintf("%d", i), func(t *testing.T) {
+			for _, translate := range []bool{true, false} {
+				re, err := compile(tt.expr, translate)
+				if err != nil {
+					t.Fatalf("got error %v, want <nil>", err)
+				}
+
+				// Act
+				got := re.MatchString(tt.input)
+
+				if got != tt.want {
+					t.Errorf("%s.MatchString(%q) = %v, want %v (translate=%v)", quote(tt.expr), tt.input, got, tt.want, translate)
+				}
+			}
+		})
+	}
+}
+
+func TestCompile(t *testing.T) {
+	var testCases = []struct {
+		expr string
+		want string // the message of the syntax error, or empty if expr is valid
+	}{
+		{`^[a-z]+$`, ``},
+		{`(?<name>a)(?<other>b)`, ``},
+		{`\/`, ``},
+		{`[\-]`, ``},
+		{`\0`, ``},
+		{`\a`, `at 2: invalid escape \a`},
+		{`\-`, `at 2: invalid escape \-`},
+		{`\00`, `at 2: invalid decimal escape`},
+		{`\1`, `invalid backreference \1`},
+		{`(a)\2`, `invalid backreference \2`},
+		{`\k<name>`, `invalid named backreference \k<name>`},
+		{`(?<a>x)(?<a>y)`, `at 12: duplicate capture group name "a"`},
+		{`(?<1a>x)`, `at 3: invalid capture group name`},
+		{`(?i)a`, `at 1: invalid group`},
+		{`(`, `at 1: missing )`},
+		{`)`, `at 0: unmatched ')'`},
+		{`[a`, `at 2: missing ]`},
+		{`]`, `at 0: lone ']'`},
+		{`{`, `at 0: nothing to repeat`},
+		{`}`, `at 0: lone '}'`},
+		{`a{2,1}`, `at 6: numbers out of order in quantifier`},
+		{`a{`, `at 2: incomplete quantifier`},
+		{`*a`, `at 0: nothing to repeat`},
+		{`a**`, `at 2: nothing to repeat`},
+		{`(?=a)*`, `at 5: nothing to repeat`},
+		{`[z-a]`, `at 4: range out of order in character class`},
+		{`[\d-z]`, `at 5: invalid character class range`},
+		{`\p{Unknown}`, `at 10: invalid property name "Unknown"`},
+		{`\p{Script=Unknown}`, `at 17: invalid property value "Unknown"`},
+		{`\u{110000}`, `at 9: invalid unicode escape`},
+		{`\x4`, `at 2: invalid escape`},
+		{`\cX`, ``},
+		{`\c1`, `at 2: invalid control escape`},
+		{`\`, `at 1: \ at end of pattern`},
+	}
+	for i, tt := range testCases {
+		t.Run(fmt.Sprintf("%d", i), func(t *testing.T) {
+			// Act
+			_, err := Compile(tt.expr)
+
+			var got string
+			if err != nil {
+				var syntaxErr *SyntaxError
+				if !errors.As(err, &syntaxErr) {
+					t.Fatalf("got error %T, want *SyntaxError", err)
+				}
+				got = syntaxErr.Msg
+			}
+			if got != tt.want {
+				t.Errorf("Compile(%s) error = %q, want %q", quote(tt.expr), got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRegexp_MatchString_backtracking(t *testing.T) {
+	// a pattern which cannot be translated must still match long inputs
+	re := MustCompile(`^(?=a)(a+)\1$`)
+	if re.re != nil {
+		t.Fatalf("got translated pattern, want backtracking")
+	}
+
+	// Act
+	got := re.MatchString(strings.Repeat("a", 2000))
+
+	if !got {
+		t.Errorf("got %v, want true", got)
+	}
+}
@@ -0,0 +1,144 @@
+package ecma
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+)
+
+// categoryAliases maps the long names and aliases of General_Category values to their short names.
+var categoryAliases = map[string]string{
+	"Letter":                "L",
+	"Cased_Letter":          "LC",
+	"Uppercase_Letter":      "Lu",
+	"Lowercase_Letter":      "Ll",
+	"Titlecase_Letter":      "Lt",
+	"Modifier_Letter":       "Lm",
+	"Other_Letter":          "Lo",
+	"Mark":                  "M",
+	"Combining_Mark":        "M",
+	"Nonspacing_Mark":       "Mn",
+	"Spacing_Mark":          "Mc",
+	"Enclosing_Mark":        "Me",
+	"Number":                "N",
+	"Decimal_Number":        "Nd",
+	"digit":                 "Nd",
+	"Letter_Number":         "Nl",
+	"Other_Number":          "No",
+	"Punctuation":           "P",
+	"punct":                 "P",
+	"Connector_Punctuation": "Pc",
+	"Dash_Punctuation":      "Pd",
+	"Open_Punctuation":      "Ps",
+	"Close_Punctuation":     "Pe",
+	"Initial_Punctuation":   "Pi",
+	"Final_Punctuation":     "Pf",
+	"Other_Punctuation":     "Po",
+	"Symbol":                "S",
+	"Math_Symbol":           "Sm",
+	"Currency_Symbol":       "Sc",
+	"Modifier_Symbol":       "Sk",
+	"Other_Symbol":          "So",
+	"Separator":             "Z",
+	"Space_Separator":       "Zs",
+	"Line_Separator":        "Zl",
+	"Paragraph_Separator":   "Zp",
+	"Other":                 "C",
+	"Control":               "Cc",
+	"cntrl":                 "Cc",
+	"Format":                "Cf",
+	"Surrogate":             "Cs",
+	"Private_Use":           "Co",
+	"Unassigned":            "Cn",
+}
+
+// property returns the code points of a UnicodePropertyValueExpression, see
+// https://tc39.es/ecma262/#sec-runtime-semantics-unicodematchproperty-p.
+func property(expr string) (charSet, error) {
+	name, value, ok := strings.Cut(expr, "=")
+	if !ok {
+		if set, ok := category(expr); ok {
+			return set, nil
+		}
+		if set, ok := binaryProperty(expr); ok {
+			return set, nil
+		}
+		return nil, fmt.Errorf("invalid property name %q", expr)
+	}
+	switch name {
+	case "General_Category", "gc":
+		if set, ok := category(value); ok {
+			return set, nil
+		}
+	case "Script", "sc", "Script_Extensions", "scx":
+		// script extensions are not available, so they are approximated by scripts
+		if t, ok := unicode.Scripts[value]; ok {
+			return tableSet(t), nil
+		}
+	default:
+		return nil, fmt.Errorf("invalid property name %q", name)
+	}
+	return nil, fmt.Errorf("invalid property value %q", value)
+}
+
+// category returns the code points of the General_Category named name.
+func category(name string) (charSet, bool) {
+	if short, ok := categoryAliases[name]; ok {
+		name = short
+	}
+	switch name {
+	case "LC":
+		return tableSet(unicode.Lu).union(tableSet(unicode.Ll)).union(tableSet(unicode.Lt)), true
+	case "Cn":
+		return assigned().complement(), true
+	case "C":
+		// unlike the table of package unicode, Other includes unassigned code points
+		return tableSet(unicode.C).union(assigned().complement()), true
+	}
+	if len(name) > 2 {
+		return nil, false // only short names are keys of unicode.Categories
+	}
+	t, ok := unicode.Categories[name]
+	if !ok {
+		return nil, false
+	}
+	return tableSet(t), true
+}
+
+// assigned returns the code points of any General_Category other than Unassigned.
+func assigned() charSet {
+	var set charSet
+	for name, t := range unicode.Categories {
+		if len(name) == 1 {
+			set = set.union(tableSet(t))
+		}
+	}
+	return set
+}
+
+// binaryProperty returns the code points of the binary property named name.
+func binaryProperty(name string) (charSet, bool) {
+	switch name {
+	case "Any":
+		return charSet{{0, unicode.MaxRune}}, true
+	case "ASCII":
+		return charSet{{0, unicode.MaxASCII}}, true
+	case "Assigned":
+		return assigned(), true
+	case "Alphabetic", "Alpha":
+		set := tableSet(unicode.Other_Alphabetic).union(tableSet(unicode.Nl))
+		return set.union(tableSet(unicode.L)), true
+	case "Lowercase", "Lower":
+		return tableSet(unicode.Ll).union(tableSet(unicode.Other_Lowercase)), true
+	case "Uppercase", "Upper":
+		return tableSet(unicode.Lu).union(tableSet(unicode.Other_Uppercase)), true
+	}
+	if strings.HasPrefix(name, "Other_") {
+		return nil, false // contributory properties are not exposed by ECMA-262
+	}
+	t, ok := unicode.Properties[name]
+	if !ok {
+		return nil, false
+	}
+	return tableSet(t), true
+}
@@ -21,6 +21,9 @@ func GetTranslator(locale string) (trans ut.Translator, found bool) {
 func GetFallback() ut.Translator {
 	return uni.GetFallback()
 }
+func AddTranslator(translator locales.Translator, override bool) error {
+	return uni.AddTranslator(translator, override)
+}
 func VerifyTranslations() (err error) {
 	return uni.VerifyTranslations()
 }
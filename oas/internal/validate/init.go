@@ -4,13 +4,28 @@ import (
 	"fmt"
 	"net/url"
 	"reflect"
+	"strings"
 
 	"github.com/go-playground/validator/v10"
+
+	"github.com/MaiMee1/go-apispec/oas/internal/ecma"
 )
 
 func init() {
 	validate = validator.New()
 
+	// use JSON names in error namespaces, marking embedded structs by EmbeddedName
+	validate.RegisterTagNameFunc(func(sf reflect.StructField) string {
+		name, _, _ := strings.Cut(sf.Tag.Get("json"), ",")
+		if name == "" && sf.Anonymous {
+			return EmbeddedName
+		}
+		if name == "-" {
+			return ""
+		}
+		return name
+	})
+
 	if err := validate.RegisterValidation("url_fragment", func(fl validator.FieldLevel) bool {
 		v := fl.Field()
 		if v.Kind() != reflect.String {
@@ -32,9 +47,12 @@ func init() {
 	}
 
 	if err := validate.RegisterValidation("regex", func(fl validator.FieldLevel) bool {
-		fl.Field()
-		// TODO: use subprocess to use node to validate regex
-		return true
+		v := fl.Field()
+		if v.Kind() != reflect.String {
+			return true // skip
+		}
+		_, err := ecma.Compile(v.String())
+		return err == nil
 	}); err != nil {
 		panic(err)
 	}
@@ -2,6 +2,7 @@ package validate
 
 import (
 	"context"
+	"strings"
 
 	ut "github.com/go-playground/universal-translator"
 	"github.com/go-playground/validator/v10"
@@ -11,6 +12,9 @@ var (
 	validate *validator.Validate
 )
 
+// EmbeddedName is the name of embedded structs without JSON names in the namespace of validation errors.
+const EmbeddedName = "\x00"
+
 func Struct(s interface{}) error {
 	return validate.Struct(s)
 }
@@ -74,3 +78,37 @@ type Validate interface {
 	VarWithValue(field interface{}, other interface{}, tag string) error
 	VarWithValueCtx(ctx context.Context, field interface{}, other interface{}, tag string) (err error)
 }
+
+// Tokens splits the namespace of a validation error into the JSON names of its fields and keys of its elements,
+// without the name of the top-level struct and of embedded structs.
+func Tokens(namespace string) []string {
+	var tokens []string
+	_, s, _ := strings.Cut(namespace, ".")
+	for len(s) > 0 {
+		var token string
+		switch s[0] {
+		case '.':
+			s = s[1:]
+			continue
+		case '[':
+			end := len(s)
+			for i := 1; i < len(s); i++ {
+				if s[i] == ']' && (i+1 == len(s) || s[i+1] == '.' || s[i+1] == '[') {
+					end = i
+					break
+				}
+			}
+			token, s = s[1:end], s[min(end+1, len(s)):]
+		default:
+			end := strings.IndexAny(s, ".[")
+			if end == -1 {
+				end = len(s)
+			}
+			token, s = s[:end], s[end:]
+		}
+		if token != EmbeddedName {
+			tokens = append(tokens, token)
+		}
+	}
+	return tokens
+}
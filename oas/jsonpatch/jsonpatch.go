@@ -0,0 +1,282 @@
+// Package jsonpatch implements JSON Patch according to RFC 6902 and JSON Merge Patch according to RFC 7386
+//
+// Patches apply to raw JSON, or to Go values such as oas.OpenAPI through their JSON encoding.
+//
+// See https://www.rfc-editor.org/rfc/rfc6902 and https://www.rfc-editor.org/rfc/rfc7386
+package jsonpatch
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"maps"
+	"math/big"
+	"reflect"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/MaiMee1/go-apispec/oas/jsonpointer"
+)
+
+type Op string
+
+const (
+	AddOp     Op = "add"
+	RemoveOp  Op = "remove"
+	ReplaceOp Op = "replace"
+	MoveOp    Op = "move"
+	CopyOp    Op = "copy"
+	TestOp    Op = "test"
+)
+
+// Operation is an operation of a JSON Patch. From is the source of MoveOp and CopyOp, and Value the value of AddOp,
+// ReplaceOp and TestOp.
+type Operation struct {
+	Op    Op              `json:"op"`
+	Path  jsonpointer.Ptr `json:"path"`
+	From  jsonpointer.Ptr `json:"from,omitempty"`
+	Value any             `json:"value,omitempty"`
+}
+
+// MarshalJSON encodes op with its value even if it is null, for the operations which have one.
+func (op Operation) MarshalJSON() ([]byte, error) {
+	type operation Operation
+	if op.Op != AddOp && op.Op != ReplaceOp && op.Op != TestOp {
+		return json.Marshal(operation(op))
+	}
+	return json.Marshal(struct {
+		operation
+		Value any `json:"value"`
+	}{operation(op), op.Value})
+}
+
+// Patch is a JSON Patch, a sequence of operations applied in order.
+type Patch []Operation
+
+// Apply applies p to the value v points to, through its JSON encoding. If the patch fails, v is left unchanged.
+//
+// v is decoded anew from the patched JSON, so what its JSON encoding does not hold is reset, such as the scope of the
+// references of an oas.OpenAPI, which OpenAPI.Validate binds again.
+func (p Patch) Apply(v any) error {
+	document, err := decode(v)
+	if err != nil {
+		return err
+	}
+	if document, err = p.apply(document); err != nil {
+		return err
+	}
+	return encode(document, v)
+}
+
+// ApplyJson applies p to the JSON document b and returns the patched document.
+func (p Patch) ApplyJson(b []byte) ([]byte, error) {
+	document, err := unmarshal(b)
+	if err != nil {
+		return nil, err
+	}
+	if document, err = p.apply(document); err != nil {
+		return nil, err
+	}
+	return json.Marshal(document)
+}
+
+func (p Patch) apply(document any) (any, error) {
+	for i, op := range p {
+		if err := op.apply(&document); err != nil {
+			return nil, fmt.Errorf("jsonpatch: operation %d %s %q: %w", i, op.Op, op.Path, err)
+		}
+	}
+	return document, nil
+}
+
+func (op Operation) apply(document *any) error {
+	switch op.Op {
+	case AddOp:
+		value, err := decode(op.Value)
+		if err != nil {
+			return err
+		}
+		return op.Path.Add(document, value)
+	case RemoveOp:
+		return op.Path.Remove(document)
+	case ReplaceOp:
+		if _, err := op.Path.Access(*document); err != nil {
+			return err
+		}
+		value, err := decode(op.Value)
+		if err != nil {
+			return err
+		}
+		return op.Path.Set(document, value)
+	case MoveOp:
+		if op.From == op.Path {
+			_, err := op.From.Access(*document)
+			return err
+		}
+		if strings.HasPrefix(string(op.Path), string(op.From)+"/") {
+			return errors.New("cannot move a value into itself")
+		}
+		value, err := access(*document, op.From)
+		if err != nil {
+			return err
+		}
+		if err := op.From.Remove(document); err != nil {
+			return err
+		}
+		return op.Path.Add(document, value)
+	case CopyOp:
+		value, err := access(*document, op.From)
+		if err != nil {
+			return err
+		}
+		if value, err = decode(value); err != nil {
+			return err
+		}
+		return op.Path.Add(document, value)
+	case TestOp:
+		actual, err := access(*document, op.Path)
+		if err != nil {
+			return err
+		}
+		value, err := decode(op.Value)
+		if err != nil {
+			return err
+		}
+		if !equal(actual, value) {
+			return fmt.Errorf("value is %s", marshal(actual))
+		}
+		return nil
+	default:
+		return fmt.Errorf("invalid op %q", op.Op)
+	}
+}
+
+// Diff returns a Patch which changes the JSON encoding of a to that of b.
+//
+// Arrays are compared element by element, so an element inserted or removed other than at the end is a replacement of
+// every element after it.
+func Diff(a, b any) (Patch, error) {
+	x, err := decode(a)
+	if err != nil {
+		return nil, err
+	}
+	y, err := decode(b)
+	if err != nil {
+		return nil, err
+	}
+	var p Patch
+	p.diff(x, y, "")
+	return p, nil
+}
+
+func (p *Patch) diff(a, b any, path jsonpointer.Ptr) {
+	switch a := a.(type) {
+	case map[string]any:
+		if b, ok := b.(map[string]any); ok {
+			for _, k := range slices.Sorted(maps.Keys(a)) {
+				if _, ok := b[k]; !ok {
+					*p = append(*p, Operation{Op: RemoveOp, Path: path.Append(k)})
+				}
+			}
+			for _, k := range slices.Sorted(maps.Keys(b)) {
+				if v, ok := a[k]; ok {
+					p.diff(v, b[k], path.Append(k))
+				} else {
+					*p = append(*p, Operation{Op: AddOp, Path: path.Append(k), Value: b[k]})
+				}
+			}
+			return
+		}
+	case []any:
+		if b, ok := b.([]any); ok {
+			for i := range min(len(a), len(b)) {
+				p.diff(a[i], b[i], path.Append(strconv.Itoa(i)))
+			}
+			for i := len(a) - 1; i >= len(b); i-- {
+				*p = append(*p, Operation{Op: RemoveOp, Path: path.Append(strconv.Itoa(i))})
+			}
+			for i := len(a); i < len(b); i++ {
+				*p = append(*p, Operation{Op: AddOp, Path: path.Append(strconv.Itoa(i)), Value: b[i]})
+			}
+			return
+		}
+	}
+	if !equal(a, b) {
+		*p = append(*p, Operation{Op: ReplaceOp, Path: path, Value: b})
+	}
+}
+
+// decode returns the JSON encoding of v decoded into an interface{}, with numbers as json.Number to keep their
+// precision. The result shares nothing with v.
+func decode(v any) (any, error) {
+	b, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+	return unmarshal(b)
+}
+
+func unmarshal(b []byte) (any, error) {
+	var v any
+	d := json.NewDecoder(bytes.NewReader(b))
+	d.UseNumber()
+	if err := d.Decode(&v); err != nil {
+		return nil, err
+	}
+	return v, nil
+}
+
+// encode replaces the value v points to by the JSON encoding of document decoded into a new value.
+func encode(document any, v any) error {
+	b, err := json.Marshal(document)
+	if err != nil {
+		return err
+	}
+	rv := reflect.ValueOf(v)
+	if rv.Kind() != reflect.Ptr || rv.IsNil() {
+		return fmt.Errorf("jsonpatch: cannot decode into %T, want a pointer", v)
+	}
+	// json.Unmarshal would merge objects into the existing maps and structs of v
+	decoded := reflect.New(rv.Elem().Type())
+	if err := json.Unmarshal(b, decoded.Interface()); err != nil {
+		return err
+	}
+	rv.Elem().Set(decoded.Elem())
+	return nil
+}
+
+func access(document any, ptr jsonpointer.Ptr) (any, error) {
+	v, err := ptr.Access(document)
+	if err != nil {
+		return nil, err
+	}
+	return v.Interface(), nil
+}
+
+// equal reports whether the decoded JSON values a and b are equal, comparing numbers by value.
+func equal(a, b any) bool {
+	switch a := a.(type) {
+	case []any:
+		b, ok := b.([]any)
+		return ok && slices.EqualFunc(a, b, equal)
+	case map[string]any:
+		b, ok := b.(map[string]any)
+		return ok && maps.EqualFunc(a, b, equal)
+	case json.Number:
+		b, ok := b.(json.Number)
+		if !ok {
+			return false
+		}
+		x, okX := new(big.Rat).SetString(a.String())
+		y, okY := new(big.Rat).SetString(b.String())
+		return okX && okY && x.Cmp(y) == 0
+	}
+	return a == b
+}
+
+func marshal(v any) string {
+	b, _ := json.Marshal(v)
+	return string(b)
+}
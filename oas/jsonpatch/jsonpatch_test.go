@@ -0,0 +1,218 @@
+package jsonpatch
+
+import (
+	"encoding/json"
+	"fmt"
+	"testing"
+
+	"github.com/MaiMee1/go-apispec/oas/jsonpointer"
+	oas "github.com/MaiMee1/go-apispec/oas/v3"
+)
+
+func TestPatch_ApplyJson(t *testing.T) {
+	// the examples of RFC 6902 appendix A
+	tests := []struct {
+		document string
+		patch    string
+		want     string // the patched document, or empty if the patch fails
+	}{
+		{`{"foo":"bar"}`, `[{"op":"add","path":"/baz","value":"qux"}]`, `{"baz":"qux","foo":"bar"}`},
+		{`{"foo":["bar","baz"]}`, `[{"op":"add","path":"/foo/1","value":"qux"}]`, `{"foo":["bar","qux","baz"]}`},
+		{`{"baz":"qux","foo":"bar"}`, `[{"op":"remove","path":"/baz"}]`, `{"foo":"bar"}`},
+		{`{"foo":["bar","qux","baz"]}`, `[{"op":"remove","path":"/foo/1"}]`, `{"foo":["bar","baz"]}`},
+		{`{"baz":"qux","foo":"bar"}`, `[{"op":"replace","path":"/baz","value":"boo"}]`, `{"baz":"boo","foo":"bar"}`},
+		{`{"foo":{"bar":"baz","waldo":"fred"},"qux":{"corge":"grault"}}`, `[{"op":"move","from":"/foo/waldo","path":"/qux/thud"}]`,
+			`{"foo":{"bar":"baz"},"qux":{"corge":"grault","thud":"fred"}}`},
+		{`{"foo":["all","grass","cows","eat"]}`, `[{"op":"move","from":"/foo/1","path":"/foo/3"}]`, `{"foo":["all","cows","eat","grass"]}`},
+		{`{"baz":"qux","foo":["a",2,"c"]}`, `[{"op":"test","path":"/baz","value":"qux"},{"op":"test","path":"/foo/1","value":2.0}]`,
+			`{"baz":"qux","foo":["a",2,"c"]}`},
+		{`{"baz":"qux"}`, `[{"op":"test","path":"/baz","value":"bar"}]`, ``},
+		{`{"foo":"bar"}`, `[{"op":"add","path":"/child","value":{"grandchild":{}}}]`, `{"child":{"grandchild":{}},"foo":"bar"}`},
+		{`{"foo":"bar"}`, `[{"op":"add","path":"/baz/bat","value":"qux"}]`, ``},
+		{`{"/":9,"~1":10}`, `[{"op":"test","path":"/~01","value":10}]`, `{"/":9,"~1":10}`},
+		{`{"foo":["bar"]}`, `[{"op":"add","path":"/foo/-","value":["abc","def"]}]`, `{"foo":["bar",["abc","def"]]}`},
+		{`{"foo":"bar"}`, `[{"op":"copy","from":"/foo","path":"/baz"},{"op":"replace","path":"","value":[1]}]`, `[1]`},
+		{`{"foo":"bar"}`, `[{"op":"replace","path":"/baz","value":1}]`, ``},
+		{`{"foo":{"bar":1}}`, `[{"op":"move","from":"/foo","path":"/foo/bar"}]`, ``},
+		{`{"n":12345678901234567890}`, `[{"op":"copy","from":"/n","path":"/m"}]`, `{"m":12345678901234567890,"n":12345678901234567890}`},
+	}
+	for i, tt := range tests {
+		t.Run(fmt.Sprintf("%d", i), func(t *testing.T) {
+			var p Patch
+			if err := json.Unmarshal([]byte(tt.patch), &p); err != nil {
+				t.Fatal(err)
+			}
+			// Act
+			b, err := p.ApplyJson([]byte(tt.document))
+			if (err != nil) != (tt.want == "") {
+				t.Fatalf("got error %v, want error %v", err, tt.want == "")
+			}
+			if string(b) != tt.want {
+				t.Errorf("got %s, want %s", b, tt.want)
+			}
+		})
+	}
+}
+
+func TestPatch_Apply(t *testing.T) {
+	tests := []struct {
+		patch Patch
+		ptr   jsonpointer.Ptr
+		want  string // the JSON encoding of the value at ptr after the patch, or empty if it is removed
+		err   bool
+	}{
+		{Patch{{Op: ReplaceOp, Path: "/servers", Value: []map[string]string{{"url": "https://staging.example.com/v3"}}}},
+			"/servers", `[{"url":"https://staging.example.com/v3"}]`, false},
+		{Patch{{Op: AddOp, Path: "/security", Value: []map[string][]string{{"api_key": {}}}}},
+			"/security", `[{"api_key":[]}]`, false},
+		{Patch{{Op: RemoveOp, Path: "/paths/~1pet~1findByTags"}}, "/paths/~1pet~1findByTags", ``, false},
+		{Patch{{Op: CopyOp, From: "/info/title", Path: "/info/description"}}, "/info/description", `"Swagger Petstore - OpenAPI 3.0"`, false},
+		{Patch{{Op: AddOp, Path: "/info/x-audience", Value: "internal"}}, "/info/x-audience", `"internal"`, false},
+		{Patch{
+			{Op: ReplaceOp, Path: "/info/title", Value: "Pets"},
+			{Op: TestOp, Path: "/info/version", Value: "0.0.0"},
+		}, "/info/title", `"Swagger Petstore - OpenAPI 3.0"`, true},
+	}
+	for i, tt := range tests {
+		t.Run(fmt.Sprintf("%d", i), func(t *testing.T) {
+			doc, err := oas.New("../v3/testdata/petstore.json")
+			if err != nil {
+				t.Fatal(err)
+			}
+			// Act
+			err = tt.patch.Apply(doc)
+			if (err != nil) != tt.err {
+				t.Fatalf("got error %v, want error %v", err, tt.err)
+			}
+			v, err := tt.ptr.Access(doc)
+			if tt.want == "" {
+				if err == nil {
+					t.Errorf("got %s at %s, want removed", marshal(v.Interface()), tt.ptr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := marshal(v.Interface()); got != tt.want {
+				t.Errorf("got %s at %s, want %s", got, tt.ptr, tt.want)
+			}
+			if err := doc.Validate(); err != nil {
+				t.Error(err)
+			}
+		})
+	}
+}
+
+func TestDiff(t *testing.T) {
+	tests := []struct {
+		a, b string
+		want string
+	}{
+		{`{"a":1,"b":[1,2,3],"c":{"d":"e"}}`, `{"a":1.0,"b":[1,4],"c":{"f":null},"g":true}`,
+			`[{"op":"replace","path":"/b/1","value":4},{"op":"remove","path":"/b/2"},` +
+				`{"op":"remove","path":"/c/d"},{"op":"add","path":"/c/f","value":null},{"op":"add","path":"/g","value":true}]`},
+		{`{"a/b":[]}`, `{"a/b":[{}]}`, `[{"op":"add","path":"/a~1b/0","value":{}}]`},
+		{`{"a":1}`, `[1]`, `[{"op":"replace","path":"","value":[1]}]`},
+	}
+	for i, tt := range tests {
+		t.Run(fmt.Sprintf("%d", i), func(t *testing.T) {
+			var a, b any
+			if err := json.Unmarshal([]byte(tt.a), &a); err != nil {
+				t.Fatal(err)
+			}
+			if err := json.Unmarshal([]byte(tt.b), &b); err != nil {
+				t.Fatal(err)
+			}
+			// Act
+			p, err := Diff(a, b)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := marshal(p); got != tt.want {
+				t.Errorf("got %s, want %s", got, tt.want)
+			}
+			patched, err := p.ApplyJson([]byte(tt.a))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if want := marshal(b); string(patched) != want {
+				t.Errorf("got patched %s, want %s", patched, want)
+			}
+		})
+	}
+}
+
+func TestMergeJson(t *testing.T) {
+	// the examples of RFC 7386 appendix A
+	tests := []struct {
+		document string
+		patch    string
+		want     string
+	}{
+		{`{"a":"b"}`, `{"a":"c"}`, `{"a":"c"}`},
+		{`{"a":"b"}`, `{"b":"c"}`, `{"a":"b","b":"c"}`},
+		{`{"a":"b"}`, `{"a":null}`, `{}`},
+		{`{"a":"b","b":"c"}`, `{"a":null}`, `{"b":"c"}`},
+		{`{"a":["b"]}`, `{"a":"c"}`, `{"a":"c"}`},
+		{`{"a":"c"}`, `{"a":["b"]}`, `{"a":["b"]}`},
+		{`{"a":{"b":"c"}}`, `{"a":{"b":"d","c":null}}`, `{"a":{"b":"d"}}`},
+		{`{"a":[{"b":"c"}]}`, `{"a":[1]}`, `{"a":[1]}`},
+		{`["a","b"]`, `["c","d"]`, `["c","d"]`},
+		{`{"a":"b"}`, `["c"]`, `["c"]`},
+		{`{"a":"foo"}`, `null`, `null`},
+		{`{"a":"foo"}`, `"bar"`, `"bar"`},
+		{`{"e":null}`, `{"a":1}`, `{"a":1,"e":null}`},
+		{`[1,2]`, `{"a":"b","c":null}`, `{"a":"b"}`},
+		{`{}`, `{"a":{"bb":{"ccc":null}}}`, `{"a":{"bb":{}}}`},
+	}
+	for i, tt := range tests {
+		t.Run(fmt.Sprintf("%d", i), func(t *testing.T) {
+			// Act
+			b, err := MergeJson([]byte(tt.document), []byte(tt.patch))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if string(b) != tt.want {
+				t.Errorf("got %s, want %s", b, tt.want)
+			}
+		})
+	}
+}
+
+func TestMergeDiff(t *testing.T) {
+	tests := []struct {
+		a, b string
+		want string
+	}{
+		{`{"a":"b","c":{"d":"e","f":"g"},"h":[1]}`, `{"a":"z","c":{"d":"e"},"h":[1]}`, `{"a":"z","c":{"f":null}}`},
+		{`{"a":[1,2]}`, `{"a":[1,3],"b":{"c":1}}`, `{"a":[1,3],"b":{"c":1}}`},
+		{`{"a":1}`, `{"a":1}`, `{}`},
+	}
+	for i, tt := range tests {
+		t.Run(fmt.Sprintf("%d", i), func(t *testing.T) {
+			var a, b any
+			if err := json.Unmarshal([]byte(tt.a), &a); err != nil {
+				t.Fatal(err)
+			}
+			if err := json.Unmarshal([]byte(tt.b), &b); err != nil {
+				t.Fatal(err)
+			}
+			// Act
+			patch, err := MergeDiff(a, b)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if string(patch) != tt.want {
+				t.Errorf("got %s, want %s", patch, tt.want)
+			}
+			patched, err := MergeJson([]byte(tt.a), patch)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if want := marshal(b); string(patched) != want {
+				t.Errorf("got patched %s, want %s", patched, want)
+			}
+		})
+	}
+}
@@ -0,0 +1,100 @@
+package jsonpatch
+
+import (
+	"encoding/json"
+	"maps"
+	"slices"
+)
+
+// Merge applies the JSON Merge Patch patch to the value v points to, through its JSON encoding. See Patch.Apply for how
+// v is decoded.
+func Merge(v any, patch []byte) error {
+	document, err := decode(v)
+	if err != nil {
+		return err
+	}
+	p, err := unmarshal(patch)
+	if err != nil {
+		return err
+	}
+	return encode(merge(document, p), v)
+}
+
+// MergeJson applies the JSON Merge Patch patch to the JSON document b and returns the patched document.
+func MergeJson(b []byte, patch []byte) ([]byte, error) {
+	document, err := unmarshal(b)
+	if err != nil {
+		return nil, err
+	}
+	p, err := unmarshal(patch)
+	if err != nil {
+		return nil, err
+	}
+	return json.Marshal(merge(document, p))
+}
+
+// merge returns target patched by patch as the MergePatch function of RFC 7386.
+func merge(target, patch any) any {
+	p, ok := patch.(map[string]any)
+	if !ok {
+		return patch
+	}
+	t, ok := target.(map[string]any)
+	if !ok {
+		t = make(map[string]any)
+	}
+	for k, v := range p {
+		if v == nil {
+			delete(t, k)
+		} else {
+			t[k] = merge(t[k], v)
+		}
+	}
+	return t
+}
+
+// MergeDiff returns a JSON Merge Patch which changes the JSON encoding of a to that of b.
+//
+// A merge patch cannot set a member to null, as null removes the member, nor change part of an array. A member of b
+// which is null is therefore removed, and an array which differs is replaced as a whole.
+func MergeDiff(a, b any) ([]byte, error) {
+	x, err := decode(a)
+	if err != nil {
+		return nil, err
+	}
+	y, err := decode(b)
+	if err != nil {
+		return nil, err
+	}
+	patch, _ := mergeDiff(x, y)
+	return json.Marshal(patch)
+}
+
+// mergeDiff returns the merge patch from a to b, and whether they differ.
+func mergeDiff(a, b any) (any, bool) {
+	x, okX := a.(map[string]any)
+	y, okY := b.(map[string]any)
+	if !okX || !okY {
+		return b, !equal(a, b)
+	}
+	patch := make(map[string]any)
+	for _, k := range slices.Sorted(maps.Keys(x)) {
+		if v, ok := y[k]; !ok || v == nil {
+			if x[k] != nil {
+				patch[k] = nil
+			}
+		}
+	}
+	for _, k := range slices.Sorted(maps.Keys(y)) {
+		if y[k] == nil {
+			continue
+		}
+		v, ok := x[k]
+		if !ok {
+			patch[k] = y[k]
+		} else if d, ok := mergeDiff(v, y[k]); ok {
+			patch[k] = d
+		}
+	}
+	return patch, len(patch) > 0
+}
@@ -28,12 +28,58 @@ var (
 type (
 	Ptr         string
 	UriFragment string
-	token       struct {
+	// token is a reference token, which refers to a member of an object by key, or to an element of an array by idx if
+	// it is an array index, or else idx is -1.
+	token struct {
 		key string
 		idx int
 	}
 )
 
+// New returns the JSON Pointer made of the unescaped reference tokens, e.g. New("paths", "/pet", "put") is
+// "/paths/~1pet/put".
+func New(tokens ...string) Ptr {
+	return Ptr("").Append(tokens...)
+}
+
+// Append returns p followed by the unescaped reference tokens.
+func (p Ptr) Append(tokens ...string) Ptr {
+	b := strings.Builder{}
+	b.WriteString(string(p))
+	for _, t := range tokens {
+		b.WriteString(ptrSep)
+		b.WriteString(escaper.Replace(t))
+	}
+	return Ptr(b.String())
+}
+
+// Tokens returns the unescaped reference tokens of p, or an error if p is not a JSON Pointer.
+func (p Ptr) Tokens() ([]string, error) {
+	tokens, err := p.tokens()
+	if err != nil {
+		return nil, err
+	}
+	keys := make([]string, len(tokens))
+	for i, t := range tokens {
+		keys[i] = t.key
+	}
+	return keys, nil
+}
+
+// Parent returns the JSON Pointer to the object or array containing the value p refers to, or p if it refers to the
+// whole document.
+func (p Ptr) Parent() Ptr {
+	if i := strings.LastIndex(string(p), ptrSep); i != -1 {
+		return p[:i]
+	}
+	return p
+}
+
+// String returns p, whose reference tokens are escaped.
+func (p Ptr) String() string {
+	return string(p)
+}
+
 type AccessError struct {
 	used      []token
 	remaining []token
@@ -57,11 +103,13 @@ func (p Ptr) tokens() (tokens []token, err error) {
 	}
 	for _, part := range parts[1:] {
 		part = unEscaper.Replace(part)
-		if idx, err := strconv.Atoi(part); err == nil {
-			tokens = append(tokens, token{"", idx})
-		} else {
-			tokens = append(tokens, token{part, -1})
+		idx := -1
+		if arrayIndexRe.MatchString(part) {
+			if i, err := strconv.Atoi(part); err == nil {
+				idx = i
+			}
 		}
+		tokens = append(tokens, token{part, idx})
 	}
 	return
 }
@@ -87,26 +135,26 @@ func (p Ptr) Access(document any) (v reflect.Value, err error) {
 					return reflect.Value{}, &AccessError{tokens[:i], tokens[i:], v, "expect map"}
 				}
 			case reflect.Map:
-				if t.idx == -1 {
-					u := v.MapIndex(reflect.ValueOf(t.key))
-					if u.IsValid() {
-						v = u
-						goto next
-					}
-					return reflect.Value{}, &AccessError{tokens[:i], tokens[i:], v, "key out of range"}
-				} else {
-					return reflect.Value{}, &AccessError{tokens[:i], tokens[i:], v, "expect array got object"}
+				if v.Type().Key().Kind() != reflect.String {
+					return reflect.Value{}, &AccessError{tokens[:i], tokens[i:], v, fmt.Sprintf("expect JSON got %v", v.Type())}
+				}
+				u := v.MapIndex(reflect.ValueOf(t.key).Convert(v.Type().Key()))
+				if u.IsValid() {
+					v = u
+					goto next
 				}
+				return reflect.Value{}, &AccessError{tokens[:i], tokens[i:], v, "key out of range"}
 			case reflect.Struct:
-				if t.idx == -1 {
-					if i := fieldByJsonTag(v.Type(), t.key); i != -1 {
-						v = v.Field(i)
-						goto next
-					}
-					return reflect.Value{}, &AccessError{tokens[:i], tokens[i:], v, "key out of range"}
-				} else {
-					return reflect.Value{}, &AccessError{tokens[:i], tokens[i:], v, "expect array got object"}
+				if index := fieldByJsonTag(v.Type(), t.key); index != nil {
+					v = v.FieldByIndex(index)
+					goto next
 				}
+				if index := inlined(v, t.key); index != nil {
+					// the member is found in the field with the same token
+					v = v.FieldByIndex(index)
+					continue
+				}
+				return reflect.Value{}, &AccessError{tokens[:i], tokens[i:], v, "key out of range"}
 			case reflect.Interface, reflect.Ptr:
 				v = v.Elem()
 			default:
@@ -118,22 +166,75 @@ func (p Ptr) Access(document any) (v reflect.Value, err error) {
 	return v, nil
 }
 
-func fieldByJsonTag(t reflect.Type, key string) int {
+// fieldByJsonTag returns the index sequence of the field of t encoded by encoding/json as key, including fields
+// promoted from embedded structs, or nil if there is none.
+func fieldByJsonTag(t reflect.Type, key string) []int {
+	var embedded []int
 	for i := 0; i < t.NumField(); i++ {
 		f := t.Field(i)
 		tag := f.Tag.Get("json")
 		if tag == "-" {
 			continue
 		}
-		name, _ := parseTag(tag)
+		name, opts := parseTag(tag)
+		if name == "" && opts.Contains("inline") {
+			continue // see inlined
+		}
+		if f.Anonymous && name == "" && f.Type.Kind() == reflect.Struct {
+			embedded = append(embedded, i)
+			continue
+		}
 		if !isValidTag(name) {
 			name = f.Name
 		}
 		if name == key {
-			return i
+			return []int{i}
+		}
+	}
+	// fields of embedded structs are shadowed by the fields of t
+	for _, i := range embedded {
+		if index := fieldByJsonTag(t.Field(i).Type, key); index != nil {
+			return append([]int{i}, index...)
+		}
+	}
+	return nil
+}
+
+// extensionPrefix is the prefix of the keys of specification extensions, see inlined.
+const extensionPrefix = "x-"
+
+// inlined returns the index sequence of the field of the struct v holding the member key of v, if key is not a field
+// of v itself, or nil if there is none. That is a field inlined with the ",inline" option, such as those of ser.Or,
+// holding a struct with the member or a map, or, for a key prefixed by "x-", a map of specification extensions named
+// Extensions, which encoding/json omits but ser.MarshalExtended inlines.
+func inlined(v reflect.Value, key string) []int {
+	t := v.Type()
+	if strings.HasPrefix(key, extensionPrefix) {
+		if f, ok := t.FieldByName("Extensions"); ok && f.Type.Kind() == reflect.Map && f.Type.Key().Kind() == reflect.String {
+			return f.Index
+		}
+	}
+	for i := 0; i < t.NumField(); i++ {
+		name, opts := parseTag(t.Field(i).Tag.Get("json"))
+		if name != "" || !opts.Contains("inline") {
+			continue
+		}
+		f := v.Field(i)
+		for (f.Kind() == reflect.Interface || f.Kind() == reflect.Ptr) && !f.IsNil() {
+			f = f.Elem()
+		}
+		switch f.Kind() {
+		case reflect.Map:
+			if f.Type().Key().Kind() == reflect.String {
+				return []int{i}
+			}
+		case reflect.Struct:
+			if fieldByJsonTag(f.Type(), key) != nil || inlined(f, key) != nil {
+				return []int{i}
+			}
 		}
 	}
-	return -1
+	return nil
 }
 
 func (uri UriFragment) Access(document any) (v reflect.Value, err error) {
@@ -3,6 +3,7 @@ package jsonpointer
 import (
 	"encoding/json"
 	"fmt"
+	"slices"
 	"testing"
 )
 
@@ -112,3 +113,336 @@ func TestUriFragment_Access(t *testing.T) {
 		})
 	}
 }
+
+type identifiers struct {
+	Id   string         `json:"$id,omitempty"`
+	Defs map[string]int `json:"$defs,omitempty"`
+}
+
+type schema struct {
+	identifiers
+	Type       string                 `json:"type,omitempty"`
+	Id         string                 `json:"id,omitempty"`
+	Items      *boolOrSchema          `json:"items,omitempty"`
+	Extensions map[string]interface{} `json:"-"`
+}
+
+// boolOrSchema is inlined as ser.Or.
+type boolOrSchema struct {
+	X bool    `json:",inline"`
+	Y *schema `json:",inline"`
+}
+
+func TestPtr_Access_struct(t *testing.T) {
+	doc := &schema{
+		identifiers: identifiers{Id: "https://example.com/schema", Defs: map[string]int{"a": 1}},
+		Type:        "object",
+		Id:          "shadowed",
+		Items:       &boolOrSchema{Y: &schema{Type: "string", Extensions: map[string]interface{}{"x-go-type": "Name"}}},
+		Extensions:  map[string]interface{}{"x-order": 1},
+	}
+
+	var testCases = []struct {
+		pointer Ptr
+		want    interface{}
+		err     bool
+	}{
+		{"/type", "object", false},
+		{"/$id", "https://example.com/schema", false},
+		{"/$defs/a", 1, false},
+		{"/id", "shadowed", false},
+		{"/Type", nil, true},
+		{"/items/type", "string", false},
+		{"/items/x-go-type", "Name", false},
+		{"/items/Y", nil, true},
+		{"/x-order", 1, false},
+		{"/x-unknown", nil, true},
+		{"/Extensions", nil, true},
+	}
+	for i, tt := range testCases {
+		t.Run(fmt.Sprintf("%d", i), func(t *testing.T) {
+			// Act
+			v, err := tt.pointer.Access(doc)
+
+			if (err != nil) != tt.err {
+				t.Fatalf("got error %v, want error %v", err, tt.err)
+			}
+			if err == nil && fmt.Sprint(v) != fmt.Sprint(tt.want) {
+				t.Errorf("got %v, want %v", v, tt.want)
+			}
+		})
+	}
+}
+
+func TestPtr_Set(t *testing.T) {
+	const data = `{"foo":["bar","baz"],"obj":{"a":1}}`
+	var testCases = []struct {
+		op      string
+		pointer Ptr
+		value   interface{}
+		want    string
+		err     bool
+	}{
+		{"set", "/obj/a", 2, `{"foo":["bar","baz"],"obj":{"a":2}}`, false},
+		{"set", "/obj/b", 2, `{"foo":["bar","baz"],"obj":{"a":1,"b":2}}`, false},
+		{"set", "/foo/1", "qux", `{"foo":["bar","qux"],"obj":{"a":1}}`, false},
+		{"set", "/foo/2", "qux", "", true},
+		{"set", "/foo/-", "qux", "", true},
+		{"set", "", []int{1}, `[1]`, false},
+		{"add", "/foo/1", "qux", `{"foo":["bar","qux","baz"],"obj":{"a":1}}`, false},
+		{"add", "/foo/-", "qux", `{"foo":["bar","baz","qux"],"obj":{"a":1}}`, false},
+		{"add", "/foo/2", "qux", `{"foo":["bar","baz","qux"],"obj":{"a":1}}`, false},
+		{"add", "/foo/3", "qux", "", true},
+		{"add", "/obj/b/c", 1, "", true},
+		{"remove", "/foo/0", nil, `{"foo":["baz"],"obj":{"a":1}}`, false},
+		{"remove", "/obj/a", nil, `{"foo":["bar","baz"],"obj":{}}`, false},
+		{"remove", "/obj/b", nil, "", true},
+		{"remove", "", nil, "", true},
+	}
+	for i, tt := range testCases {
+		t.Run(fmt.Sprintf("%d", i), func(t *testing.T) {
+			var doc interface{}
+			if err := json.Unmarshal([]byte(data), &doc); err != nil {
+				t.Fatal(err)
+			}
+			// Act
+			var err error
+			switch tt.op {
+			case "set":
+				err = tt.pointer.Set(&doc, tt.value)
+			case "add":
+				err = tt.pointer.Add(&doc, tt.value)
+			case "remove":
+				err = tt.pointer.Remove(&doc)
+			}
+
+			if (err != nil) != tt.err {
+				t.Fatalf("got error %v, want error %v", err, tt.err)
+			}
+			if err != nil {
+				return
+			}
+			b, err := json.Marshal(doc)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if string(b) != tt.want {
+				t.Errorf("got %s, want %s", b, tt.want)
+			}
+		})
+	}
+}
+
+type document struct {
+	Schemas map[string]schema `json:"schemas,omitempty"`
+	Tags    []string          `json:"tags,omitempty"`
+}
+
+func TestPtr_Set_struct(t *testing.T) {
+	var testCases = []struct {
+		op      string
+		pointer Ptr
+		value   interface{}
+		want    string
+	}{
+		{"set", "/schemas/pet/type", "string", `{"schemas":{"pet":{"$id":"pet","type":"string"}},"tags":["a"]}`},
+		{"set", "/schemas/pet/$defs/a", 1, `{"schemas":{"pet":{"$id":"pet","$defs":{"a":1},"type":"object"}},"tags":["a"]}`},
+		{"set", "/schemas/tag", map[string]interface{}{"type": "string"}, `{"schemas":{"pet":{"$id":"pet","type":"object"},"tag":{"type":"string"}},"tags":["a"]}`},
+		{"add", "/tags/0", "b", `{"schemas":{"pet":{"$id":"pet","type":"object"}},"tags":["b","a"]}`},
+		{"remove", "/schemas/pet/$id", nil, `{"schemas":{"pet":{"type":"object"}},"tags":["a"]}`},
+		{"remove", "/tags/0", nil, `{"schemas":{"pet":{"$id":"pet","type":"object"}}}`},
+	}
+	for i, tt := range testCases {
+		t.Run(fmt.Sprintf("%d", i), func(t *testing.T) {
+			doc := &document{
+				Schemas: map[string]schema{"pet": {identifiers: identifiers{Id: "pet"}, Type: "object"}},
+				Tags:    []string{"a"},
+			}
+			// Act
+			var err error
+			switch tt.op {
+			case "set":
+				err = tt.pointer.Set(doc, tt.value)
+			case "add":
+				err = tt.pointer.Add(doc, tt.value)
+			case "remove":
+				err = tt.pointer.Remove(doc)
+			}
+
+			if err != nil {
+				t.Fatal(err)
+			}
+			b, err := json.Marshal(doc)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if string(b) != tt.want {
+				t.Errorf("got %s, want %s", b, tt.want)
+			}
+		})
+	}
+}
+
+func TestRelativePtr_Access(t *testing.T) {
+	// the examples of draft-bhutton-relative-json-pointer-00 section 5.1
+	var doc interface{}
+	if err := json.Unmarshal([]byte(`{"foo":["bar","baz"],"highly":{"nested":{"objects":true}}}`), &doc); err != nil {
+		t.Fatal(err)
+	}
+
+	var testCases = []struct {
+		from    Ptr
+		pointer RelativePtr
+		want    interface{}
+		err     bool
+	}{
+		{"/foo/1", "0", "baz", false},
+		{"/foo/1", "1/0", "bar", false},
+		{"/foo/1", "0-1", "bar", false},
+		{"/foo/1", "2/highly/nested/objects", true, false},
+		{"/foo/1", "0#", 1, false},
+		{"/foo/1", "0-1#", 0, false},
+		{"/foo/1", "1#", "foo", false},
+		{"/highly/nested", "0/objects", true, false},
+		{"/highly/nested", "1/nested/objects", true, false},
+		{"/highly/nested", "2/foo/0", "bar", false},
+		{"/highly/nested", "0#", "nested", false},
+		{"/highly/nested", "1#", "highly", false},
+		{"/foo/1", "0+1", nil, true},
+		{"/foo/1", "0-2", nil, true},
+		{"/highly/nested", "0+1", nil, true},
+		{"/highly/nested", "2#", nil, true},
+		{"/highly/nested", "3", nil, true},
+		{"/highly/nested", "01", nil, true},
+	}
+	for i, tt := range testCases {
+		t.Run(fmt.Sprintf("%d", i), func(t *testing.T) {
+			// Act
+			v, err := tt.pointer.Access(doc, tt.from)
+
+			if (err != nil) != tt.err {
+				t.Fatalf("got error %v, want error %v", err, tt.err)
+			}
+			if err == nil && fmt.Sprint(v) != fmt.Sprint(tt.want) {
+				t.Errorf("got %v, want %v", v, tt.want)
+			}
+		})
+	}
+}
+
+func TestRelativePtr_Resolve(t *testing.T) {
+	var testCases = []struct {
+		from    Ptr
+		pointer RelativePtr
+		want    Ptr
+		err     bool
+	}{
+		{"/foo/1", "0", "/foo/1", false},
+		{"/foo/1", "2/a~1b", "/a~1b", false},
+		{"/foo/1", "0+3/x", "/foo/4/x", false},
+		{"/foo/1", "2", "", false},
+		{"/foo/1", "3", "", true},
+		{"/foo/bar", "0+1", "", true},
+		{"/foo/1", "0#", "", true},
+	}
+	for i, tt := range testCases {
+		t.Run(fmt.Sprintf("%d", i), func(t *testing.T) {
+			// Act
+			got, err := tt.pointer.Resolve(tt.from)
+
+			if (err != nil) != tt.err {
+				t.Fatalf("got error %v, want error %v", err, tt.err)
+			}
+			if got != tt.want {
+				t.Errorf("got %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestNew(t *testing.T) {
+	var testCases = []struct {
+		tokens []string
+		want   Ptr
+	}{
+		{nil, ""},
+		{[]string{""}, "/"},
+		{[]string{"paths", "/pet", "put"}, "/paths/~1pet/put"},
+		{[]string{"m~n", "~1"}, "/m~0n/~01"},
+		{[]string{"responses", "200"}, "/responses/200"},
+	}
+	for i, tt := range testCases {
+		t.Run(fmt.Sprintf("%d", i), func(t *testing.T) {
+			// Act
+			p := New(tt.tokens...)
+
+			if p != tt.want {
+				t.Errorf("got %q, want %q", p, tt.want)
+			}
+			tokens, err := p.Tokens()
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !slices.Equal(tokens, tt.tokens) {
+				t.Errorf("got tokens %q, want %q", tokens, tt.tokens)
+			}
+		})
+	}
+}
+
+func TestPtr_Parent(t *testing.T) {
+	var testCases = []struct {
+		pointer Ptr
+		want    Ptr
+	}{
+		{"", ""},
+		{"/", ""},
+		{"/paths", ""},
+		{"/paths/~1pet/put", "/paths/~1pet"},
+		{New("a", "b/c").Append("d"), "/a/b~1c"},
+	}
+	for i, tt := range testCases {
+		t.Run(fmt.Sprintf("%d", i), func(t *testing.T) {
+			// Act
+			p := tt.pointer.Parent()
+
+			if p != tt.want {
+				t.Errorf("got %q, want %q", p, tt.want)
+			}
+		})
+	}
+}
+
+type statusCode string
+
+func TestPtr_Access_numeric(t *testing.T) {
+	doc := map[string]interface{}{
+		"responses": map[statusCode]string{"200": "ok", "01": "zero one"},
+		"items":     []string{"a", "b"},
+	}
+
+	var testCases = []struct {
+		pointer Ptr
+		want    interface{}
+		err     bool
+	}{
+		{"/responses/200", "ok", false},
+		{"/responses/01", "zero one", false},
+		{"/items/1", "b", false},
+		{"/items/01", nil, true},
+		{"/items/-", nil, true},
+	}
+	for i, tt := range testCases {
+		t.Run(fmt.Sprintf("%d", i), func(t *testing.T) {
+			// Act
+			v, err := tt.pointer.Access(doc)
+
+			if (err != nil) != tt.err {
+				t.Fatalf("got error %v, want error %v", err, tt.err)
+			}
+			if err == nil && fmt.Sprint(v) != fmt.Sprint(tt.want) {
+				t.Errorf("got %v, want %v", v, tt.want)
+			}
+		})
+	}
+}
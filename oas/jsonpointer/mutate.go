@@ -0,0 +1,226 @@
+package jsonpointer
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+)
+
+type operation int8
+
+const (
+	setOperation operation = iota + 1
+	addOperation
+	removeOperation
+)
+
+// Set replaces the value at p in document with value. A member is added to an object which does not have it, but an
+// array element must exist. A struct is navigated by the JSON encoding of its fields.
+//
+// Values which cannot be modified in place, such as map values or the document itself, are written back to their
+// parent, so document must be a pointer for its root to be replaced. A value which cannot be assigned, such as a
+// map[string]any for a struct, is converted through its JSON encoding.
+func (p Ptr) Set(document any, value any) error {
+	return p.mutate(document, setOperation, value)
+}
+
+// Add adds value at p in document as the "add" operation of JSON Patch: a member of an object is added or replaced, and
+// an element is inserted in an array at its index, or appended if the index is "-" or the length of the array. Setting
+// a field of a struct replaces it. See Set for how document is modified.
+func (p Ptr) Add(document any, value any) error {
+	return p.mutate(document, addOperation, value)
+}
+
+// Remove removes the value at p from document, which must exist. A member is deleted from an object and an element
+// from an array, shifting the elements after it, while a field of a struct is set to its zero value. See Set for how
+// document is modified.
+func (p Ptr) Remove(document any) error {
+	return p.mutate(document, removeOperation, nil)
+}
+
+func (p Ptr) mutate(document any, op operation, value any) error {
+	tokens, err := p.tokens()
+	if err != nil {
+		return err
+	}
+	v := reflect.ValueOf(document)
+	if len(tokens) > 0 {
+		m := &mutator{tokens, op, value}
+		return m.mutate(v, 0, func(reflect.Value) error {
+			return errors.New("jsonpointer.Ptr: cannot replace a document which is not a pointer")
+		})
+	}
+	if op == removeOperation {
+		return errors.New("jsonpointer.Ptr: cannot remove the document")
+	}
+	if v.Kind() != reflect.Ptr || v.IsNil() {
+		return errors.New("jsonpointer.Ptr: cannot replace a document which is not a pointer")
+	}
+	x, err := assignable(value, v.Elem().Type())
+	if err != nil {
+		return err
+	}
+	v.Elem().Set(x)
+	return nil
+}
+
+type mutator struct {
+	tokens []token
+	op     operation
+	value  any
+}
+
+// mutate applies the operation to the value at the remaining tokens of v, located at the first i tokens. The value
+// replacing v, if it cannot be modified in place, is passed to set.
+func (m *mutator) mutate(v reflect.Value, i int, set func(reflect.Value) error) error {
+	if !v.IsValid() {
+		return m.error(i, v, "expect JSON got nil")
+	}
+	for v.Kind() == reflect.Interface || v.Kind() == reflect.Ptr {
+		if v.IsNil() {
+			return m.error(i, v, "expect JSON got nil")
+		}
+		if v.Kind() == reflect.Ptr {
+			e := v.Elem()
+			set = func(x reflect.Value) error {
+				e.Set(x)
+				return nil
+			}
+		}
+		v = v.Elem()
+	}
+	t, last := m.tokens[i], i == len(m.tokens)-1
+	switch v.Kind() {
+	case reflect.Map:
+		if v.Type().Key().Kind() != reflect.String {
+			return m.error(i, v, fmt.Sprintf("expect JSON got %v", v.Type()))
+		}
+		key := reflect.ValueOf(t.key).Convert(v.Type().Key())
+		u := v.MapIndex(key)
+		if !u.IsValid() && (!last || m.op == removeOperation) {
+			return m.error(i, v, "key out of range")
+		}
+		switch {
+		case !last:
+			// map values cannot be modified in place
+			return m.mutate(u, i+1, func(x reflect.Value) error {
+				v.SetMapIndex(key, x)
+				return nil
+			})
+		case m.op == removeOperation:
+			v.SetMapIndex(key, reflect.Value{})
+			return nil
+		}
+		x, err := assignable(m.value, v.Type().Elem())
+		if err != nil {
+			return err
+		}
+		if v.IsNil() {
+			v = reflect.MakeMap(v.Type())
+			v.SetMapIndex(key, x)
+			return set(v)
+		}
+		v.SetMapIndex(key, x)
+		return nil
+	case reflect.Slice:
+		n := v.Len()
+		if t.idx == -1 && !(t.key == "-" && last && m.op == addOperation) {
+			return m.error(i, v, "expect object got array")
+		}
+		idx := t.idx
+		if t.idx == -1 {
+			idx = n // "-"
+		}
+		if idx > n || idx == n && (!last || m.op != addOperation) {
+			return m.error(i, v, "index out of range")
+		}
+		if !last {
+			e := v.Index(idx)
+			return m.mutate(e, i+1, func(x reflect.Value) error {
+				e.Set(x)
+				return nil
+			})
+		}
+		switch m.op {
+		case addOperation:
+			x, err := assignable(m.value, v.Type().Elem())
+			if err != nil {
+				return err
+			}
+			s := reflect.MakeSlice(v.Type(), 0, n+1)
+			s = reflect.Append(reflect.AppendSlice(s, v.Slice(0, idx)), x)
+			return set(reflect.AppendSlice(s, v.Slice(idx, n)))
+		case removeOperation:
+			s := reflect.MakeSlice(v.Type(), 0, n-1)
+			return set(reflect.AppendSlice(reflect.AppendSlice(s, v.Slice(0, idx)), v.Slice(idx+1, n)))
+		}
+		x, err := assignable(m.value, v.Type().Elem())
+		if err != nil {
+			return err
+		}
+		v.Index(idx).Set(x)
+		return nil
+	case reflect.Struct:
+		index := fieldByJsonTag(v.Type(), t.key)
+		inline := index == nil
+		if inline {
+			index = inlined(v, t.key)
+		}
+		if index == nil {
+			return m.error(i, v, "key out of range")
+		}
+		setField := func(x reflect.Value) error {
+			if v.CanSet() {
+				v.FieldByIndex(index).Set(x)
+				return nil
+			}
+			// a struct which cannot be modified in place is replaced by a copy
+			c := reflect.New(v.Type()).Elem()
+			c.Set(v)
+			c.FieldByIndex(index).Set(x)
+			return set(c)
+		}
+		f := v.FieldByIndex(index)
+		switch {
+		case inline:
+			// the member is found in the field with the same token
+			return m.mutate(f, i, setField)
+		case !last:
+			return m.mutate(f, i+1, setField)
+		case m.op == removeOperation:
+			return setField(reflect.Zero(f.Type()))
+		}
+		x, err := assignable(m.value, f.Type())
+		if err != nil {
+			return err
+		}
+		return setField(x)
+	default:
+		return m.error(i, v, fmt.Sprintf("expect JSON got %v", v.Type()))
+	}
+}
+
+func (m *mutator) error(i int, v reflect.Value, msg string) error {
+	return &AccessError{m.tokens[:i], m.tokens[i:], v, msg}
+}
+
+// assignable returns value as a reflect.Value assignable to typ, converting it through its JSON encoding if needed.
+func assignable(value any, typ reflect.Type) (reflect.Value, error) {
+	if value == nil {
+		return reflect.Zero(typ), nil
+	}
+	x := reflect.ValueOf(value)
+	if x.Type().AssignableTo(typ) {
+		return x, nil
+	}
+	b, err := json.Marshal(value)
+	if err != nil {
+		return reflect.Value{}, err
+	}
+	ptr := reflect.New(typ)
+	if err := json.Unmarshal(b, ptr.Interface()); err != nil {
+		return reflect.Value{}, fmt.Errorf("jsonpointer.Ptr: cannot assign %T to %v: %w", value, typ, err)
+	}
+	return ptr.Elem(), nil
+}
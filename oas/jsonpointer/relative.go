@@ -0,0 +1,146 @@
+package jsonpointer
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// RelativePtr is a Relative JSON Pointer, which refers to a value relative to a location in a document: it goes up a
+// number of levels, optionally moves to another element of the same array, and then either refers to a value with a
+// JSON Pointer, such as "1/name", or to the member name or array index of the location with "#", such as "0#".
+//
+// See https://datatracker.ietf.org/doc/html/draft-bhutton-relative-json-pointer-00
+type RelativePtr string
+
+var relativePtrRe = regexp.MustCompile(`^(0|[1-9][0-9]*)([+-][1-9][0-9]*)?(#|(?:/(?:[^/~]|~[01])*)*)$`)
+
+// relative is a parsed RelativePtr.
+type relative struct {
+	up     int
+	offset int
+	name   bool // "#"
+	ptr    Ptr
+}
+
+func (p RelativePtr) parse() (relative, error) {
+	m := relativePtrRe.FindStringSubmatch(string(p))
+	if m == nil {
+		return relative{}, errors.New("jsonpointer.RelativePtr: invalid syntax")
+	}
+	var r relative
+	var err error
+	if r.up, err = strconv.Atoi(m[1]); err != nil {
+		return relative{}, fmt.Errorf("jsonpointer.RelativePtr: invalid prefix: %w", err)
+	}
+	if m[2] != "" {
+		if r.offset, err = strconv.Atoi(m[2]); err != nil {
+			return relative{}, fmt.Errorf("jsonpointer.RelativePtr: invalid index manipulation: %w", err)
+		}
+	}
+	r.name = m[3] == "#"
+	if !r.name {
+		r.ptr = Ptr(m[3])
+	}
+	return r, nil
+}
+
+// Validate returns an error if p is not a Relative JSON Pointer.
+func (p RelativePtr) Validate() error {
+	_, err := p.parse()
+	return err
+}
+
+// Resolve returns the JSON Pointer which p refers to from the location from. It is an error if p refers to a member
+// name or an array index with "#", which is not a location, or goes up beyond the root of the document.
+func (p RelativePtr) Resolve(from Ptr) (Ptr, error) {
+	r, err := p.parse()
+	if err != nil {
+		return "", err
+	}
+	if r.name {
+		return "", errors.New("jsonpointer.RelativePtr: cannot resolve a member name or array index")
+	}
+	loc, err := r.origin(from)
+	if err != nil {
+		return "", err
+	}
+	return loc + r.ptr, nil
+}
+
+// origin returns the location the JSON Pointer of r is evaluated from, which is from after going up and moving within
+// its array.
+func (r relative) origin(from Ptr) (Ptr, error) {
+	if !jsonPointerRe.MatchString(string(from)) {
+		return "", errors.New("jsonpointer.Ptr: invalid syntax")
+	}
+	segments := strings.Split(string(from), ptrSep)[1:]
+	if r.up > len(segments) {
+		return "", fmt.Errorf("jsonpointer.RelativePtr: cannot go up %d levels from %q", r.up, from)
+	}
+	segments = segments[:len(segments)-r.up]
+	if r.offset != 0 {
+		if len(segments) == 0 || !arrayIndexRe.MatchString(segments[len(segments)-1]) {
+			return "", fmt.Errorf("jsonpointer.RelativePtr: cannot manipulate the index of %q, which is not an array element", from)
+		}
+		idx, err := strconv.Atoi(segments[len(segments)-1])
+		if err != nil || idx+r.offset < 0 {
+			return "", fmt.Errorf("jsonpointer.RelativePtr: index out of range")
+		}
+		segments[len(segments)-1] = strconv.Itoa(idx + r.offset)
+	}
+	if len(segments) == 0 {
+		return "", nil
+	}
+	return Ptr(ptrSep + strings.Join(segments, ptrSep)), nil
+}
+
+// Access returns the value p refers to in document from the location from. With "#", it is the member name as a string
+// or the array index as an int of the location p goes up to.
+func (p RelativePtr) Access(document any, from Ptr) (reflect.Value, error) {
+	r, err := p.parse()
+	if err != nil {
+		return reflect.Value{}, err
+	}
+	loc, err := r.origin(from)
+	if err != nil {
+		return reflect.Value{}, err
+	}
+	if r.offset == 0 && !r.name {
+		return (loc + r.ptr).Access(document)
+	}
+
+	if loc == "" {
+		return reflect.Value{}, errors.New("jsonpointer.RelativePtr: the root of the document has no member name or array index")
+	}
+	if _, err := loc.Access(document); err != nil {
+		return reflect.Value{}, err
+	}
+	i := strings.LastIndex(string(loc), ptrSep)
+	parent, err := loc[:i].Access(document)
+	if err != nil {
+		return reflect.Value{}, err
+	}
+	for parent.Kind() == reflect.Interface || parent.Kind() == reflect.Ptr {
+		parent = parent.Elem()
+	}
+	isArray := parent.Kind() == reflect.Slice || parent.Kind() == reflect.Array
+	if r.offset != 0 && !isArray {
+		return reflect.Value{}, fmt.Errorf("jsonpointer.RelativePtr: cannot manipulate the index of %q, which is not an array element", from)
+	}
+	if !r.name {
+		return (loc + r.ptr).Access(document)
+	}
+	last := unEscaper.Replace(string(loc[i+1:]))
+	if !isArray {
+		return reflect.ValueOf(last), nil
+	}
+	idx, err := strconv.Atoi(last)
+	if err != nil {
+		return reflect.Value{}, fmt.Errorf("jsonpointer.RelativePtr: invalid array index %q", last)
+	}
+	return reflect.ValueOf(idx), nil
+}
@@ -1,6 +1,7 @@
 package abc
 
 import (
+	"context"
 	"iter"
 
 	"github.com/MaiMee1/go-apispec/oas/jsonschema"
@@ -27,16 +28,59 @@ func AppliesTo(m KeywordCollection, t jsonschema.Type) bool {
 	return true
 }
 
+// Validate returns jsonschema.ValidationErrors of all keywords of m failed by v, or the first other error.
 func Validate(m KeywordCollection, v interface{}) error {
-	var err error // TODO: create validation errors
+	return jsonschema.Errors(Evaluate(m, v))
+}
+
+// Evaluate returns the unit of the schema m evaluated against v, with the units of its keywords nested. v may be any
+// Go value, see jsonschema.Normalize.
+func Evaluate(m KeywordCollection, v interface{}) ([]*jsonschema.Output, error) {
+	return EvaluateContext(context.Background(), m, v)
+}
+
+// EvaluateContext is Evaluate within the context of an evaluation, see jsonschema.EvaluateContext.
+func EvaluateContext(ctx context.Context, m KeywordCollection, v interface{}) ([]*jsonschema.Output, error) {
+	v, err := jsonschema.Normalize(v)
+	if err != nil {
+		return nil, err
+	}
+	var units []*jsonschema.Output
+	var dependents []jsonschema.AdjacentEvaluator
 	t := jsonschema.TypeOf(v)
 	for mixin := range m.Keywords() {
-		if mixin.AppliesTo(t) && mixin.Kind().Has(jsonschema.Assertion) {
-			err = mixin.Validate(v)
-			if err != nil {
+		if !mixin.AppliesTo(t) || !mixin.Kind().Has(jsonschema.Applicator|jsonschema.Annotation|jsonschema.Assertion) {
+			continue
+		}
+		if dependent, ok := mixin.(jsonschema.AdjacentEvaluator); ok {
+			dependents = append(dependents, dependent)
+			continue
+		}
+		u, err := jsonschema.EvaluateContext(ctx, mixin, v)
+		if err != nil {
+			return nil, err
+		}
+		units = append(units, u...)
+	}
+	adjacent := units
+	for _, dependent := range dependents {
+		u, err := dependent.EvaluateAdjacent(ctx, v, adjacent)
+		if err != nil {
+			return nil, err
+		}
+		units = append(units, u...)
+	}
+	return []*jsonschema.Output{jsonschema.NewOutput("", units...)}, nil
+}
+
+// Compile compiles the keywords of m which are jsonschema.Compiler.
+func Compile(m KeywordCollection) error {
+	for mixin := range m.Keywords() {
+		if c, ok := mixin.(jsonschema.Compiler); ok {
+			if err := c.Compile(); err != nil {
 				return err
 			}
 		}
 	}
-	return err
+	return nil
 }
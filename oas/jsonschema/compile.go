@@ -0,0 +1,45 @@
+package jsonschema
+
+import "context"
+
+// Validator is a compiled schema. It is safe for concurrent use.
+type Validator struct {
+	schema Keyword
+	ctx    context.Context // of evaluations
+}
+
+// Option is an option of Compile.
+type Option func(v *Validator)
+
+// WithFormatAssertion makes format an assertion in evaluations of the Validator, checked by checkers, if any, and
+// otherwise by the built-in and registered checkers, see AssertFormat.
+func WithFormatAssertion(checkers map[Format]FormatChecker) Option {
+	return func(v *Validator) {
+		v.ctx = AssertFormat(v.ctx, checkers)
+	}
+}
+
+// Compile prepares schema and its subschemas for evaluation if schema is a Compiler, and returns the Validator of
+// schema with options, which must not be modified afterwards.
+func Compile(schema Keyword, options ...Option) (*Validator, error) {
+	if c, ok := schema.(Compiler); ok {
+		if err := c.Compile(); err != nil {
+			return nil, err
+		}
+	}
+	v := &Validator{schema: schema, ctx: context.Background()}
+	for _, option := range options {
+		option(v)
+	}
+	return v, nil
+}
+
+// Validate validates instance against the schema, see Keyword.Validate.
+func (v *Validator) Validate(instance interface{}) error {
+	return Errors(v.Evaluate(instance))
+}
+
+// Evaluate returns the units of the schema evaluated against instance, see Evaluator.
+func (v *Validator) Evaluate(instance interface{}) ([]*Output, error) {
+	return EvaluateContext(v.ctx, v.schema, instance)
+}
@@ -0,0 +1,59 @@
+package jsonschema
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"sync"
+)
+
+// Decoder decodes the JSON encoding of a schema of a dialect.
+type Decoder func(b []byte) (Keyword, error)
+
+var (
+	dialectsMu sync.RWMutex
+	dialects   = map[string]Decoder{}
+)
+
+// RegisterDialect registers decode for schemas of the dialect identified by the meta-schema uri, replacing the
+// decoder of uri if any. The schema packages register their dialects when imported.
+func RegisterDialect(uri string, decode Decoder) {
+	dialectsMu.Lock()
+	defer dialectsMu.Unlock()
+	dialects[strings.TrimSuffix(uri, "#")] = decode
+}
+
+// DecoderOf returns the Decoder of schemas of type S.
+func DecoderOf[S any, P interface {
+	*S
+	Keyword
+}]() Decoder {
+	return func(b []byte) (Keyword, error) {
+		s := P(new(S))
+		if err := json.Unmarshal(b, s); err != nil {
+			return nil, err
+		}
+		return s, nil
+	}
+}
+
+// Decode decodes the JSON encoding of a schema in the dialect of its "$schema", or in dialect if it has none. The
+// dialect must be registered, see RegisterDialect.
+//
+// The schema is not compiled, see Compile.
+func Decode(b []byte, dialect string) (Keyword, error) {
+	var meta struct {
+		Schema string `json:"$schema"`
+	}
+	// booleans and other values are left to the decoder
+	if err := json.Unmarshal(b, &meta); err == nil && meta.Schema != "" {
+		dialect = meta.Schema
+	}
+	dialectsMu.RLock()
+	decode, ok := dialects[strings.TrimSuffix(dialect, "#")]
+	dialectsMu.RUnlock()
+	if !ok {
+		return nil, fmt.Errorf("jsonschema: unknown dialect %q", dialect)
+	}
+	return decode(b)
+}
@@ -0,0 +1,43 @@
+package draft07
+
+import (
+	"github.com/MaiMee1/go-apispec/oas/jsonschema"
+)
+
+type MetaSchemaMixin[S jsonschema.Keyword] struct {
+	Schema      string       `json:"$schema,omitempty" validate:"omitempty,uri"`
+	Id          string       `json:"$id,omitempty" validate:"omitempty,uri-reference"`
+	LegacyId    string       `json:"id,omitempty" validate:"omitempty,uri-reference"` // draft-04 "$id"
+	Comment     string       `json:"$comment,omitempty"`
+	Definitions map[string]S `json:"definitions,omitempty" validate:"dive"`
+}
+
+func (m *MetaSchemaMixin[S]) Kind() jsonschema.Kind {
+	return jsonschema.Identifier | jsonschema.ReservedLocation
+}
+
+func (m *MetaSchemaMixin[S]) AppliesTo(t jsonschema.Type) bool {
+	return true
+}
+
+// Compile compiles the subschemas of Definitions.
+func (m *MetaSchemaMixin[S]) Compile() error {
+	for _, s := range m.Definitions {
+		if err := compile(s); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (m *MetaSchemaMixin[S]) Validate(v interface{}) error {
+	return nil
+}
+
+// id returns the "$id" of m, which is "id" in draft-04.
+func (m *MetaSchemaMixin[S]) id() string {
+	if m.Id != "" {
+		return m.Id
+	}
+	return m.LegacyId
+}
@@ -0,0 +1,142 @@
+// Package draft07 implements the draft-07 dialect of JSON Schema, along with the draft-06 and draft-04 dialects it
+// is compatible with, such as the boolean exclusiveMaximum and exclusiveMinimum, and the "id", of draft-04.
+package draft07
+
+import (
+	"context"
+	"iter"
+	"reflect"
+	"slices"
+
+	"github.com/MaiMee1/go-apispec/oas/jsonschema"
+	"github.com/MaiMee1/go-apispec/oas/jsonschema/abc"
+	"github.com/MaiMee1/go-apispec/oas/jsonschema/draft2020"
+	"github.com/MaiMee1/go-apispec/oas/resolve"
+)
+
+// URIs of the meta-schemas of the dialects, which select Schema through "$schema", see jsonschema.Decode.
+const (
+	Dialect        = "http://json-schema.org/draft-07/schema#"
+	Draft06Dialect = "http://json-schema.org/draft-06/schema#"
+	Draft04Dialect = "http://json-schema.org/draft-04/schema#"
+)
+
+func init() {
+	for _, dialect := range []string{Dialect, Draft06Dialect, Draft04Dialect} {
+		jsonschema.RegisterDialect(dialect, jsonschema.DecoderOf[Schema]())
+	}
+}
+
+var zero Schema
+
+type Schema struct {
+	MetaSchemaMixin[*Schema]
+	draft2020.ReferenceMixin[*Schema]
+	draft2020.MetaDataMixin
+	draft2020.ValidationMixin
+	draft2020.StringMixin
+	NumericMixin
+	draft2020.ObjectMixin[*Schema]
+	DependenciesMixin[*Schema]
+	ArrayMixin[*Schema]
+	draft2020.ApplicatorMixin[*Schema]
+
+	keywords []jsonschema.Keyword // cached by Compile
+}
+
+// Keywords yields the mixins of m, which is only ReferenceMixin if m has a "$ref", as the other keywords of the
+// schema are ignored, see https://json-schema.org/draft-07/draft-handrews-json-schema-01#section-8.3.
+func (m *Schema) Keywords() iter.Seq[jsonschema.Keyword] {
+	if m.keywords != nil {
+		return slices.Values(m.keywords)
+	}
+	return func(yield func(jsonschema.Keyword) bool) {
+		if m.Ref != "" {
+			yield(&m.ReferenceMixin)
+			return
+		}
+		if !reflect.DeepEqual(m.MetaSchemaMixin, zero.MetaSchemaMixin) {
+			if !yield(&m.MetaSchemaMixin) {
+				return
+			}
+		}
+		if !reflect.DeepEqual(m.MetaDataMixin, zero.MetaDataMixin) {
+			if !yield(&m.MetaDataMixin) {
+				return
+			}
+		}
+		if !reflect.DeepEqual(m.ValidationMixin, zero.ValidationMixin) {
+			if !yield(&m.ValidationMixin) {
+				return
+			}
+		}
+		if !reflect.DeepEqual(m.StringMixin, zero.StringMixin) {
+			if !yield(&m.StringMixin) {
+				return
+			}
+		}
+		if !reflect.DeepEqual(m.NumericMixin, zero.NumericMixin) {
+			if !yield(&m.NumericMixin) {
+				return
+			}
+		}
+		if !reflect.DeepEqual(m.ObjectMixin, zero.ObjectMixin) {
+			if !yield(&m.ObjectMixin) {
+				return
+			}
+		}
+		if !reflect.DeepEqual(m.DependenciesMixin, zero.DependenciesMixin) {
+			if !yield(&m.DependenciesMixin) {
+				return
+			}
+		}
+		if !reflect.DeepEqual(m.ArrayMixin, zero.ArrayMixin) {
+			if !yield(&m.ArrayMixin) {
+				return
+			}
+		}
+		if !reflect.DeepEqual(m.ApplicatorMixin, zero.ApplicatorMixin) {
+			if !yield(&m.ApplicatorMixin) {
+				return
+			}
+		}
+	}
+}
+
+func (m *Schema) Kind() jsonschema.Kind {
+	return abc.Kind(m)
+}
+
+func (m *Schema) AppliesTo(t jsonschema.Type) bool {
+	return abc.AppliesTo(m, t)
+}
+
+func (m *Schema) Validate(v interface{}) error {
+	return jsonschema.Errors(m.Evaluate(v))
+}
+
+// Evaluate returns the unit of evaluating m against v, see jsonschema.Output.Format.
+func (m *Schema) Evaluate(v interface{}) ([]*jsonschema.Output, error) {
+	return m.EvaluateContext(context.Background(), v)
+}
+
+// EvaluateContext is Evaluate within the context of an evaluation, in which m enters the dynamic scope, see
+// resolve.EnterScope.
+func (m *Schema) EvaluateContext(ctx context.Context, v interface{}) ([]*jsonschema.Output, error) {
+	units, err := abc.EvaluateContext(resolve.EnterScope(ctx, m.Context()), m, v)
+	return jsonschema.Absolute(units, m.id()), err
+}
+
+// Compile compiles the keywords of m and of its subschemas, and caches the keywords of m, see jsonschema.Compile.
+//
+// If m is not bound to the scope of a resolver, it is bound as a standalone schema, see resolve.Standalone.
+func (m *Schema) Compile() error {
+	if m.keywords != nil {
+		return nil // compiled, or being compiled through a cyclic reference
+	}
+	if _, _, ok := resolve.Scope(m.Context()); !ok {
+		resolve.Standalone(m, m.id(), nil)
+	}
+	m.keywords = slices.AppendSeq(make([]jsonschema.Keyword, 0), m.Keywords())
+	return abc.Compile(m)
+}
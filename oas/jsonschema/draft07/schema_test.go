@@ -0,0 +1,86 @@
+package draft07
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"slices"
+	"testing"
+
+	"github.com/MaiMee1/go-apispec/oas/jsonschema"
+)
+
+func TestSchema_MarshalJSON(t *testing.T) {
+	var s Schema
+	data := `{"$schema":"http://json-schema.org/draft-04/schema#","id":"https://example.com/pet","minimum":0,"exclusiveMinimum":false,"dependencies":{"a":["b"],"c":{"required":["d"]}},"items":[{"type":"string"}],"additionalItems":false}`
+	err := json.Unmarshal([]byte(data), &s)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Act
+	b, err := json.Marshal(s)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(b) != data {
+		t.Errorf("got %s, want %s", b, data)
+	}
+}
+
+func TestSchema_Validate(t *testing.T) {
+	tests := []struct {
+		schema   string
+		instance string
+		want     []string // keyword location and message of the errors
+	}{
+		{`{"$schema": "http://json-schema.org/draft-04/schema#", "minimum": 0, "exclusiveMinimum": true}`, `0`,
+			[]string{"/exclusiveMinimum number must be greater than 0"}},
+		{`{"$schema": "http://json-schema.org/draft-04/schema#", "maximum": 1, "exclusiveMaximum": false}`, `1`, nil},
+		{`{"$schema": "http://json-schema.org/draft-06/schema#", "exclusiveMaximum": 1}`, `1`,
+			[]string{"/exclusiveMaximum number must be less than 1"}},
+		{`{"items": [{"type": "integer"}, {"type": "string"}], "additionalItems": false}`, `[1, 2, 3]`,
+			[]string{"/items/1/type value must be of type string, got number", "/additionalItems array must not have additional items from index 2"}},
+		{`{"items": [{"type": "integer"}], "additionalItems": {"type": "string"}}`, `[1, "a", 3]`,
+			[]string{"/additionalItems/type value must be of type string, got number"}},
+		{`{"items": {"type": "integer"}, "additionalItems": false}`, `[1, "a"]`,
+			[]string{"/items/type value must be of type integer, got string"}},
+		{`{"dependencies": {"card": ["billing"], "billing": {"required": ["country"]}}}`, `{"card": 1, "billing": "x"}`,
+			[]string{"/dependencies/billing/required object must have properties \"country\""}},
+		{`{"dependencies": {"card": ["billing", "name"]}}`, `{"card": 1, "name": "x"}`,
+			[]string{"/dependencies object must have properties \"billing\" as it has property \"card\""}},
+		{`{"definitions": {"name": {"type": "string"}}, "properties": {"name": {"$ref": "#/definitions/name", "maxLength": 1}}}`, `{"name": "Tom"}`, nil},
+		{`{"definitions": {"name": {"$id": "#name", "type": "string"}}, "properties": {"name": {"$ref": "#name"}}}`, `{"name": 1}`,
+			[]string{"/properties/name/$ref/type value must be of type string, got number"}},
+		{`{"id": "https://example.com/pet", "definitions": {"name": {"id": "#name", "type": "string"}}, "properties": {"name": {"$ref": "#name"}}}`, `{"name": 1}`,
+			[]string{"/properties/name/$ref/type value must be of type string, got number"}},
+	}
+	for i, tt := range tests {
+		t.Run(fmt.Sprintf("%d", i), func(t *testing.T) {
+			s, err := jsonschema.Decode([]byte(tt.schema), Dialect)
+			if err != nil {
+				t.Fatal(err)
+			}
+			v, err := jsonschema.Compile(s)
+			if err != nil {
+				t.Fatal(err)
+			}
+			var instance interface{}
+			if err := json.Unmarshal([]byte(tt.instance), &instance); err != nil {
+				t.Fatal(err)
+			}
+			// Act
+			err = v.Validate(instance)
+			var errs jsonschema.ValidationErrors
+			if err != nil && !errors.As(err, &errs) {
+				t.Fatal(err)
+			}
+			var got []string
+			for _, e := range errs {
+				got = append(got, string(e.KeywordLocation)+" "+e.Message)
+			}
+			if !slices.Equal(got, tt.want) {
+				t.Errorf("got %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,290 @@
+package draft07
+
+import (
+	"context"
+	"encoding/json"
+	"maps"
+	"reflect"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/MaiMee1/go-apispec/oas/jsonschema"
+	"github.com/MaiMee1/go-apispec/oas/jsonschema/draft2020"
+	"github.com/MaiMee1/go-apispec/oas/ser"
+)
+
+// Bound is the value of exclusiveMaximum and exclusiveMinimum, which is a boolean making maximum and minimum
+// exclusive in draft-04, and a number since draft-06.
+type Bound struct {
+	Exclusive bool     // draft-04
+	Limit     *float64 // draft-06 and later
+}
+
+//goland:noinspection GoMixedReceiverTypes
+func (b Bound) MarshalJSON() ([]byte, error) {
+	if b.Limit != nil {
+		return json.Marshal(*b.Limit)
+	}
+	return json.Marshal(b.Exclusive)
+}
+
+//goland:noinspection GoMixedReceiverTypes
+func (b *Bound) UnmarshalJSON(data []byte) error {
+	var exclusive bool
+	if err := json.Unmarshal(data, &exclusive); err == nil {
+		*b = Bound{Exclusive: exclusive}
+		return nil
+	}
+	var limit float64
+	if err := json.Unmarshal(data, &limit); err != nil {
+		return err
+	}
+	*b = Bound{Limit: &limit}
+	return nil
+}
+
+type NumericMixin struct {
+	MultipleOf       *float64 `json:"multipleOf,omitempty" validate:"omitempty,gt=0"`
+	Maximum          *float64 `json:"maximum,omitempty"`
+	ExclusiveMaximum *Bound   `json:"exclusiveMaximum,omitempty"`
+	Minimum          *float64 `json:"minimum,omitempty"`
+	ExclusiveMinimum *Bound   `json:"exclusiveMinimum,omitempty"`
+}
+
+func (m *NumericMixin) Kind() jsonschema.Kind {
+	return jsonschema.Assertion
+}
+
+func (m *NumericMixin) AppliesTo(t jsonschema.Type) bool {
+	return t.Has(jsonschema.IntegerType | jsonschema.NumberType)
+}
+
+func (m *NumericMixin) Validate(v interface{}) error {
+	return jsonschema.Errors(m.Evaluate(v))
+}
+
+// Evaluate evaluates m as the equivalent 2020-12 keywords, so that an exclusive maximum or minimum of draft-04 fails
+// as exclusiveMaximum or exclusiveMinimum.
+func (m *NumericMixin) Evaluate(v interface{}) ([]*jsonschema.Output, error) {
+	mixin := m.normalized()
+	return mixin.Evaluate(v)
+}
+
+func (m *NumericMixin) normalized() draft2020.NumericMixin {
+	mixin := draft2020.NumericMixin{MultipleOf: m.MultipleOf, Maximum: m.Maximum, Minimum: m.Minimum}
+	if m.ExclusiveMaximum != nil {
+		if m.ExclusiveMaximum.Limit != nil {
+			mixin.ExclusiveMaximum = m.ExclusiveMaximum.Limit
+		} else if m.ExclusiveMaximum.Exclusive {
+			mixin.Maximum, mixin.ExclusiveMaximum = nil, m.Maximum
+		}
+	}
+	if m.ExclusiveMinimum != nil {
+		if m.ExclusiveMinimum.Limit != nil {
+			mixin.ExclusiveMinimum = m.ExclusiveMinimum.Limit
+		} else if m.ExclusiveMinimum.Exclusive {
+			mixin.Minimum, mixin.ExclusiveMinimum = nil, m.Minimum
+		}
+	}
+	return mixin
+}
+
+type ArrayMixin[S jsonschema.Keyword] struct {
+	MaxItems        *jsonschema.Int  `json:"maxItems,omitempty" validate:"omitempty,gte=0"`
+	MinItems        jsonschema.Int   `json:"minItems,omitempty" validate:"omitempty,gte=0"`
+	UniqueItems     bool             `json:"uniqueItems,omitempty"`
+	Items           *ser.Or[[]S, S]  `json:"items,omitempty"`
+	AdditionalItems *ser.Or[bool, S] `json:"additionalItems,omitempty"`
+	Contains        S                `json:"contains,omitempty"`
+}
+
+func (m *ArrayMixin[S]) Kind() jsonschema.Kind {
+	return jsonschema.Assertion
+}
+
+func (m *ArrayMixin[S]) AppliesTo(t jsonschema.Type) bool {
+	return t.Has(jsonschema.ArrayType)
+}
+
+// Compile compiles the subschemas.
+func (m *ArrayMixin[S]) Compile() error {
+	if m.Items != nil {
+		if err := compile(m.Items.X...); err != nil {
+			return err
+		}
+		if err := compile(m.Items.Y); err != nil {
+			return err
+		}
+	}
+	if m.AdditionalItems != nil {
+		if err := compile(m.AdditionalItems.Y); err != nil {
+			return err
+		}
+	}
+	return compile(m.Contains)
+}
+
+func (m *ArrayMixin[S]) Validate(v interface{}) error {
+	return jsonschema.Errors(m.Evaluate(v))
+}
+
+func (m *ArrayMixin[S]) Evaluate(v interface{}) ([]*jsonschema.Output, error) {
+	return m.EvaluateContext(context.Background(), v)
+}
+
+// EvaluateContext evaluates the keywords shared with 2020-12 as such, and items either as a schema applied to every
+// item or as an array of schemas applied to the item at the same index, followed by additionalItems.
+func (m *ArrayMixin[S]) EvaluateContext(ctx context.Context, v interface{}) ([]*jsonschema.Output, error) {
+	shared := draft2020.ArrayMixin[S]{MaxItems: m.MaxItems, MinItems: m.MinItems, UniqueItems: m.UniqueItems, Contains: m.Contains}
+	units, err := shared.EvaluateContext(ctx, v)
+	if err != nil {
+		return nil, err
+	}
+	v, err = jsonschema.Normalize(v)
+	if err != nil {
+		return nil, err
+	}
+	arr, ok := v.([]interface{})
+	if m.Items == nil || !ok {
+		return units, nil
+	}
+	var nested []*jsonschema.Output
+	if m.Items.X == nil {
+		for i, e := range arr {
+			u, err := jsonschema.EvaluateContext(ctx, m.Items.Y, e)
+			if err != nil {
+				return nil, err
+			}
+			nested = append(nested, jsonschema.Prefix(u, strconv.Itoa(i), "items")...)
+		}
+		return append(units, jsonschema.NewOutput("items", nested...)), nil
+	}
+
+	n := min(len(arr), len(m.Items.X))
+	for i, e := range arr[:n] {
+		index := strconv.Itoa(i)
+		u, err := jsonschema.EvaluateContext(ctx, m.Items.X[i], e)
+		if err != nil {
+			return nil, err
+		}
+		nested = append(nested, jsonschema.Prefix(u, index, "items", index)...)
+	}
+	units = append(units, jsonschema.NewOutput("items", nested...))
+	if m.AdditionalItems == nil || n == len(arr) {
+		return units, nil
+	}
+	if isNil(m.AdditionalItems.Y) {
+		return append(units, assert("additionalItems", m.AdditionalItems.X, strconv.Itoa(n))), nil
+	}
+	nested = nil
+	for i, e := range arr[n:] {
+		u, err := jsonschema.EvaluateContext(ctx, m.AdditionalItems.Y, e)
+		if err != nil {
+			return nil, err
+		}
+		nested = append(nested, jsonschema.Prefix(u, strconv.Itoa(n+i), "additionalItems")...)
+	}
+	return append(units, jsonschema.NewOutput("additionalItems", nested...)), nil
+}
+
+// DependenciesMixin is the dependencies keyword, which was split into dependentRequired and dependentSchemas in
+// 2019-09.
+type DependenciesMixin[S jsonschema.Keyword] struct {
+	Dependencies map[string]ser.Or[[]string, S] `json:"dependencies,omitempty"`
+}
+
+func (m *DependenciesMixin[S]) Kind() jsonschema.Kind {
+	return jsonschema.Applicator | jsonschema.Assertion
+}
+
+func (m *DependenciesMixin[S]) AppliesTo(t jsonschema.Type) bool {
+	return t.Has(jsonschema.ObjectType)
+}
+
+// Compile compiles the subschemas.
+func (m *DependenciesMixin[S]) Compile() error {
+	for _, dependency := range m.Dependencies {
+		if err := compile(dependency.Y); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (m *DependenciesMixin[S]) Validate(v interface{}) error {
+	return jsonschema.Errors(m.Evaluate(v))
+}
+
+func (m *DependenciesMixin[S]) Evaluate(v interface{}) ([]*jsonschema.Output, error) {
+	return m.EvaluateContext(context.Background(), v)
+}
+
+// EvaluateContext requires the properties of an array dependency, and applies the schema of a schema dependency, if
+// the object has the property the dependency is named after.
+func (m *DependenciesMixin[S]) EvaluateContext(ctx context.Context, v interface{}) ([]*jsonschema.Output, error) {
+	v, err := jsonschema.Normalize(v)
+	if err != nil {
+		return nil, err
+	}
+	obj, ok := v.(map[string]interface{})
+	if !ok {
+		return nil, nil
+	}
+	var units, nested []*jsonschema.Output
+	for _, name := range slices.Sorted(maps.Keys(m.Dependencies)) {
+		if _, found := obj[name]; !found {
+			continue
+		}
+		dependency := m.Dependencies[name]
+		if isNil(dependency.Y) {
+			var missing []string
+			for _, req := range dependency.X {
+				if _, found := obj[req]; !found {
+					missing = append(missing, strconv.Quote(req))
+				}
+			}
+			units = append(units, assert("dependencies", len(missing) == 0, strings.Join(missing, ", "), strconv.Quote(name)))
+			continue
+		}
+		u, err := jsonschema.EvaluateContext(ctx, dependency.Y, obj)
+		if err != nil {
+			return nil, err
+		}
+		nested = append(nested, jsonschema.Prefix(u, "", "dependencies", name)...)
+	}
+	if len(nested) > 0 {
+		units = append(units, jsonschema.NewOutput("dependencies", nested...))
+	}
+	return units, nil
+}
+
+// assert returns the unit of keyword, failed with params unless ok.
+func assert(keyword string, ok bool, params ...string) *jsonschema.Output {
+	u := jsonschema.NewOutput(keyword)
+	if !ok {
+		u.Fail(params...)
+	}
+	return u
+}
+
+// compile compiles the subschemas which are jsonschema.Compiler.
+func compile[S jsonschema.Keyword](schemas ...S) error {
+	for _, s := range schemas {
+		if isNil(s) {
+			continue
+		}
+		if c, ok := any(s).(jsonschema.Compiler); ok {
+			if err := c.Compile(); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// isNil reports whether the subschema s is absent.
+func isNil[S any](s S) bool {
+	v := reflect.ValueOf(s)
+	return !v.IsValid() || (v.Kind() == reflect.Pointer && v.IsNil())
+}
@@ -1,10 +1,8 @@
 package draft2020
 
 import (
-	"errors"
-	"fmt"
-	"reflect"
-	"slices"
+	"context"
+	"strconv"
 
 	"github.com/MaiMee1/go-apispec/oas/jsonschema"
 )
@@ -27,50 +25,109 @@ func (m *ApplicatorMixin[S]) AppliesTo(t jsonschema.Type) bool {
 	return true
 }
 
+// Compile compiles the subschemas.
+func (m *ApplicatorMixin[S]) Compile() error {
+	for _, schemas := range [][]S{m.AllOf, m.AnyOf, m.OneOf} {
+		if err := compile(schemas...); err != nil {
+			return err
+		}
+	}
+	return compile(m.If, m.Then, m.Else, m.Not)
+}
+
 func (m *ApplicatorMixin[S]) Validate(v interface{}) error {
+	return jsonschema.Errors(m.Evaluate(v))
+}
+
+func (m *ApplicatorMixin[S]) Evaluate(v interface{}) ([]*jsonschema.Output, error) {
+	return m.EvaluateContext(context.Background(), v)
+}
+
+func (m *ApplicatorMixin[S]) EvaluateContext(ctx context.Context, v interface{}) ([]*jsonschema.Output, error) {
+	var units []*jsonschema.Output
 	if len(m.AllOf) > 0 {
-		for i, schema := range m.AllOf {
-			if err := schema.Validate(v); err != nil {
-				return fmt.Errorf("allOff[%d]: %w", i, err)
-			}
+		nested, _, err := evaluateEach(ctx, m.AllOf, v, "allOf")
+		if err != nil {
+			return nil, err
 		}
+		units = append(units, jsonschema.NewOutput("allOf", nested...))
 	}
 	if len(m.AnyOf) > 0 {
-		slices.ContainsFunc(m.AnyOf, func(schema S) bool {
-			return schema.Validate(v) == nil
-		})
+		// every subschema is evaluated to collect annotations
+		nested, n, err := evaluateEach(ctx, m.AnyOf, v, "anyOf")
+		if err != nil {
+			return nil, err
+		}
+		unit := jsonschema.NewOutput("anyOf", nested...)
+		if n > 0 {
+			unit.Pass()
+		} else {
+			unit.Fail()
+		}
+		units = append(units, unit)
 	}
 	if len(m.OneOf) > 0 {
-		var indices []int
-		for i, schema := range m.AllOf {
-			if schema.Validate(v) == nil {
-				indices = append(indices, i)
-			}
+		nested, n, err := evaluateEach(ctx, m.OneOf, v, "oneOf")
+		if err != nil {
+			return nil, err
 		}
-		if len(indices) != 1 {
-			return fmt.Errorf("oneOf: found %d matching at %v, want %d", len(indices), indices, 1)
+		unit := jsonschema.NewOutput("oneOf", nested...)
+		if n == 1 {
+			unit.Pass()
+		} else {
+			unit.Fail(strconv.Itoa(n))
 		}
+		units = append(units, unit)
 	}
-	if !reflect.DeepEqual(m.If, nil) && (!reflect.DeepEqual(m.Then, nil) || !reflect.DeepEqual(m.Else, nil)) {
-		eval := m.If.Validate(v) == nil
-		if eval {
-			if !reflect.DeepEqual(m.Then, nil) {
-				if err := m.Then.Validate(v); err != nil {
-					return fmt.Errorf("then: %w", err)
-				}
+	if !isNil(m.If) {
+		nested, err := jsonschema.EvaluateContext(ctx, m.If, v)
+		if err != nil {
+			return nil, err
+		}
+		// if only selects then or else, but keeps its annotations when valid
+		units = append(units, jsonschema.NewOutput("if", jsonschema.Prefix(nested, "", "if")...).Pass())
+		keyword, schema := "then", m.Then
+		if !valid(nested) {
+			keyword, schema = "else", m.Else
+		}
+		if !isNil(schema) {
+			nested, err := jsonschema.EvaluateContext(ctx, schema, v)
+			if err != nil {
+				return nil, err
 			}
+			units = append(units, jsonschema.NewOutput(keyword, jsonschema.Prefix(nested, "", keyword)...))
+		}
+	}
+	if !isNil(m.Not) {
+		nested, err := jsonschema.EvaluateContext(ctx, m.Not, v)
+		if err != nil {
+			return nil, err
+		}
+		unit := jsonschema.NewOutput("not", jsonschema.Prefix(nested, "", "not")...)
+		if valid(nested) {
+			unit.Fail()
 		} else {
-			if !reflect.DeepEqual(m.Else, nil) {
-				if err := m.Else.Validate(v); err != nil {
-					return fmt.Errorf("else: %w", err)
-				}
-			}
+			unit.Pass()
 		}
+		units = append(units, unit)
 	}
-	if !reflect.DeepEqual(m.Not, nil) {
-		if m.Not.Validate(v) == nil {
-			return errors.New("not: schema matched")
+	return units, nil
+}
+
+// evaluateEach returns the units of schemas evaluated against v, located at keyword and their index, and the number
+// of valid schemas.
+func evaluateEach[S jsonschema.Keyword](ctx context.Context, schemas []S, v interface{}, keyword string) ([]*jsonschema.Output, int, error) {
+	var units []*jsonschema.Output
+	n := 0
+	for i, schema := range schemas {
+		u, err := jsonschema.EvaluateContext(ctx, schema, v)
+		if err != nil {
+			return nil, 0, err
+		}
+		if valid(u) {
+			n++
 		}
+		units = append(units, jsonschema.Prefix(u, "", keyword, strconv.Itoa(i))...)
 	}
-	return nil
+	return units, n, nil
 }
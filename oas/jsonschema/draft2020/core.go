@@ -1,16 +1,57 @@
 package draft2020
 
 import (
+	"bytes"
 	"context"
+	"errors"
+	"fmt"
+	"reflect"
 
-	"github.com/MaiMee1/go-apispec/oas/jsonpointer"
 	"github.com/MaiMee1/go-apispec/oas/jsonschema"
+	"github.com/MaiMee1/go-apispec/oas/resolve"
 )
 
+// BooleanMixin makes a schema the boolean schema true, which every instance is valid against, or false, which none
+// is, if Boolean is not nil, see https://json-schema.org/draft/2020-12/json-schema-core#section-4.3.2. A boolean
+// schema has no other keywords.
+type BooleanMixin struct {
+	Boolean *bool `json:"-"`
+}
+
+func (m *BooleanMixin) Kind() jsonschema.Kind {
+	return jsonschema.Assertion
+}
+
+func (m *BooleanMixin) AppliesTo(t jsonschema.Type) bool {
+	return true
+}
+
+// Validate fails every v if m is the boolean schema false, with an error located at the schema itself.
+func (m *BooleanMixin) Validate(v interface{}) error {
+	if m.Boolean == nil || *m.Boolean {
+		return nil
+	}
+	err := jsonschema.NewValidationError("false")
+	err.KeywordLocation = ""
+	return jsonschema.ValidationErrors{err}
+}
+
+// ParseBoolean returns the value of the boolean schema encoded by b, or false if b does not encode one.
+func ParseBoolean(b []byte) (value bool, ok bool) {
+	switch string(bytes.TrimSpace(b)) {
+	case "true":
+		return true, true
+	case "false":
+		return false, true
+	default:
+		return false, false
+	}
+}
+
 type MetaSchemaMixin[S jsonschema.Keyword] struct {
 	Schema        string          `json:"$schema,omitempty" validate:"omitempty,uri"`
 	Id            string          `json:"$id,omitempty" validate:"omitempty,uri-reference"`
-	Comments      string          `json:"$comments,omitempty"`
+	Comment       string          `json:"$comment,omitempty"`
 	Defs          map[string]S    `json:"$defs,omitempty" validate:"dive"`
 	Anchor        string          `json:"$anchor,omitempty"`
 	DynamicAnchor string          `json:"$dynamicAnchor,omitempty"`
@@ -25,6 +66,16 @@ func (m *MetaSchemaMixin[S]) AppliesTo(t jsonschema.Type) bool {
 	return true
 }
 
+// Compile compiles the subschemas of Defs.
+func (m *MetaSchemaMixin[S]) Compile() error {
+	for _, s := range m.Defs {
+		if err := compile(s); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
 func (m *MetaSchemaMixin[S]) Validate(v interface{}) error {
 	_ = v.(*Schema)
 	return nil
@@ -34,7 +85,8 @@ type ReferenceMixin[S any] struct {
 	Ref        string `json:"$ref,omitempty" validate:"uri-reference"`
 	DynamicRef string `json:"$dynamicRef,omitempty" validate:"uri-reference"`
 
-	ctx context.Context
+	ctx      context.Context
+	resolved *S // Ref resolved by Compile
 }
 
 func (m *ReferenceMixin[S]) Kind() jsonschema.Kind {
@@ -46,23 +98,171 @@ func (m *ReferenceMixin[S]) AppliesTo(t jsonschema.Type) bool {
 }
 
 func (m *ReferenceMixin[S]) Validate(v interface{}) error {
+	return jsonschema.Errors(m.Evaluate(v))
+}
+
+func (m *ReferenceMixin[S]) Evaluate(v interface{}) ([]*jsonschema.Output, error) {
+	return m.EvaluateContext(context.Background(), v)
+}
+
+// EvaluateContext applies the schemas referred to by Ref and DynamicRef to v, resolving DynamicRef in the dynamic
+// scope of ctx, see resolve.DynamicRef.
+func (m *ReferenceMixin[S]) EvaluateContext(ctx context.Context, v interface{}) ([]*jsonschema.Output, error) {
+	var units []*jsonschema.Output
+	if m.Ref != "" {
+		target := m.resolved
+		if target == nil {
+			var err error
+			if target, err = resolve.Ref[*S](m.ctx, m.Ref); err != nil {
+				return nil, err
+			}
+		}
+		u, err := m.apply(ctx, "$ref", m.Ref, resolve.Canonical(m.ctx, m.Ref), target, v)
+		if err != nil {
+			return nil, err
+		}
+		units = append(units, u)
+	}
+	if m.DynamicRef != "" {
+		target, location, err := resolve.DynamicRef[*S](m.ctx, ctx, m.DynamicRef)
+		if err != nil {
+			return nil, err
+		}
+		u, err := m.apply(ctx, "$dynamicRef", m.DynamicRef, location, target, v)
+		if err != nil {
+			return nil, err
+		}
+		units = append(units, u)
+	}
+	return units, nil
+}
+
+// apply returns the unit of keyword applying target, which ref refers to, to v. The units of target are located
+// absolutely at location, the canonical URI of target, unless target has an absolute "$id".
+func (m *ReferenceMixin[S]) apply(ctx context.Context, keyword string, ref string, location string, target *S, v interface{}) (*jsonschema.Output, error) {
+	schema, ok := keywordOf(target)
+	if !ok {
+		return nil, fmt.Errorf("%s: %q does not refer to a schema", keyword, ref)
+	}
+	ctx, ok = enter(ctx, location, v)
+	if !ok {
+		return nil, fmt.Errorf("%s: %q refers to %s, which is being applied to the same instance", keyword, ref, location)
+	}
+	units, err := jsonschema.EvaluateContext(ctx, schema, v)
+	if err != nil {
+		return nil, err
+	}
+	jsonschema.Absolute(units, location)
+	return jsonschema.NewOutput(keyword, jsonschema.Prefix(units, "", keyword)...), nil
+}
+
+type enteredKey struct{}
+
+// entered is a schema applied by a reference during evaluation, in the schema applied by its parent.
+type entered struct {
+	location string
+	instance interface{}
+	parent   *entered
+}
+
+// enter returns ctx with the schema at location entered to be applied to v, or false if it is already being applied
+// to v, in which case applying it again would never end.
+//
+// The location of v in the instance is not tracked, but a schema applied to the same value by an enclosing reference
+// has not descended into it, while one which has is applied to a different value, see isInstance.
+func enter(ctx context.Context, location string, v interface{}) (context.Context, bool) {
+	e, _ := ctx.Value(enteredKey{}).(*entered)
+	for p := e; p != nil; p = p.parent {
+		if p.location == location && isInstance(p.instance, v) {
+			return ctx, false
+		}
+	}
+	return context.WithValue(ctx, enteredKey{}, &entered{location, v, e}), true
+}
+
+// isInstance reports whether a and b are the same value, that is, the same array or object, or equal scalars.
+func isInstance(a, b interface{}) bool {
+	va, vb := reflect.ValueOf(a), reflect.ValueOf(b)
+	if !va.IsValid() || !vb.IsValid() {
+		return va.IsValid() == vb.IsValid()
+	}
+	if va.Type() != vb.Type() {
+		return false
+	}
+	switch va.Kind() {
+	case reflect.Map, reflect.Slice:
+		return va.Pointer() == vb.Pointer() && va.Len() == vb.Len()
+	}
+	return va.Comparable() && a == b
+}
+
+// Compile resolves Ref once, if m is bound to the scope of a resolver, and compiles the schemas Ref and DynamicRef
+// initially refer to.
+func (m *ReferenceMixin[S]) Compile() error {
+	if _, _, ok := resolve.Scope(m.ctx); !ok {
+		return nil
+	}
+	if m.Ref != "" {
+		// resolved as *S to refer to the schema itself rather than to a copy
+		resolved, err := resolve.Ref[*S](m.ctx, m.Ref)
+		if err != nil {
+			return err
+		}
+		m.resolved = resolved
+		if err := compileRef(resolved); err != nil {
+			return err
+		}
+	}
+	if m.DynamicRef != "" {
+		// the dynamic scope is only known during evaluation
+		resolved, err := resolve.Ref[*S](m.ctx, m.DynamicRef)
+		if err != nil {
+			return err
+		}
+		return compileRef(resolved)
+	}
+	return nil
+}
+
+func compileRef[S any](resolved *S) error {
+	if schema, ok := keywordOf(resolved); ok {
+		if c, ok := schema.(jsonschema.Compiler); ok {
+			return c.Compile()
+		}
+	}
 	return nil
 }
 
+// keywordOf returns the schema s points to as a jsonschema.Keyword, which is either *s or s itself.
+func keywordOf[S any](s *S) (jsonschema.Keyword, bool) {
+	if s == nil {
+		return nil, false
+	}
+	if !isNil(*s) {
+		if k, ok := any(*s).(jsonschema.Keyword); ok {
+			return k, true
+		}
+	}
+	k, ok := any(s).(jsonschema.Keyword)
+	return k, ok
+}
+
+// WithContext sets the context used to resolve Ref, see [resolve.WithScope].
 func (m *ReferenceMixin[S]) WithContext(ctx context.Context) *ReferenceMixin[S] {
 	m.ctx = ctx
 	return m
 }
 
-func (m *ReferenceMixin[S]) Resolve() S {
-	root := m.ctx.Value("Root")
-	if root == nil {
-		panic("root not available")
-	}
+// Context returns the context used to resolve Ref.
+func (m *ReferenceMixin[S]) Context() context.Context {
+	return m.ctx
+}
 
-	v, err := jsonpointer.UriFragment(m.Ref).Access(root)
-	if err != nil {
-		panic(err)
+// Resolve returns the value referred to by Ref, which may be in another document.
+func (m *ReferenceMixin[S]) Resolve() (S, error) {
+	if m.Ref == "" {
+		var s S
+		return s, errors.New("draft2020.ReferenceMixin: no $ref to resolve")
 	}
-	return v.Interface().(S)
+	return resolve.Ref[S](m.ctx, m.Ref)
 }
@@ -25,3 +25,29 @@ func (m *MetaDataMixin) AppliesTo(t jsonschema.Type) bool {
 func (m *MetaDataMixin) Validate(v interface{}) error {
 	return nil
 }
+
+func (m *MetaDataMixin) Evaluate(v interface{}) ([]*jsonschema.Output, error) {
+	var units []*jsonschema.Output
+	if m.Title != "" {
+		units = append(units, jsonschema.NewOutput("title").Annotate(m.Title))
+	}
+	if m.Description != "" {
+		units = append(units, jsonschema.NewOutput("description").Annotate(m.Description))
+	}
+	if m.Default != nil {
+		units = append(units, jsonschema.NewOutput("default").Annotate(m.Default))
+	}
+	if m.Examples != nil {
+		units = append(units, jsonschema.NewOutput("examples").Annotate(m.Examples))
+	}
+	if m.Deprecated {
+		units = append(units, jsonschema.NewOutput("deprecated").Annotate(m.Deprecated))
+	}
+	if m.ReadOnly {
+		units = append(units, jsonschema.NewOutput("readOnly").Annotate(m.ReadOnly))
+	}
+	if m.WriteOnly {
+		units = append(units, jsonschema.NewOutput("writeOnly").Annotate(m.WriteOnly))
+	}
+	return units, nil
+}
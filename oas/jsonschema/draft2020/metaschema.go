@@ -0,0 +1,170 @@
+package draft2020
+
+import (
+	"context"
+	"embed"
+	"encoding/json"
+	"fmt"
+	"io/fs"
+	"maps"
+	"slices"
+	"sync"
+
+	"github.com/MaiMee1/go-apispec/oas/jsonschema"
+	"github.com/MaiMee1/go-apispec/oas/resolve"
+	"github.com/MaiMee1/go-apispec/oas/ser"
+)
+
+// Dialect is the URI of the 2020-12 meta-schema, which schemas without "$schema" are validated against by
+// ValidateSchema.
+const Dialect = "https://json-schema.org/draft/2020-12/schema"
+
+func init() {
+	jsonschema.RegisterDialect(Dialect, jsonschema.DecoderOf[Schema]())
+}
+
+// Vocabularies are the URIs of the 2020-12 vocabularies implemented by the mixins.
+var Vocabularies = []string{
+	"https://json-schema.org/draft/2020-12/vocab/core",
+	"https://json-schema.org/draft/2020-12/vocab/applicator",
+	"https://json-schema.org/draft/2020-12/vocab/unevaluated",
+	"https://json-schema.org/draft/2020-12/vocab/validation",
+	"https://json-schema.org/draft/2020-12/vocab/meta-data",
+	"https://json-schema.org/draft/2020-12/vocab/format-annotation",
+	"https://json-schema.org/draft/2020-12/vocab/content",
+}
+
+//go:embed metaschema
+var metaSchemaFS embed.FS
+
+// MetaSchemas loads the 2020-12 meta-schema and the meta-schemas of its vocabularies by their "$id".
+//
+// Clone it to add other meta-schemas, see MetaValidator.
+var MetaSchemas = mustLoadMetaSchemas(metaSchemaFS)
+
+// LoadMetaSchemas returns a loader of the JSON or YAML documents of fsys by their "$id".
+func LoadMetaSchemas(fsys fs.FS) (resolve.MapLoader, error) {
+	loader := make(resolve.MapLoader)
+	err := fs.WalkDir(fsys, ".", func(path string, d fs.DirEntry, err error) error {
+		if err != nil || d.IsDir() {
+			return err
+		}
+		b, err := fs.ReadFile(fsys, path)
+		if err != nil {
+			return err
+		}
+		if b, err = ser.ToJson(b); err != nil {
+			return fmt.Errorf("draft2020: meta-schema %s: %w", path, err)
+		}
+		var meta struct {
+			Id string `json:"$id"`
+		}
+		if err := json.Unmarshal(b, &meta); err != nil {
+			return fmt.Errorf("draft2020: meta-schema %s: %w", path, err)
+		}
+		if meta.Id == "" {
+			return fmt.Errorf("draft2020: meta-schema %s has no $id", path)
+		}
+		loader[meta.Id] = b
+		return nil
+	})
+	return loader, err
+}
+
+func mustLoadMetaSchemas(fsys fs.FS) resolve.MapLoader {
+	loader, err := LoadMetaSchemas(fsys)
+	if err != nil {
+		panic(err)
+	}
+	return loader
+}
+
+// MetaValidator validates schema documents against the meta-schema of their "$schema". It is safe for concurrent use.
+type MetaValidator struct {
+	Dialect      string         // meta-schema of documents without "$schema"
+	Vocabularies []string       // vocabularies meta-schemas may require through "$vocabulary"
+	Loader       resolve.Loader // loads meta-schemas by their URI
+
+	mu       sync.Mutex
+	resolver *resolve.Resolver
+	compiled map[string]*Schema // by URI
+}
+
+// Validate validates document against its meta-schema. document is either the JSON or YAML encoding of a schema, as
+// []byte or json.RawMessage, or a value normalized to one, see jsonschema.Normalize.
+//
+// The errors are located at the keywords of document, such as "/properties/name/minLength". Meta-schemas requiring a
+// vocabulary other than Vocabularies are rejected with an error.
+func (v *MetaValidator) Validate(document interface{}) error {
+	instance, err := decodeDocument(document)
+	if err != nil {
+		return err
+	}
+	uri := v.Dialect
+	if obj, ok := instance.(map[string]interface{}); ok {
+		if s, ok := obj["$schema"].(string); ok && s != "" {
+			uri = s
+		}
+	}
+	meta, err := v.metaSchema(uri)
+	if err != nil {
+		return err
+	}
+	return meta.Validate(instance)
+}
+
+// metaSchema returns the compiled meta-schema identified by uri.
+func (v *MetaValidator) metaSchema(uri string) (*Schema, error) {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	if s, ok := v.compiled[uri]; ok {
+		return s, nil
+	}
+	if v.resolver == nil {
+		v.resolver = resolve.New(v.Loader)
+		v.compiled = make(map[string]*Schema)
+	}
+	s, err := resolve.Ref[*Schema](resolve.WithScope(context.Background(), v.resolver, nil), uri)
+	if err != nil {
+		return nil, err
+	}
+	for _, vocabulary := range slices.Sorted(maps.Keys(s.Vocabulary)) {
+		if s.Vocabulary[vocabulary] && !slices.Contains(v.Vocabularies, vocabulary) {
+			return nil, fmt.Errorf("draft2020: meta-schema %q requires unknown vocabulary %q", uri, vocabulary)
+		}
+	}
+	if err := s.Compile(); err != nil {
+		return nil, err
+	}
+	v.compiled[uri] = s
+	return s, nil
+}
+
+func decodeDocument(document interface{}) (interface{}, error) {
+	var b []byte
+	switch d := document.(type) {
+	case []byte:
+		b = d
+	case json.RawMessage:
+		b = d
+	default:
+		return jsonschema.Normalize(document)
+	}
+	b, err := ser.ToJson(b)
+	if err != nil {
+		return nil, err
+	}
+	var v interface{}
+	if err := json.Unmarshal(b, &v); err != nil {
+		return nil, err
+	}
+	return v, nil
+}
+
+var metaValidator = &MetaValidator{Dialect: Dialect, Vocabularies: Vocabularies, Loader: MetaSchemas}
+
+// ValidateSchema validates document against the 2020-12 meta-schema, or the one of MetaSchemas identified by its
+// "$schema", see MetaValidator.Validate.
+func ValidateSchema(document interface{}) error {
+	return metaValidator.Validate(document)
+}
@@ -1,8 +1,21 @@
 package draft2020
 
 import (
+	"context"
 	"encoding/json"
+	"errors"
+	"fmt"
+	"maps"
+	"net/url"
+	"slices"
+	"sync"
 	"testing"
+
+	"github.com/MaiMee1/go-apispec/oas/internal/ecma"
+	"github.com/MaiMee1/go-apispec/oas/jsonpointer"
+	"github.com/MaiMee1/go-apispec/oas/jsonschema"
+	"github.com/MaiMee1/go-apispec/oas/jsonschema/internal/testsuite"
+	"github.com/MaiMee1/go-apispec/oas/resolve"
 )
 
 func TestSchema_UnmarshalJSON(t *testing.T) {
@@ -44,3 +57,747 @@ func TestSchema_MarshalJSON(t *testing.T) {
 	}
 	t.Log(string(b))
 }
+
+func TestSchema_comment(t *testing.T) {
+	var s Schema
+	data := `{"$comment":"names are compared case-insensitively","type":"string"}`
+	if err := json.Unmarshal([]byte(data), &s); err != nil {
+		t.Fatal(err)
+	}
+	if s.Comment != "names are compared case-insensitively" {
+		t.Errorf("got comment %q", s.Comment)
+	}
+	if !slices.Contains(slices.Collect(s.Keywords()), jsonschema.Keyword(&s.MetaSchemaMixin)) {
+		t.Error("$comment is not a keyword of the schema")
+	}
+	if slices.Contains(slices.Collect((&Schema{}).Keywords()), jsonschema.Keyword(&zero.MetaSchemaMixin)) {
+		t.Error("empty schema has core keywords")
+	}
+	// Act
+	b, err := json.Marshal(s)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(b) != data {
+		t.Errorf("got %s, want %s", b, data)
+	}
+	if err := s.Validate("cat"); err != nil {
+		t.Error(err)
+	}
+	if err := ValidateSchema([]byte(data)); err != nil {
+		t.Error(err)
+	}
+	if err := ValidateSchema([]byte(`{"$comment":1}`)); fmt.Sprint(err) != `"/$comment": value must be of type string, got number` {
+		t.Errorf("got error %v", err)
+	}
+}
+
+func TestSchema_UnmarshalJSON_integer(t *testing.T) {
+	tests := []struct {
+		data string
+		want string
+	}{
+		{`{"maxLength":2}`, "<nil>"},
+		{`{"maxLength":2.0}`, "<nil>"},
+		{`{"maxLength":2e0}`, "<nil>"},
+		{`{"maxLength":2.5}`, "jsonschema: cannot unmarshal 2.5 into Int, want an integer"},
+		{`{"maxLength":"2"}`, `jsonschema: cannot unmarshal "2" into Int, want an integer`},
+	}
+	for i, tt := range tests {
+		t.Run(fmt.Sprintf("%d", i), func(t *testing.T) {
+			var s Schema
+			// Act
+			err := json.Unmarshal([]byte(tt.data), &s)
+
+			if fmt.Sprint(err) != tt.want {
+				t.Errorf("got error %v, want %s", err, tt.want)
+			}
+			if err == nil && *s.MaxLength != 2 {
+				t.Errorf("got maxLength %d, want 2", *s.MaxLength)
+			}
+		})
+	}
+}
+
+func TestSchema_Validate_boolean(t *testing.T) {
+	pet := map[string]interface{}{"name": "Garfield"}
+	tests := []struct {
+		data     string
+		instance interface{}
+		want     string
+	}{
+		{`true`, pet, "<nil>"},
+		{`false`, pet, `"": value is not allowed by the schema false`},
+		{`{"properties":{"name":false}}`, pet, `"/name": value is not allowed by the schema false`},
+		{`{"items":false}`, []interface{}{1}, `"": array must not have items from index 0`},
+		{`{"not":true}`, pet, `"": value must not match the schema`},
+	}
+	for i, tt := range tests {
+		t.Run(fmt.Sprintf("%d", i), func(t *testing.T) {
+			var s Schema
+			if err := json.Unmarshal([]byte(tt.data), &s); err != nil {
+				t.Fatal(err)
+			}
+			// Act
+			err := s.Validate(tt.instance)
+
+			if fmt.Sprint(err) != tt.want {
+				t.Errorf("got error %v, want %s", err, tt.want)
+			}
+			b, err := json.Marshal(s)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if string(b) != tt.data {
+				t.Errorf("got %s, want %s", b, tt.data)
+			}
+		})
+	}
+}
+
+func TestSchema_Validate(t *testing.T) {
+	var s Schema
+	data := `{
+  "$id": "https://example.com/pet",
+  "type": "object",
+  "required": ["name", "id"],
+  "properties": {
+    "name": {"type": "string", "maxLength": 3},
+    "tags": {"type": "array", "items": {"type": "string"}, "uniqueItems": true}
+  },
+  "additionalProperties": false
+}`
+	if err := json.Unmarshal([]byte(data), &s); err != nil {
+		t.Fatal(err)
+	}
+	var instance interface{}
+	if err := json.Unmarshal([]byte(`{"name":"Garfield","tags":["cat",1,"cat"],"age":3}`), &instance); err != nil {
+		t.Fatal(err)
+	}
+	// Act
+	err := s.Validate(instance)
+	var errs jsonschema.ValidationErrors
+	if !errors.As(err, &errs) {
+		t.Fatalf("got %v, want ValidationErrors", err)
+	}
+	want := []jsonschema.ValidationError{
+		{KeywordLocation: "/required"},
+		{InstanceLocation: "/name", KeywordLocation: "/properties/name/maxLength"},
+		{InstanceLocation: "/tags", KeywordLocation: "/properties/tags/uniqueItems"},
+		{InstanceLocation: "/tags/1", KeywordLocation: "/properties/tags/items/type"},
+		{KeywordLocation: "/additionalProperties"},
+	}
+	if len(errs) != len(want) {
+		t.Fatalf("got %d errors, want %d:\n%v", len(errs), len(want), errs)
+	}
+	for i, e := range errs {
+		if e.InstanceLocation != want[i].InstanceLocation || e.KeywordLocation != want[i].KeywordLocation {
+			t.Errorf("got %q %q, want %q %q", e.InstanceLocation, e.KeywordLocation, want[i].InstanceLocation, want[i].KeywordLocation)
+		}
+		if e.AbsoluteKeywordLocation != "https://example.com/pet#"+string(e.KeywordLocation) {
+			t.Errorf("got absolute location %q", e.AbsoluteKeywordLocation)
+		}
+	}
+	if errs[0].Message != `object must have properties "id"` {
+		t.Errorf("got message %q", errs[0].Message)
+	}
+}
+
+type color string
+
+type pet struct {
+	Id    int64    `json:"id"`
+	Name  string   `json:"name"`
+	Color color    `json:"color,omitempty"`
+	Tags  []string `json:"tags,omitempty"`
+	Owner *pet     `json:"owner,omitempty"`
+	Born  birthday `json:"born"`
+	skip  bool
+}
+
+type birthday struct {
+	month, day int
+}
+
+func (b birthday) MarshalJSON() ([]byte, error) {
+	return json.Marshal(fmt.Sprintf("--%02d-%02d", b.month, b.day))
+}
+
+func TestSchema_Validate_goValues(t *testing.T) {
+	var s Schema
+	data := `{
+  "type": "object",
+  "required": ["id", "name", "born"],
+  "properties": {
+    "id": {"type": "integer", "minimum": 1},
+    "name": {"type": "string", "maxLength": 8},
+    "color": {"enum": ["black", "orange"]},
+    "tags": {"type": "array", "items": {"type": "string"}, "uniqueItems": true},
+    "owner": {"type": "object", "required": ["name"]},
+    "born": {"type": "string", "pattern": "^--\\d{2}-\\d{2}$"}
+  },
+  "additionalProperties": false
+}`
+	if err := json.Unmarshal([]byte(data), &s); err != nil {
+		t.Fatal(err)
+	}
+
+	var testCases = []struct {
+		schema   *Schema
+		instance interface{}
+		valid    bool
+	}{
+		{&s, pet{Id: 1, Name: "Garfield", Color: "orange", Tags: []string{"cat"}, Born: birthday{6, 19}}, true},
+		{&s, &pet{Id: 1, Name: "Garfield", Owner: &pet{Name: "Jon"}, skip: true}, true},
+		{&s, pet{Id: 0, Name: "Garfield"}, false},
+		{&s, pet{Id: 1, Name: "Garfield", Color: "white"}, false},
+		{&s, pet{Id: 1, Name: "Garfield", Tags: []string{"cat", "cat"}}, false},
+		{&s, map[string]interface{}{"id": json.Number("2"), "name": "Nermal", "born": "--01-01"}, true},
+		{&s, map[string]interface{}{"id": json.Number("2.5"), "name": "Nermal", "born": "--01-01"}, false},
+		{&s, map[string]interface{}{"id": 2, "name": color("Arlene"), "born": birthday{1, 1}}, true},
+		{&s, map[string]string{"id": "1"}, false},
+		{&s, (*pet)(nil), false},
+		{s.Properties["tags"], []string{"a", "b"}, true},
+		{s.Properties["tags"], []interface{}{[]int{1}, []interface{}{1.0}}, false},
+		{s.Properties["tags"], [2]color{"a", "a"}, false},
+	}
+	for i, tt := range testCases {
+		t.Run(fmt.Sprintf("%d", i), func(t *testing.T) {
+			// Act
+			err := tt.schema.Validate(tt.instance)
+
+			var errs jsonschema.ValidationErrors
+			if err != nil && !errors.As(err, &errs) {
+				t.Fatalf("got error %v, want ValidationErrors", err)
+			}
+			if (err == nil) != tt.valid {
+				t.Errorf("got valid %v, want %v: %v", err == nil, tt.valid, err)
+			}
+		})
+	}
+
+	t.Run("unsupported", func(t *testing.T) {
+		// Act
+		err := s.Validate(make(chan int))
+
+		var errs jsonschema.ValidationErrors
+		if err == nil || errors.As(err, &errs) {
+			t.Errorf("got %v, want an error other than ValidationErrors", err)
+		}
+	})
+}
+
+func TestMixins_Validate_goValues(t *testing.T) {
+	zero, one := jsonschema.Int(0), 1.0
+	var testCases = []struct {
+		keyword  jsonschema.Keyword
+		instance interface{}
+		valid    bool
+	}{
+		{&ArrayMixin[*Schema]{MinItems: 2}, []string{"a", "b"}, true},
+		{&ArrayMixin[*Schema]{MinItems: 2}, []string{"a"}, false},
+		{&ArrayMixin[*Schema]{UniqueItems: true}, []interface{}{[]int{1}, []interface{}{1.0}}, false},
+		{&ArrayMixin[*Schema]{MinItems: 2}, "a", true},
+		{&ObjectMixin[*Schema]{Required: []string{"name"}}, map[string]int{"id": 1}, false},
+		{&ObjectMixin[*Schema]{Required: []string{"name"}}, pet{Name: "Garfield"}, true},
+		{&ObjectMixin[*Schema]{Required: []string{"name"}}, []string{"name"}, true},
+		{&ObjectMixin[*Schema]{DependentRequired: map[string][]string{"owner": {"name"}}}, pet{Owner: &pet{}}, true},
+		{&ObjectMixin[*Schema]{DependentRequired: map[string][]string{"color": {"tags"}}}, pet{Color: "orange"}, false},
+		{&ObjectMixin[*Schema]{DependentSchemas: map[string]*Schema{"color": {ObjectMixin: ObjectMixin[*Schema]{Required: []string{"tags"}}}}}, pet{Color: "orange"}, false},
+		{&StringMixin{MinLength: 2}, color("a"), false},
+		{&StringMixin{MinLength: 2}, 1, true},
+		{&NumericMixin{Minimum: &one}, int8(0), false},
+		{&NumericMixin{Minimum: &one}, json.Number("1"), true},
+		{&NumericMixin{Minimum: &one}, "0", true},
+		{&ArrayMixin[*Schema]{MaxItems: &zero}, []string{}, true},
+		{&ArrayMixin[*Schema]{MaxItems: &zero}, []string{"a"}, false},
+		{&ObjectMixin[*Schema]{MaxProperties: &zero}, map[string]int{"id": 1}, false},
+		{&StringMixin{MaxLength: &zero}, "", true},
+		{&StringMixin{MaxLength: &zero}, "a", false},
+		{&ValidationMixin{Const: json.RawMessage(`[{"a":1}]`)}, []interface{}{map[string]int{"a": 1}}, true},
+		{&ValidationMixin{Const: json.RawMessage(`null`)}, nil, true},
+		{&ValidationMixin{Const: json.RawMessage(`null`)}, 0, false},
+		{&ValidationMixin{Enum: []interface{}{[]interface{}{"orange"}}}, map[string]interface{}{"x": 1}, false},
+		{&ValidationMixin{Enum: []interface{}{[]interface{}{"orange"}}}, []color{"orange"}, true},
+	}
+	for i, tt := range testCases {
+		t.Run(fmt.Sprintf("%d", i), func(t *testing.T) {
+			// Act
+			err := tt.keyword.Validate(tt.instance)
+
+			var errs jsonschema.ValidationErrors
+			if err != nil && !errors.As(err, &errs) {
+				t.Fatalf("got error %v, want ValidationErrors", err)
+			}
+			if (err == nil) != tt.valid {
+				t.Errorf("got valid %v, want %v: %v", err == nil, tt.valid, err)
+			}
+		})
+	}
+}
+
+func TestOutput_Format(t *testing.T) {
+	var s Schema
+	data := `{
+  "title": "pet",
+  "properties": {
+    "name": {"type": "string", "title": "name"},
+    "age": {"type": "integer", "minimum": 1}
+  }
+}`
+	if err := json.Unmarshal([]byte(data), &s); err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		instance string
+		format   jsonschema.OutputFormat
+		want     string
+	}{
+		{`{"age":0}`, jsonschema.FlagOutput, `{"valid":false}`},
+		{`{"age":0}`, jsonschema.BasicOutput, `{"valid":false,"keywordLocation":"","instanceLocation":"","errors":[` +
+			`{"valid":false,"keywordLocation":"/properties/age/minimum","instanceLocation":"/age","error":"number must be at least 1"}]}`},
+		{`{"age":0}`, jsonschema.DetailedOutput, `{"valid":false,"keywordLocation":"","instanceLocation":"","errors":[` +
+			`{"valid":false,"keywordLocation":"/properties/age/minimum","instanceLocation":"/age","error":"number must be at least 1"}]}`},
+		{`{"name":"Tom"}`, jsonschema.BasicOutput, `{"valid":true,"keywordLocation":"","instanceLocation":"","annotations":[` +
+			`{"valid":true,"keywordLocation":"/title","instanceLocation":"","annotation":"pet"},` +
+			`{"valid":true,"keywordLocation":"/properties","instanceLocation":"","annotation":["name"]},` +
+			`{"valid":true,"keywordLocation":"/properties/name/title","instanceLocation":"/name","annotation":"name"}]}`},
+		{`"Tom"`, jsonschema.VerboseOutput, `{"valid":true,"keywordLocation":"","instanceLocation":"","annotations":[` +
+			`{"valid":true,"keywordLocation":"/title","instanceLocation":"","annotation":"pet"}]}`},
+		{`{"age":0}`, jsonschema.VerboseOutput, `{"valid":false,"keywordLocation":"","instanceLocation":"","errors":[` +
+			`{"valid":true,"keywordLocation":"/title","instanceLocation":"","annotation":"pet"},` +
+			`{"valid":false,"keywordLocation":"/properties","instanceLocation":"","errors":[` +
+			`{"valid":false,"keywordLocation":"/properties/age","instanceLocation":"/age","errors":[` +
+			`{"valid":true,"keywordLocation":"/properties/age/type","instanceLocation":"/age"},` +
+			`{"valid":false,"keywordLocation":"/properties/age/minimum","instanceLocation":"/age","error":"number must be at least 1"}]}]}]}`},
+	}
+	for i, tt := range tests {
+		t.Run(fmt.Sprintf("%d", i), func(t *testing.T) {
+			var instance interface{}
+			if err := json.Unmarshal([]byte(tt.instance), &instance); err != nil {
+				t.Fatal(err)
+			}
+			units, err := s.Evaluate(instance)
+			if err != nil {
+				t.Fatal(err)
+			}
+			// Act
+			b, err := json.Marshal(units[0].Format(tt.format))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if string(b) != tt.want {
+				t.Errorf("got %s\nwant %s", b, tt.want)
+			}
+		})
+	}
+}
+
+func TestUnevaluatedMixin_Validate(t *testing.T) {
+	tests := []struct {
+		schema   string
+		instance string
+		want     []jsonpointer.Ptr
+	}{
+		{`{"allOf":[{"properties":{"a":{}}}],"properties":{"b":{}},"unevaluatedProperties":false}`, `{"a":1,"b":2}`, nil},
+		{`{"allOf":[{"properties":{"a":{}}}],"unevaluatedProperties":false}`, `{"a":1,"c":3}`, []jsonpointer.Ptr{"/unevaluatedProperties"}},
+		{`{"allOf":[{"properties":{"a":{"type":"string"}}}],"unevaluatedProperties":false}`, `{"a":1}`,
+			[]jsonpointer.Ptr{"/allOf/0/properties/a/type", "/unevaluatedProperties"}},
+		{`{"patternProperties":{"^x-":{}},"unevaluatedProperties":{"type":"integer"}}`, `{"x-a":"s","b":"s"}`,
+			[]jsonpointer.Ptr{"/unevaluatedProperties/type"}},
+		{`{"properties":{"a":{"unevaluatedProperties":false}}}`, `{"a":{"b":1}}`, []jsonpointer.Ptr{"/properties/a/unevaluatedProperties"}},
+		{`{"prefixItems":[{}],"unevaluatedItems":false}`, `[1]`, nil},
+		{`{"prefixItems":[{}],"unevaluatedItems":false}`, `[1,2]`, []jsonpointer.Ptr{"/unevaluatedItems"}},
+		{`{"allOf":[{"contains":{"type":"string"}}],"unevaluatedItems":{"type":"integer"}}`, `["a",1,2.5]`,
+			[]jsonpointer.Ptr{"/unevaluatedItems/type"}},
+		{`{"allOf":[{"items":true}],"unevaluatedItems":false}`, `[1,2]`, nil},
+	}
+	for i, tt := range tests {
+		t.Run(fmt.Sprintf("%d", i), func(t *testing.T) {
+			var s Schema
+			if err := json.Unmarshal([]byte(tt.schema), &s); err != nil {
+				t.Fatal(err)
+			}
+			var instance interface{}
+			if err := json.Unmarshal([]byte(tt.instance), &instance); err != nil {
+				t.Fatal(err)
+			}
+			// Act
+			err := s.Validate(instance)
+			var errs jsonschema.ValidationErrors
+			if err != nil && !errors.As(err, &errs) {
+				t.Fatal(err)
+			}
+			var got []jsonpointer.Ptr
+			for _, e := range errs {
+				got = append(got, e.KeywordLocation)
+			}
+			if !slices.Equal(got, tt.want) {
+				t.Errorf("got %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestApplicatorMixin_Validate(t *testing.T) {
+	testsuite.Run(t, "testdata", nil, func(b []byte) (testsuite.Validator, error) {
+		var s Schema
+		return &s, json.Unmarshal(b, &s)
+	})
+}
+
+func TestReferenceMixin_Validate(t *testing.T) {
+	// page is a generic container schema, with the item type left to the schema referring to it
+	var s Schema
+	data := `{
+  "$id": "https://example.com/pets",
+  "$ref": "page",
+  "$defs": {
+    "pet": {
+      "$dynamicAnchor": "item",
+      "type": "object",
+      "required": ["name"],
+      "properties": {"name": {"type": "string"}}
+    },
+    "page": {
+      "$id": "page",
+      "type": "object",
+      "required": ["items"],
+      "properties": {
+        "items": {"type": "array", "items": {"$dynamicRef": "#item"}},
+        "next": {"$ref": "#cursor"}
+      },
+      "$defs": {
+        "item": {"$dynamicAnchor": "item"},
+        "cursor": {"$anchor": "cursor", "type": "string", "minLength": 1}
+      }
+    }
+  }
+}`
+	if err := json.Unmarshal([]byte(data), &s); err != nil {
+		t.Fatal(err)
+	}
+	resolve.Standalone(&s, s.Id, MetaSchemas)
+	tests := []struct {
+		instance string
+		format   jsonschema.OutputFormat
+		want     string
+	}{
+		{`{"items":[{"name":"Tom"},{"name":"Garfield"}],"next":"abc"}`, jsonschema.FlagOutput, `{"valid":true}`},
+		{`{"items":[{"name":"Tom"},{"age":3}]}`, jsonschema.BasicOutput, `{"valid":false,"keywordLocation":"","instanceLocation":"","errors":[` +
+			`{"valid":false,"keywordLocation":"/$ref/properties/items/items/$dynamicRef/required","absoluteKeywordLocation":"https://example.com/pets#/$defs/pet/required","instanceLocation":"/items/1","error":"object must have properties \"name\""}]}`},
+		{`{"items":[],"next":""}`, jsonschema.BasicOutput, `{"valid":false,"keywordLocation":"","instanceLocation":"","errors":[` +
+			`{"valid":false,"keywordLocation":"/$ref/properties/next/$ref/minLength","absoluteKeywordLocation":"https://example.com/page#/$defs/cursor/minLength","instanceLocation":"/next","error":"string must have at least 1 characters, got 0"}]}`},
+	}
+	for i, tt := range tests {
+		t.Run(fmt.Sprintf("%d", i), func(t *testing.T) {
+			var instance interface{}
+			if err := json.Unmarshal([]byte(tt.instance), &instance); err != nil {
+				t.Fatal(err)
+			}
+			// Act
+			units, err := s.Evaluate(instance)
+			if err != nil {
+				t.Fatal(err)
+			}
+			b, err := json.Marshal(jsonschema.NewOutput("", units...).Format(tt.format))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if string(b) != tt.want {
+				t.Errorf("got %s\nwant %s", b, tt.want)
+			}
+		})
+	}
+}
+
+func TestReferenceMixin_Validate_cycle(t *testing.T) {
+	tests := []struct {
+		schema   string
+		instance string
+		want     string
+	}{
+		{`{"$defs":{"a":{"$ref":"#/$defs/a"}},"$ref":"#/$defs/a"}`, `1`, `$ref: "#/$defs/a" refers to #/$defs/a, which is being applied to the same instance`},
+		{`{"$defs":{"a":{"$ref":"#/$defs/b"},"b":{"allOf":[{"$ref":"#/$defs/a"}]}},"$ref":"#/$defs/a"}`, `{"a":[]}`, `$ref: "#/$defs/a" refers to #/$defs/a, which is being applied to the same instance`},
+		{`{"$id":"https://example.com/a","$dynamicAnchor":"a","$dynamicRef":"#a"}`, `[]`, `$dynamicRef: "#a" refers to https://example.com/a, which is being applied to the same instance`},
+		{`{"properties":{"a":{"$ref":"#"}},"items":{"$ref":"#"}}`, `{"a":{"a":[[1],{"a":1}]}}`, "<nil>"},
+		{`{"$defs":{"a":{"type":"integer"}},"allOf":[{"$ref":"#/$defs/a"},{"$ref":"#/$defs/a"}]}`, `1`, "<nil>"},
+	}
+	for i, tt := range tests {
+		for _, compiled := range []bool{false, true} {
+			t.Run(fmt.Sprintf("%d/compiled=%v", i, compiled), func(t *testing.T) {
+				var s Schema
+				if err := json.Unmarshal([]byte(tt.schema), &s); err != nil {
+					t.Fatal(err)
+				}
+				resolve.Standalone(&s, s.Id, MetaSchemas)
+				if compiled {
+					if err := s.Compile(); err != nil {
+						t.Fatal(err)
+					}
+				}
+				var instance interface{}
+				if err := json.Unmarshal([]byte(tt.instance), &instance); err != nil {
+					t.Fatal(err)
+				}
+				// Act
+				err := s.Validate(instance)
+
+				if fmt.Sprint(err) != tt.want {
+					t.Errorf("got error %v, want %s", err, tt.want)
+				}
+			})
+		}
+	}
+}
+
+func TestValidateSchema(t *testing.T) {
+	tests := []struct {
+		document  string
+		locations []string // of errors
+		err       bool     // other than jsonschema.ValidationErrors
+	}{
+		{`{"type":"object","required":["name"],"properties":{"name":{"type":"string","minLength":1}}}`, nil, false},
+		{"type: object\nproperties:\n  name:\n    minLength: -1\n", []string{"/properties/name/minLength"}, false},
+		{`{"required":"name"}`, []string{"/required"}, false},
+		{`{"type":"strin"}`, []string{"/type", "/type", "/type"}, false},
+		{`{"$defs":{"a":{"type":["string","string"]}}}`, []string{"/$defs/a/type", "/$defs/a/type", "/$defs/a/type"}, false},
+		{`{"items":{"anyOf":[]}}`, []string{"/items/anyOf"}, false},
+		{`{"$schema":"https://json-schema.org/draft/2020-12/schema","$anchor":"1a"}`, []string{"/$anchor"}, false},
+		{`{"$schema":"https://json-schema.org/draft/2020-12/meta/validation","minLength":-1,"anyOf":[]}`, []string{"/minLength"}, false},
+		{`{"$schema":"https://example.com/unknown"}`, nil, true},
+	}
+	for i, tt := range tests {
+		t.Run(fmt.Sprintf("%d", i), func(t *testing.T) {
+			// Act
+			err := ValidateSchema([]byte(tt.document))
+
+			var errs jsonschema.ValidationErrors
+			if !errors.As(err, &errs) {
+				if (err != nil) != tt.err || err != nil && len(tt.locations) > 0 {
+					t.Fatalf("got error %v", err)
+				}
+				return
+			}
+			var locations []string
+			for _, e := range errs {
+				locations = append(locations, string(e.InstanceLocation))
+			}
+			if !slices.Equal(locations, tt.locations) {
+				t.Errorf("got errors at %q, want %q: %v", locations, tt.locations, err)
+			}
+		})
+	}
+}
+
+func TestMetaValidator_Validate(t *testing.T) {
+	loader := maps.Clone(MetaSchemas)
+	loader["https://example.com/strict"] = []byte(`{
+  "$id": "https://example.com/strict",
+  "$dynamicAnchor": "meta",
+  "$vocabulary": {"https://json-schema.org/draft/2020-12/vocab/core": true},
+  "allOf": [{"$ref": "https://json-schema.org/draft/2020-12/schema"}],
+  "required": ["title"]
+}`)
+	loader["https://example.com/custom"] = []byte(`{
+  "$id": "https://example.com/custom",
+  "$vocabulary": {"https://example.com/vocab/custom": true}
+}`)
+	v := &MetaValidator{Dialect: "https://example.com/strict", Vocabularies: Vocabularies, Loader: loader}
+	tests := []struct {
+		document interface{}
+		want     string
+	}{
+		{json.RawMessage(`{"title":"pet","properties":{"name":{"title":"name"}}}`), "<nil>"},
+		{json.RawMessage(`{"title":"pet","properties":{"name":{}}}`), `"/properties/name": object must have properties "title"`},
+		{&Schema{MetaDataMixin: MetaDataMixin{Title: "pet"}, StringMixin: StringMixin{MinLength: -1}}, `"/minLength": number must be at least 0`},
+		{json.RawMessage(`{"$schema":"https://example.com/custom"}`), `draft2020: meta-schema "https://example.com/custom" requires unknown vocabulary "https://example.com/vocab/custom"`},
+	}
+	for i, tt := range tests {
+		t.Run(fmt.Sprintf("%d", i), func(t *testing.T) {
+			// Act
+			err := v.Validate(tt.document)
+
+			if fmt.Sprint(err) != tt.want {
+				t.Errorf("got error %v, want %s", err, tt.want)
+			}
+		})
+	}
+}
+
+// remotes loads the remote documents of the suite along with the MetaSchemas.
+var remotes = testsuite.Remotes("../testdata/JSON-Schema-Test-Suite/remotes", MetaSchemas)
+
+func TestSuite(t *testing.T) {
+	for _, compiled := range []bool{false, true} {
+		t.Run(fmt.Sprintf("compiled=%v", compiled), func(t *testing.T) {
+			testsuite.Run(t, "../testdata/JSON-Schema-Test-Suite/tests/draft2020-12", nil, func(b []byte) (testsuite.Validator, error) {
+				var s Schema
+				if err := json.Unmarshal(b, &s); err != nil {
+					return &s, err
+				}
+				resolve.Standalone(&s, s.Id, remotes)
+				if !compiled {
+					return &s, nil
+				}
+				return &s, s.Compile()
+			})
+		})
+	}
+}
+
+func TestSuite_format(t *testing.T) {
+	testsuite.Run(t, "../testdata/JSON-Schema-Test-Suite/tests/draft2020-12/optional/format", nil, func(b []byte) (testsuite.Validator, error) {
+		var s Schema
+		if err := json.Unmarshal(b, &s); err != nil {
+			return &s, err
+		}
+		return jsonschema.Compile(&s, jsonschema.WithFormatAssertion(nil))
+	})
+}
+
+func TestSuite_optional(t *testing.T) {
+	for _, compiled := range []bool{false, true} {
+		t.Run(fmt.Sprintf("compiled=%v", compiled), func(t *testing.T) {
+			testsuite.Run(t, "../testdata/JSON-Schema-Test-Suite/tests/draft2020-12/optional", nil, func(b []byte) (testsuite.Validator, error) {
+				var s Schema
+				if err := json.Unmarshal(b, &s); err != nil || !compiled {
+					return &s, err
+				}
+				return &s, s.Compile()
+			})
+		})
+	}
+}
+
+func TestCompile(t *testing.T) {
+	var s Schema
+	data := `{
+  "$id": "https://example.com/tree",
+  "type": "object",
+  "required": ["name"],
+  "properties": {
+    "name": {"type": "string", "pattern": "^[a-z]+$"},
+    "children": {"type": "array", "items": {"$ref": "#"}}
+  }
+}`
+	if err := json.Unmarshal([]byte(data), &s); err != nil {
+		t.Fatal(err)
+	}
+	base, _ := url.Parse("https://example.com/tree")
+	r := resolve.New(nil)
+	r.Register(base, &s)
+	resolve.Bind(&s, resolve.WithScope(context.TODO(), r, base))
+	// Act
+	v, err := jsonschema.Compile(&s)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if ref := s.Properties["children"].Items.Y.resolved; ref == nil || *ref != &s {
+		t.Errorf("got $ref resolved to %v, want the root schema", ref)
+	}
+	var instance interface{}
+	if err := json.Unmarshal([]byte(`{"name":"root","children":[{"name":"leaf"}]}`), &instance); err != nil {
+		t.Fatal(err)
+	}
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if err := v.Validate(instance); err != nil {
+				t.Error(err)
+			}
+		}()
+	}
+	wg.Wait()
+}
+
+func TestCompile_pattern(t *testing.T) {
+	tests := []struct {
+		schema string
+		want   string
+	}{
+		{`{"pattern": "^[a-z]+$"}`, ``},
+		{`{"pattern": "("}`, "pattern: pattern \"(\" failed to compile: ecma: invalid regular expression `(`: at 1: missing )"},
+		{`{"patternProperties": {"\\a": {}}}`,
+			"patternProperties: pattern \"\\\\a\" failed to compile: ecma: invalid regular expression `\\a`: at 2: invalid escape \\a"},
+	}
+	for i, tt := range tests {
+		t.Run(fmt.Sprintf("%d", i), func(t *testing.T) {
+			var s Schema
+			if err := json.Unmarshal([]byte(tt.schema), &s); err != nil {
+				t.Fatal(err)
+			}
+			// Act
+			_, err := jsonschema.Compile(&s)
+			var got string
+			if err != nil {
+				got = err.Error()
+			}
+			if got != tt.want {
+				t.Errorf("got %s, want %s", got, tt.want)
+			}
+			var syntaxErr *ecma.SyntaxError
+			if err != nil && !errors.As(err, &syntaxErr) {
+				t.Errorf("got error %T, want it to wrap *ecma.SyntaxError", err)
+			}
+		})
+	}
+}
+
+// benchmarkSchema is a schema of a request body with patterns and subschemas.
+const benchmarkSchema = `{
+  "type": "object",
+  "required": ["id", "name", "tags"],
+  "properties": {
+    "id": {"type": "string", "pattern": "^[0-9a-f]{8}-[0-9a-f]{4}-[0-9a-f]{4}-[0-9a-f]{4}-[0-9a-f]{12}$"},
+    "name": {"type": "string", "minLength": 1, "maxLength": 64},
+    "tags": {"type": "array", "items": {"type": "string", "pattern": "^[a-z][a-z0-9-]*$"}, "uniqueItems": true},
+    "price": {"type": "number", "minimum": 0, "multipleOf": 0.01}
+  },
+  "patternProperties": {"^x-": {"type": "string"}},
+  "additionalProperties": false
+}`
+
+const benchmarkInstance = `{
+  "id": "2eb8aa08-aa98-11ea-b4aa-73b441d16380",
+  "name": "Garfield",
+  "tags": ["cat", "orange", "lasagna-lover"],
+  "price": 19.99,
+  "x-source": "comics"
+}`
+
+func benchmarkValidate(b *testing.B, compile bool) {
+	var s Schema
+	if err := json.Unmarshal([]byte(benchmarkSchema), &s); err != nil {
+		b.Fatal(err)
+	}
+	validate := s.Validate
+	if compile {
+		v, err := jsonschema.Compile(&s)
+		if err != nil {
+			b.Fatal(err)
+		}
+		validate = v.Validate
+	}
+	var instance interface{}
+	if err := json.Unmarshal([]byte(benchmarkInstance), &instance); err != nil {
+		b.Fatal(err)
+	}
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if err := validate(instance); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkSchema_Validate(b *testing.B) {
+	benchmarkValidate(b, false)
+}
+
+func BenchmarkValidator_Validate(b *testing.B) {
+	benchmarkValidate(b, true)
+}
@@ -1,6 +1,12 @@
 package draft2020
 
 import (
+	"context"
+	"maps"
+	"slices"
+	"strconv"
+	"strings"
+
 	"github.com/MaiMee1/go-apispec/oas/jsonschema"
 	"github.com/MaiMee1/go-apispec/oas/ser"
 )
@@ -18,7 +24,149 @@ func (m *UnevaluatedMixin[S]) AppliesTo(t jsonschema.Type) bool {
 	return t.Has(jsonschema.ObjectType | jsonschema.ArrayType)
 }
 
+// Compile compiles the subschemas.
+func (m *UnevaluatedMixin[S]) Compile() error {
+	for _, or := range []*ser.Or[bool, S]{m.UnevaluatedItems, m.UnevaluatedProperties} {
+		if or == nil {
+			continue
+		}
+		if err := compile(or.Y); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Validate validates v as if no adjacent keyword evaluates any item or property.
 func (m *UnevaluatedMixin[S]) Validate(v interface{}) error {
-	//TODO implement me
-	panic("implement me")
+	return jsonschema.Errors(m.EvaluateAdjacent(context.Background(), v, nil))
+}
+
+// EvaluateAdjacent applies the subschemas to the items or properties of v not evaluated by the adjacent units,
+// including units of subschemas applied to v itself, such as by allOf.
+func (m *UnevaluatedMixin[S]) EvaluateAdjacent(ctx context.Context, v interface{}, adjacent []*jsonschema.Output) ([]*jsonschema.Output, error) {
+	switch v := v.(type) {
+	case []interface{}:
+		if m.UnevaluatedItems == nil {
+			return nil, nil
+		}
+		evaluated := evaluatedItems(adjacent, len(v))
+		var indices []string
+		var nested []*jsonschema.Output
+		for i, e := range v {
+			if evaluated[i] {
+				continue
+			}
+			index := strconv.Itoa(i)
+			indices = append(indices, index)
+			if !isNil(m.UnevaluatedItems.Y) {
+				u, err := jsonschema.EvaluateContext(ctx, m.UnevaluatedItems.Y, e)
+				if err != nil {
+					return nil, err
+				}
+				nested = append(nested, jsonschema.Prefix(u, index, "unevaluatedItems")...)
+			}
+		}
+		unit := jsonschema.NewOutput("unevaluatedItems", nested...)
+		if isNil(m.UnevaluatedItems.Y) && !m.UnevaluatedItems.X && len(indices) > 0 {
+			unit.Fail(strings.Join(indices, ", "))
+		}
+		if len(indices) > 0 {
+			unit.Annotate(true)
+		}
+		return []*jsonschema.Output{unit}, nil
+	case map[string]interface{}:
+		if m.UnevaluatedProperties == nil {
+			return nil, nil
+		}
+		evaluated := evaluatedProperties(adjacent)
+		names := make([]string, 0)
+		var nested []*jsonschema.Output
+		for _, k := range slices.Sorted(maps.Keys(v)) {
+			if evaluated[k] {
+				continue
+			}
+			names = append(names, k)
+			if !isNil(m.UnevaluatedProperties.Y) {
+				u, err := jsonschema.EvaluateContext(ctx, m.UnevaluatedProperties.Y, v[k])
+				if err != nil {
+					return nil, err
+				}
+				nested = append(nested, jsonschema.Prefix(u, k, "unevaluatedProperties")...)
+			}
+		}
+		unit := jsonschema.NewOutput("unevaluatedProperties", nested...)
+		if isNil(m.UnevaluatedProperties.Y) && !m.UnevaluatedProperties.X && len(names) > 0 {
+			quoted := make([]string, len(names))
+			for i, k := range names {
+				quoted[i] = strconv.Quote(k)
+			}
+			unit.Fail(strings.Join(quoted, ", "))
+		}
+		return []*jsonschema.Output{unit.Annotate(names)}, nil
+	default:
+		return nil, nil
+	}
+}
+
+// sameInstance calls fn with the valid units evaluated at the same instance location as the units, that is units
+// of keywords and of subschemas applied in place, such as by allOf or $ref.
+func sameInstance(units []*jsonschema.Output, fn func(u *jsonschema.Output)) {
+	for _, u := range units {
+		if !u.Valid || u.InstanceLocation != "" {
+			continue
+		}
+		fn(u)
+		sameInstance(u.Units(), fn)
+	}
+}
+
+// evaluatedProperties returns the names of properties annotated by properties, patternProperties,
+// additionalProperties and unevaluatedProperties in units.
+func evaluatedProperties(units []*jsonschema.Output) map[string]bool {
+	evaluated := make(map[string]bool)
+	sameInstance(units, func(u *jsonschema.Output) {
+		switch u.Keyword() {
+		case "properties", "patternProperties", "additionalProperties", "unevaluatedProperties":
+			if names, ok := u.Annotation.([]string); ok {
+				for _, name := range names {
+					evaluated[name] = true
+				}
+			}
+		}
+	})
+	return evaluated
+}
+
+// evaluatedItems returns which of n items are annotated by prefixItems, items, contains and unevaluatedItems in
+// units.
+func evaluatedItems(units []*jsonschema.Output, n int) []bool {
+	evaluated := make([]bool, n)
+	all := func() {
+		for i := range evaluated {
+			evaluated[i] = true
+		}
+	}
+	sameInstance(units, func(u *jsonschema.Output) {
+		switch u.Keyword() {
+		case "prefixItems", "items", "unevaluatedItems":
+			switch annotation := u.Annotation.(type) {
+			case bool:
+				if annotation {
+					all()
+				}
+			case int:
+				for i := 0; i <= annotation && i < n; i++ {
+					evaluated[i] = true
+				}
+			}
+		case "contains":
+			if indices, ok := u.Annotation.([]int); ok {
+				for _, i := range indices {
+					evaluated[i] = true
+				}
+			}
+		}
+	})
+	return evaluated
 }
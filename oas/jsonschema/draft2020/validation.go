@@ -1,31 +1,31 @@
 package draft2020
 
 import (
-	"errors"
+	"context"
+	"encoding/json"
 	"fmt"
 	"maps"
+	"math"
 	"reflect"
-	"regexp"
 	"slices"
 	"strconv"
 	"strings"
+	"unicode/utf8"
 
-	"github.com/MaiMee1/go-apispec/oas/internal/validate"
+	"github.com/MaiMee1/go-apispec/oas/internal/ecma"
 	"github.com/MaiMee1/go-apispec/oas/jsonschema"
 	"github.com/MaiMee1/go-apispec/oas/ser"
 )
 
-const validateTagSep = ','
-
 type ArrayMixin[S jsonschema.Keyword] struct {
-	MaxItems    int              `json:"maxItems,omitempty" validate:"omitempty,gte=0"`
-	MinItems    int              `json:"minItems,omitempty" validate:"omitempty,gte=0"`
+	MaxItems    *jsonschema.Int  `json:"maxItems,omitempty" validate:"omitempty,gte=0"`
+	MinItems    jsonschema.Int   `json:"minItems,omitempty" validate:"omitempty,gte=0"`
 	UniqueItems bool             `json:"uniqueItems,omitempty"`
 	PrefixItems []S              `json:"prefixItems,omitempty"`
 	Items       *ser.Or[bool, S] `json:"items,omitempty"`
 	Contains    S                `json:"contains,omitempty"`
-	MaxContains int              `json:"maxContains,omitempty" validate:"omitempty,gte=0"`
-	MinContains *int             `json:"minContains,omitempty" validate:"omitempty,gte=0"`
+	MaxContains *jsonschema.Int  `json:"maxContains,omitempty" validate:"omitempty,gte=0"`
+	MinContains *jsonschema.Int  `json:"minContains,omitempty" validate:"omitempty,gte=0"`
 }
 
 func (m *ArrayMixin[S]) Kind() jsonschema.Kind {
@@ -36,107 +36,152 @@ func (m *ArrayMixin[S]) AppliesTo(t jsonschema.Type) bool {
 	return t.Has(jsonschema.ArrayType)
 }
 
-func (m *ArrayMixin[S]) Validate(v interface{}) error {
-	arr := v.([]interface{})
-	if err := validate.Var(arr, m.validateTag()); err != nil {
+// Compile compiles the subschemas.
+func (m *ArrayMixin[S]) Compile() error {
+	if err := compile(m.PrefixItems...); err != nil {
 		return err
 	}
-	if err := m.checkItems(arr); err != nil {
-		return err
-	}
-	if err := m.checkContains(arr); err != nil {
-		return err
+	if m.Items != nil {
+		if err := compile(m.Items.Y); err != nil {
+			return err
+		}
 	}
-	return nil
+	return compile(m.Contains)
+}
+
+func (m *ArrayMixin[S]) Validate(v interface{}) error {
+	return jsonschema.Errors(m.Evaluate(v))
 }
 
-func (m *ArrayMixin[S]) validateTag() string {
-	b := strings.Builder{}
-	if m.MaxItems != 0 {
-		b.WriteString("max=")
-		b.WriteString(strconv.Itoa(m.MaxItems))
-		b.WriteRune(validateTagSep)
+func (m *ArrayMixin[S]) Evaluate(v interface{}) ([]*jsonschema.Output, error) {
+	return m.EvaluateContext(context.Background(), v)
+}
+
+func (m *ArrayMixin[S]) EvaluateContext(ctx context.Context, v interface{}) ([]*jsonschema.Output, error) {
+	arr, ok, err := instanceOf[[]interface{}](v)
+	if !ok {
+		return nil, err
+	}
+	var units []*jsonschema.Output
+	if m.MaxItems != nil {
+		units = append(units, assert("maxItems", len(arr) <= int(*m.MaxItems), strconv.Itoa(int(*m.MaxItems)), strconv.Itoa(len(arr))))
 	}
 	if m.MinItems != 0 {
-		b.WriteString("min=")
-		b.WriteString(strconv.Itoa(m.MinItems))
-		b.WriteRune(validateTagSep)
+		units = append(units, assert("minItems", len(arr) >= int(m.MinItems), strconv.Itoa(int(m.MinItems)), strconv.Itoa(len(arr))))
 	}
 	if m.UniqueItems {
-		b.WriteString("unique")
-		b.WriteRune(validateTagSep)
-	}
-	return b.String()
-}
-
-func (m *ArrayMixin[S]) checkItems(arr []interface{}) error {
-	var additionalItemIsValid *bool
-	t := true
-	f := false
-	hasItems := m.Items != nil
-	if hasItems {
-		if reflect.DeepEqual(m.Items.Y, nil) {
-			if m.Items.X {
-				additionalItemIsValid = &t
-			} else {
-				additionalItemIsValid = &f
+		units = append(units, m.checkUnique(arr))
+	}
+	items, err := m.checkItems(ctx, arr)
+	if err != nil {
+		return nil, err
+	}
+	units = append(units, items...)
+	contains, err := m.checkContains(ctx, arr)
+	if err != nil {
+		return nil, err
+	}
+	units = append(units, contains...)
+	return units, nil
+}
+
+func (m *ArrayMixin[S]) checkUnique(arr []interface{}) *jsonschema.Output {
+	for i := range arr {
+		for j := i + 1; j < len(arr); j++ {
+			if equal(arr[i], arr[j]) {
+				return assert("uniqueItems", false, strconv.Itoa(i), strconv.Itoa(j))
 			}
 		}
-	} else {
-		additionalItemIsValid = &t
 	}
+	return assert("uniqueItems", true)
+}
 
-	for i, e := range arr {
-		if len(m.PrefixItems) > i {
-			if err := m.PrefixItems[i].Validate(e); err != nil {
-				return fmt.Errorf("prefixItems: invalid item at index %d: %w", i, err)
+func (m *ArrayMixin[S]) checkItems(ctx context.Context, arr []interface{}) ([]*jsonschema.Output, error) {
+	var units []*jsonschema.Output
+	if len(m.PrefixItems) > 0 {
+		n := min(len(arr), len(m.PrefixItems))
+		var nested []*jsonschema.Output
+		for i, e := range arr[:n] {
+			index := strconv.Itoa(i)
+			u, err := jsonschema.EvaluateContext(ctx, m.PrefixItems[i], e)
+			if err != nil {
+				return nil, err
 			}
+			nested = append(nested, jsonschema.Prefix(u, index, "prefixItems", index)...)
+		}
+		unit := jsonschema.NewOutput("prefixItems", nested...)
+		// the largest index to which a subschema is applied, or true if applied to every index
+		if n == len(arr) {
+			unit.Annotate(true)
+		} else if n > 0 {
+			unit.Annotate(n - 1)
+		}
+		units = append(units, unit)
+	}
+	if m.Items != nil {
+		start := min(len(arr), len(m.PrefixItems))
+		var unit *jsonschema.Output
+		if isNil(m.Items.Y) {
+			unit = assert("items", m.Items.X || start == len(arr), strconv.Itoa(start))
 		} else {
-			if additionalItemIsValid == nil {
-				if err := m.Items.Y.Validate(e); err != nil {
-					return fmt.Errorf("items: invalid item at index %d: %w", i, err)
-				}
-			} else {
-				if *additionalItemIsValid {
-					continue
-				} else {
-					return fmt.Errorf("items: items from index %d is invalid", i)
+			var nested []*jsonschema.Output
+			for i, e := range arr[start:] {
+				u, err := jsonschema.EvaluateContext(ctx, m.Items.Y, e)
+				if err != nil {
+					return nil, err
 				}
+				nested = append(nested, jsonschema.Prefix(u, strconv.Itoa(start+i), "items")...)
 			}
+			unit = jsonschema.NewOutput("items", nested...)
 		}
+		if start < len(arr) {
+			unit.Annotate(true)
+		}
+		units = append(units, unit)
 	}
-	return nil
+	return units, nil
 }
 
-func (m *ArrayMixin[S]) checkContains(arr []interface{}) error {
-	if reflect.DeepEqual(m.Contains, nil) {
-		return nil
+func (m *ArrayMixin[S]) checkContains(ctx context.Context, arr []interface{}) ([]*jsonschema.Output, error) {
+	if isNil(m.Contains) {
+		return nil, nil
 	}
-	found := 0
-	for _, e := range arr {
-		if m.Contains.Validate(e) == nil {
-			found++
+	var nested []*jsonschema.Output
+	matched := make([]int, 0)
+	for i, e := range arr {
+		u, err := jsonschema.EvaluateContext(ctx, m.Contains, e)
+		if err != nil {
+			return nil, err
+		}
+		if valid(u) {
+			matched = append(matched, i)
 		}
+		nested = append(nested, jsonschema.Prefix(u, strconv.Itoa(i), "contains")...)
 	}
-	wantMin := 1 // default MinContains is 1: https://json-schema.org/draft/2020-12/json-schema-validation#section-6.4.5
-	if m.MinContains != nil {
-		wantMin = *m.MinContains
+
+	unit := jsonschema.NewOutput("contains", nested...)
+	// minContains replaces the default of at least 1 matching item: https://json-schema.org/draft/2020-12/json-schema-validation#section-6.4.5
+	if len(matched) > 0 || m.MinContains != nil {
+		unit.Pass().Annotate(matched)
+	} else {
+		unit.Fail()
 	}
-	if wantMin > found {
-		return fmt.Errorf("contains: found %d matching items, want at least %d", found, wantMin)
+	units := []*jsonschema.Output{unit}
+	if m.MinContains != nil {
+		units = append(units, assert("minContains", len(matched) >= int(*m.MinContains), strconv.Itoa(int(*m.MinContains)), strconv.Itoa(len(matched))))
 	}
-	if m.MaxContains != 0 && found > m.MaxContains {
-		return fmt.Errorf("contains: found %d matching items, want at most %d", found, m.MaxContains)
+	if m.MaxContains != nil {
+		units = append(units, assert("maxContains", len(matched) <= int(*m.MaxContains), strconv.Itoa(int(*m.MaxContains)), strconv.Itoa(len(matched))))
 	}
-	return nil
+	return units, nil
 }
 
 type NumericMixin struct {
-	MultipleOf       int  `json:"multipleOf,omitempty" validate:"omitempty,gt=0"`
-	Maximum          int  `json:"maximum,omitempty"`
-	ExclusiveMaximum bool `json:"exclusiveMaximum,omitempty"`
-	Minimum          int  `json:"minimum,omitempty"`
-	ExclusiveMinimum bool `json:"exclusiveMinimum,omitempty"`
+	MultipleOf       *float64 `json:"multipleOf,omitempty" validate:"omitempty,gt=0"`
+	Maximum          *float64 `json:"maximum,omitempty"`
+	ExclusiveMaximum *float64 `json:"exclusiveMaximum,omitempty"`
+	Minimum          *float64 `json:"minimum,omitempty"`
+	ExclusiveMinimum *float64 `json:"exclusiveMinimum,omitempty"`
 }
 
 func (m *NumericMixin) Kind() jsonschema.Kind {
@@ -148,45 +193,62 @@ func (m *NumericMixin) AppliesTo(t jsonschema.Type) bool {
 }
 
 func (m *NumericMixin) Validate(v interface{}) error {
-	return validate.Var(v, m.validateTag())
+	return jsonschema.Errors(m.Evaluate(v))
 }
 
-func (m *NumericMixin) validateTag() string {
-	b := strings.Builder{}
-	if m.MultipleOf != 0 {
-		b.WriteString("multipleOf=")
-		b.WriteString(strconv.Itoa(m.MultipleOf))
-		b.WriteRune(validateTagSep)
+func (m *NumericMixin) Evaluate(v interface{}) ([]*jsonschema.Output, error) {
+	v, err := jsonschema.Normalize(v)
+	if err != nil {
+		return nil, err
 	}
-	if m.Maximum != 0 {
-		b.WriteString("lt")
-		if !m.ExclusiveMaximum {
-			b.WriteRune('e')
-		}
-		b.WriteRune('=')
-		b.WriteString(strconv.Itoa(m.Maximum))
-		b.WriteRune(validateTagSep)
-	}
-	if m.Minimum != 0 {
-		b.WriteString("gt")
-		if !m.ExclusiveMinimum {
-			b.WriteRune('e')
-		}
-		b.WriteRune('=')
-		b.WriteString(strconv.Itoa(m.Minimum))
-		b.WriteRune(validateTagSep)
+	x, ok := number(v)
+	if !ok {
+		return nil, nil
+	}
+	var units []*jsonschema.Output
+	if m.MultipleOf != nil {
+		units = append(units, assert("multipleOf", isMultipleOf(x, *m.MultipleOf), formatFloat(*m.MultipleOf)))
+	}
+	if m.Maximum != nil {
+		units = append(units, assert("maximum", x <= *m.Maximum, formatFloat(*m.Maximum)))
+	}
+	if m.ExclusiveMaximum != nil {
+		units = append(units, assert("exclusiveMaximum", x < *m.ExclusiveMaximum, formatFloat(*m.ExclusiveMaximum)))
+	}
+	if m.Minimum != nil {
+		units = append(units, assert("minimum", x >= *m.Minimum, formatFloat(*m.Minimum)))
+	}
+	if m.ExclusiveMinimum != nil {
+		units = append(units, assert("exclusiveMinimum", x > *m.ExclusiveMinimum, formatFloat(*m.ExclusiveMinimum)))
+	}
+	return units, nil
+}
+
+// isMultipleOf reports whether x divided by y is an integer, allowing for the precision of decimal fractions.
+func isMultipleOf(x, y float64) bool {
+	q := x / y
+	if math.IsInf(q, 0) {
+		return false
 	}
-	return b.String()
+	return math.Abs(q-math.Round(q)) <= 1e-9*math.Max(1, math.Abs(q))
+}
+
+func formatFloat(f float64) string {
+	return strconv.FormatFloat(f, 'g', -1, 64)
 }
 
 type ObjectMixin[S jsonschema.Keyword] struct {
-	MaxProperties        int              `json:"maxProperties,omitempty" validate:"omitempty,gte=0"`
-	MinProperties        int              `json:"minProperties,omitempty" validate:"omitempty,gte=0"`
-	PropertyNames        S                `json:"propertyNames,omitempty" validate:"omitempty,dive"`
-	Required             []string         `json:"required,omitempty" validate:"omitempty,min=1,unique"`
-	Properties           map[string]S     `json:"properties,omitempty" validate:"dive"`
-	PatternProperties    map[string]S     `json:"patternProperties,omitempty" validate:"dive,keys,regex,endkeys"`
-	AdditionalProperties *ser.Or[bool, S] `json:"additionalProperties,omitempty"`
+	MaxProperties        *jsonschema.Int     `json:"maxProperties,omitempty" validate:"omitempty,gte=0"`
+	MinProperties        jsonschema.Int      `json:"minProperties,omitempty" validate:"omitempty,gte=0"`
+	PropertyNames        S                   `json:"propertyNames,omitempty" validate:"omitempty,dive"`
+	Required             []string            `json:"required,omitempty" validate:"omitempty,min=1,unique"`
+	Properties           map[string]S        `json:"properties,omitempty" validate:"dive"`
+	PatternProperties    map[string]S        `json:"patternProperties,omitempty" validate:"dive,keys,regex,endkeys"`
+	AdditionalProperties *ser.Or[bool, S]    `json:"additionalProperties,omitempty"`
+	DependentRequired    map[string][]string `json:"dependentRequired,omitempty" validate:"dive,unique"`
+	DependentSchemas     map[string]S        `json:"dependentSchemas,omitempty" validate:"dive"`
+
+	patterns map[string]*ecma.Regexp // compiled PatternProperties
 }
 
 func (m *ObjectMixin[S]) Kind() jsonschema.Kind {
@@ -197,90 +259,215 @@ func (m *ObjectMixin[S]) AppliesTo(t jsonschema.Type) bool {
 	return t.Has(jsonschema.ObjectType)
 }
 
-func (m *ObjectMixin[S]) Validate(v interface{}) error {
-	obj := v.(map[string]interface{})
-	if err := validate.Var(obj, m.validateTag()); err != nil {
-		return err
+// Compile compiles the patterns of PatternProperties and the subschemas.
+func (m *ObjectMixin[S]) Compile() error {
+	m.patterns = make(map[string]*ecma.Regexp, len(m.PatternProperties))
+	for pattern, s := range m.PatternProperties {
+		re, err := ecma.Compile(pattern)
+		if err != nil {
+			return fmt.Errorf("patternProperties: pattern %q failed to compile: %w", pattern, err)
+		}
+		m.patterns[pattern] = re
+		if err := compile(s); err != nil {
+			return err
+		}
 	}
-	if err := m.validateProperties(obj); err != nil {
-		return err
+	for _, s := range m.Properties {
+		if err := compile(s); err != nil {
+			return err
+		}
 	}
-	return nil
+	for _, s := range m.DependentSchemas {
+		if err := compile(s); err != nil {
+			return err
+		}
+	}
+	if m.AdditionalProperties != nil {
+		if err := compile(m.AdditionalProperties.Y); err != nil {
+			return err
+		}
+	}
+	return compile(m.PropertyNames)
+}
+
+func (m *ObjectMixin[S]) Validate(v interface{}) error {
+	return jsonschema.Errors(m.Evaluate(v))
+}
+
+func (m *ObjectMixin[S]) Evaluate(v interface{}) ([]*jsonschema.Output, error) {
+	return m.EvaluateContext(context.Background(), v)
 }
 
-func (m *ObjectMixin[S]) validateTag() string {
-	b := strings.Builder{}
-	if m.MaxProperties != 0 {
-		b.WriteString("max=")
-		b.WriteString(strconv.Itoa(m.MaxProperties))
-		b.WriteRune(validateTagSep)
+func (m *ObjectMixin[S]) EvaluateContext(ctx context.Context, v interface{}) ([]*jsonschema.Output, error) {
+	obj, ok, err := instanceOf[map[string]interface{}](v)
+	if !ok {
+		return nil, err
+	}
+	var units []*jsonschema.Output
+	if m.MaxProperties != nil {
+		units = append(units, assert("maxProperties", len(obj) <= int(*m.MaxProperties), strconv.Itoa(int(*m.MaxProperties)), strconv.Itoa(len(obj))))
 	}
 	if m.MinProperties != 0 {
-		b.WriteString("min=")
-		b.WriteString(strconv.Itoa(m.MinProperties))
-		b.WriteRune(validateTagSep)
+		units = append(units, assert("minProperties", len(obj) >= int(m.MinProperties), strconv.Itoa(int(m.MinProperties)), strconv.Itoa(len(obj))))
+	}
+	if len(m.Required) > 0 {
+		var missing []string
+		for _, req := range m.Required {
+			if _, found := obj[req]; !found {
+				missing = append(missing, strconv.Quote(req))
+			}
+		}
+		units = append(units, assert("required", len(missing) == 0, strings.Join(missing, ", ")))
+	}
+	units = append(units, m.checkDependentRequired(obj)...)
+	properties, err := m.evaluateProperties(ctx, obj)
+	if err != nil {
+		return nil, err
+	}
+	units = append(units, properties...)
+	dependent, err := m.evaluateDependentSchemas(ctx, obj)
+	if err != nil {
+		return nil, err
+	}
+	return append(units, dependent...), nil
+}
+
+// checkDependentRequired requires the properties listed for each property of obj in DependentRequired.
+func (m *ObjectMixin[S]) checkDependentRequired(obj map[string]interface{}) []*jsonschema.Output {
+	var units []*jsonschema.Output
+	for _, name := range slices.Sorted(maps.Keys(m.DependentRequired)) {
+		if _, found := obj[name]; !found {
+			continue
+		}
+		var missing []string
+		for _, req := range m.DependentRequired[name] {
+			if _, found := obj[req]; !found {
+				missing = append(missing, strconv.Quote(req))
+			}
+		}
+		units = append(units, assert("dependentRequired", len(missing) == 0, strings.Join(missing, ", "), strconv.Quote(name)))
 	}
-	return b.String()
+	return units
 }
 
-func (m *ObjectMixin[S]) validateProperties(obj map[string]interface{}) error {
-	keys := slices.Collect(maps.Keys(obj))
-	for _, req := range m.Required {
-		if slices.Index(keys, req) == -1 {
-			return fmt.Errorf("required: property name %q not found", req)
+// evaluateDependentSchemas applies the schema of each property of obj in DependentSchemas to obj.
+func (m *ObjectMixin[S]) evaluateDependentSchemas(ctx context.Context, obj map[string]interface{}) ([]*jsonschema.Output, error) {
+	if m.DependentSchemas == nil {
+		return nil, nil
+	}
+	var nested []*jsonschema.Output
+	for _, name := range slices.Sorted(maps.Keys(m.DependentSchemas)) {
+		if _, found := obj[name]; !found {
+			continue
 		}
+		u, err := jsonschema.EvaluateContext(ctx, m.DependentSchemas[name], obj)
+		if err != nil {
+			return nil, err
+		}
+		nested = append(nested, jsonschema.Prefix(u, "", "dependentSchemas", name)...)
 	}
-	hasPropertyNames := !reflect.DeepEqual(m.PropertyNames, nil)
-	hasProperties := !reflect.DeepEqual(m.Properties, nil)
-	hasPatternProperties := !reflect.DeepEqual(m.PatternProperties, nil)
-	hasAdditionalProperties := m.AdditionalProperties != nil
-	for k, v := range obj {
-		if hasPropertyNames {
-			if err := m.PropertyNames.Validate(v); err != nil {
-				return fmt.Errorf("propertyNames: property name %q: %w", k, err)
+	return []*jsonschema.Output{jsonschema.NewOutput("dependentSchemas", nested...)}, nil
+}
+
+func (m *ObjectMixin[S]) evaluateProperties(ctx context.Context, obj map[string]interface{}) ([]*jsonschema.Output, error) {
+	var units []*jsonschema.Output
+	keys := slices.Sorted(maps.Keys(obj))
+	if !isNil(m.PropertyNames) {
+		var nested []*jsonschema.Output
+		for _, k := range keys {
+			u, err := jsonschema.EvaluateContext(ctx, m.PropertyNames, k)
+			if err != nil {
+				return nil, err
 			}
+			// names are not values of the instance, so units are located at the object
+			nested = append(nested, jsonschema.Prefix(u, "", "propertyNames")...)
 		}
-		if hasProperties {
-			if s, ok := m.Properties[k]; ok {
-				if err := s.Validate(v); err != nil {
-					return fmt.Errorf("properties: property %q: %w", k, err)
-				}
+		units = append(units, jsonschema.NewOutput("propertyNames", nested...))
+	}
+
+	evaluated := make(map[string]bool, len(obj))
+	if m.Properties != nil {
+		var nested []*jsonschema.Output
+		names := make([]string, 0)
+		for _, k := range keys {
+			s, found := m.Properties[k]
+			if !found {
 				continue
 			}
+			u, err := jsonschema.EvaluateContext(ctx, s, obj[k])
+			if err != nil {
+				return nil, err
+			}
+			nested = append(nested, jsonschema.Prefix(u, k, "properties", k)...)
+			names = append(names, k)
+			evaluated[k] = true
 		}
-		if hasPatternProperties {
-			for pattern, s := range m.PatternProperties {
-				if matched, err := regexp.Match(pattern, []byte(k)); matched || err != nil {
-					if err != nil {
-						return fmt.Errorf("patternProperties: pattern %q failed to compile %w", pattern, err)
-					}
-					if err := s.Validate(v); err != nil {
-						return fmt.Errorf("patternProperties: property %q: %w", k, err)
-					}
+		units = append(units, jsonschema.NewOutput("properties", nested...).Annotate(names))
+	}
+	if m.PatternProperties != nil {
+		var nested []*jsonschema.Output
+		names := make([]string, 0)
+		for _, pattern := range slices.Sorted(maps.Keys(m.PatternProperties)) {
+			re, ok := m.patterns[pattern]
+			if !ok {
+				var err error
+				if re, err = ecma.Compile(pattern); err != nil {
+					return nil, fmt.Errorf("patternProperties: pattern %q failed to compile: %w", pattern, err)
+				}
+			}
+			for _, k := range keys {
+				if !re.MatchString(k) {
 					continue
 				}
+				u, err := jsonschema.EvaluateContext(ctx, m.PatternProperties[pattern], obj[k])
+				if err != nil {
+					return nil, err
+				}
+				nested = append(nested, jsonschema.Prefix(u, k, "patternProperties", pattern)...)
+				if !evaluated[k] {
+					names = append(names, k)
+				}
+				evaluated[k] = true
 			}
 		}
-		if hasAdditionalProperties {
-			if !reflect.DeepEqual(m.AdditionalProperties.Y, nil) {
-				if err := m.AdditionalProperties.Y.Validate(v); err != nil {
-					return fmt.Errorf("additionalProperties: property %q: %w", k, err)
-				}
+		slices.Sort(names)
+		units = append(units, jsonschema.NewOutput("patternProperties", nested...).Annotate(names))
+	}
+	if m.AdditionalProperties != nil {
+		var nested []*jsonschema.Output
+		var additional []string
+		for _, k := range keys {
+			if evaluated[k] {
 				continue
 			}
-			if m.AdditionalProperties.X {
-				continue
+			additional = append(additional, k)
+			if !isNil(m.AdditionalProperties.Y) {
+				u, err := jsonschema.EvaluateContext(ctx, m.AdditionalProperties.Y, obj[k])
+				if err != nil {
+					return nil, err
+				}
+				nested = append(nested, jsonschema.Prefix(u, k, "additionalProperties")...)
 			}
-			return fmt.Errorf("additionalProperties: got extra property %q", k)
 		}
+		unit := jsonschema.NewOutput("additionalProperties", nested...)
+		if isNil(m.AdditionalProperties.Y) && !m.AdditionalProperties.X && len(additional) > 0 {
+			quoted := make([]string, len(additional))
+			for i, k := range additional {
+				quoted[i] = strconv.Quote(k)
+			}
+			unit.Fail(strings.Join(quoted, ", "))
+		}
+		units = append(units, unit.Annotate(append(make([]string, 0), additional...)))
 	}
-	return nil
+	return units, nil
 }
 
 type StringMixin struct {
-	MaxLength int    `json:"maxLength,omitempty" validate:"omitempty,gte=0"`
-	MinLength int    `json:"minLength,omitempty" validate:"omitempty,gte=0"`
-	Pattern   string `json:"pattern,omitempty"  validate:"regex"`
+	MaxLength *jsonschema.Int `json:"maxLength,omitempty" validate:"omitempty,gte=0"`
+	MinLength jsonschema.Int  `json:"minLength,omitempty" validate:"omitempty,gte=0"`
+	Pattern   string          `json:"pattern,omitempty"  validate:"regex"`
+
+	pattern *ecma.Regexp // compiled Pattern
 }
 
 func (m *StringMixin) Kind() jsonschema.Kind {
@@ -291,36 +478,54 @@ func (m *StringMixin) AppliesTo(t jsonschema.Type) bool {
 	return t.Has(jsonschema.StringType)
 }
 
+// Compile compiles Pattern.
+func (m *StringMixin) Compile() error {
+	if m.Pattern == "" {
+		return nil
+	}
+	re, err := ecma.Compile(m.Pattern)
+	if err != nil {
+		return fmt.Errorf("pattern: pattern %q failed to compile: %w", m.Pattern, err)
+	}
+	m.pattern = re
+	return nil
+}
+
 func (m *StringMixin) Validate(v interface{}) error {
-	str := v.(string)
-	return validate.Var(str, m.validateTag())
+	return jsonschema.Errors(m.Evaluate(v))
 }
 
-func (m *StringMixin) validateTag() string {
-	b := strings.Builder{}
-	if m.MaxLength != 0 {
-		b.WriteString("max=")
-		b.WriteString(strconv.Itoa(m.MaxLength))
-		b.WriteRune(validateTagSep)
+func (m *StringMixin) Evaluate(v interface{}) ([]*jsonschema.Output, error) {
+	str, ok, err := instanceOf[string](v)
+	if !ok {
+		return nil, err
+	}
+	var units []*jsonschema.Output
+	length := utf8.RuneCountInString(str)
+	if m.MaxLength != nil {
+		units = append(units, assert("maxLength", length <= int(*m.MaxLength), strconv.Itoa(int(*m.MaxLength)), strconv.Itoa(length)))
 	}
 	if m.MinLength != 0 {
-		b.WriteString("min=")
-		b.WriteString(strconv.Itoa(m.MinLength))
-		b.WriteRune(validateTagSep)
+		units = append(units, assert("minLength", length >= int(m.MinLength), strconv.Itoa(int(m.MinLength)), strconv.Itoa(length)))
 	}
 	if m.Pattern != "" {
-		b.WriteString("regex_ecma=")
-		b.WriteString(m.Pattern)
-		b.WriteRune(validateTagSep)
+		re := m.pattern
+		if re == nil {
+			var err error
+			if re, err = ecma.Compile(m.Pattern); err != nil {
+				return nil, fmt.Errorf("pattern: pattern %q failed to compile: %w", m.Pattern, err)
+			}
+		}
+		units = append(units, assert("pattern", re.MatchString(str), strconv.Quote(m.Pattern)))
 	}
-	return b.String()
+	return units, nil
 }
 
 type ValidationMixin struct {
 	Type   jsonschema.Type   `json:"type,omitempty"`
 	Format jsonschema.Format `json:"format,omitempty"`
 	Enum   []interface{}     `json:"enum,omitempty"`
-	Const  interface{}       `json:"const,omitempty"`
+	Const  json.RawMessage   `json:"const,omitempty"` // which may be null
 }
 
 func (m *ValidationMixin) Kind() jsonschema.Kind {
@@ -332,22 +537,143 @@ func (m *ValidationMixin) AppliesTo(t jsonschema.Type) bool {
 }
 
 func (m *ValidationMixin) Validate(v interface{}) error {
-	if m.Const != nil {
-		if v != m.Const {
-			return errors.New("const: invalid value")
+	return jsonschema.Errors(m.Evaluate(v))
+}
+
+func (m *ValidationMixin) Evaluate(v interface{}) ([]*jsonschema.Output, error) {
+	return m.EvaluateContext(context.Background(), v)
+}
+
+// EvaluateContext is Evaluate within the context of an evaluation, in which format may be an assertion, see
+// jsonschema.AssertFormat.
+func (m *ValidationMixin) EvaluateContext(ctx context.Context, v interface{}) ([]*jsonschema.Output, error) {
+	v, err := jsonschema.Normalize(v)
+	if err != nil {
+		return nil, err
+	}
+	var units []*jsonschema.Output
+	if m.Type != 0 {
+		t := jsonschema.TypeOf(v)
+		if x, ok := number(v); ok && x == math.Trunc(x) {
+			// any number with zero fractional part is an integer
+			t = jsonschema.IntegerType | jsonschema.NumberType
 		}
+		units = append(units, assert("type", m.Type.Has(t), m.Type.String(), jsonschema.TypeOf(v).String()))
 	}
 	if len(m.Enum) != 0 {
-		for _, enum := range m.Enum {
-			if enum == v {
-				return nil
-			}
+		ok := slices.ContainsFunc(m.Enum, func(enum interface{}) bool { return equal(v, enum) })
+		units = append(units, assert("enum", ok, marshal(m.Enum)))
+	}
+	if m.Const != nil {
+		var c interface{}
+		if err := json.Unmarshal(m.Const, &c); err != nil {
+			return nil, fmt.Errorf("const: %w", err)
+		}
+		units = append(units, assert("const", equal(v, c), marshal(m.Const)))
+	}
+	if m.Format != jsonschema.NoFormat {
+		// format only annotates unless asserted: https://json-schema.org/draft/2020-12/json-schema-validation#section-7.2
+		ok := jsonschema.CheckFormat(ctx, m.Format, v)
+		units = append(units, assert("format", ok, strconv.Quote(string(m.Format))).Annotate(m.Format))
+	}
+	return units, nil
+}
+
+// assert returns the unit of keyword, failed with params unless ok.
+func assert(keyword string, ok bool, params ...string) *jsonschema.Output {
+	u := jsonschema.NewOutput(keyword)
+	if !ok {
+		u.Fail(params...)
+	}
+	return u
+}
+
+// valid reports whether all units are valid.
+func valid(units []*jsonschema.Output) bool {
+	for _, u := range units {
+		if !u.Valid {
+			return false
 		}
-		return errors.New("enum: invalid value")
 	}
-	if !m.Type.Has(jsonschema.TypeOf(v)) {
-		return fmt.Errorf("type: want %s, got %s", m.Type, jsonschema.TypeOf(v))
+	return true
+}
+
+// equal reports whether JSON values a and b are equal, regardless of the Go types of numbers. Values which cannot be
+// normalized are not equal to any value.
+func equal(a, b interface{}) bool {
+	a, errA := jsonschema.Normalize(a)
+	b, errB := jsonschema.Normalize(b)
+	if errA != nil || errB != nil {
+		return false
+	}
+	switch a := a.(type) {
+	case []interface{}:
+		b, ok := b.([]interface{})
+		return ok && slices.EqualFunc(a, b, equal)
+	case map[string]interface{}:
+		b, ok := b.(map[string]interface{})
+		return ok && maps.EqualFunc(a, b, equal)
+	}
+	if x, ok := number(a); ok {
+		y, ok := number(b)
+		return ok && x == y
+	}
+	return reflect.TypeOf(a) == reflect.TypeOf(b) && a == b
+}
+
+// instanceOf returns v normalized as a T, see jsonschema.Normalize, or false if it is not one, in which case the
+// keywords of a mixin evaluated on its own, rather than by a schema which checks jsonschema.Keyword.AppliesTo, do not
+// apply to it.
+func instanceOf[T any](v interface{}) (T, bool, error) {
+	var zero T
+	v, err := jsonschema.Normalize(v)
+	if err != nil {
+		return zero, false, err
+	}
+	t, ok := v.(T)
+	return t, ok, nil
+}
+
+// number returns v as float64 if it is a number.
+func number(v interface{}) (float64, bool) {
+	rv := reflect.ValueOf(v)
+	switch {
+	case rv.CanInt():
+		return float64(rv.Int()), true
+	case rv.CanUint():
+		return float64(rv.Uint()), true
+	case rv.CanFloat():
+		return rv.Float(), true
+	default:
+		return 0, false
+	}
+}
+
+// compile compiles the subschemas which are jsonschema.Compiler.
+func compile[S jsonschema.Keyword](schemas ...S) error {
+	for _, s := range schemas {
+		if isNil(s) {
+			continue
+		}
+		if c, ok := any(s).(jsonschema.Compiler); ok {
+			if err := c.Compile(); err != nil {
+				return err
+			}
+		}
 	}
-	// TODO: Format
 	return nil
 }
+
+// isNil reports whether the subschema s is absent.
+func isNil[S any](s S) bool {
+	v := reflect.ValueOf(s)
+	return !v.IsValid() || (v.Kind() == reflect.Pointer && v.IsNil())
+}
+
+func marshal(v interface{}) string {
+	b, err := json.Marshal(v)
+	if err != nil {
+		return fmt.Sprint(v)
+	}
+	return string(b)
+}
@@ -0,0 +1,149 @@
+package jsonschema
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	ut "github.com/go-playground/universal-translator"
+
+	"github.com/MaiMee1/go-apispec/oas/internal/i18n"
+	"github.com/MaiMee1/go-apispec/oas/jsonpointer"
+)
+
+// ValidationError is an assertion of a keyword failed by an instance, see
+// https://json-schema.org/draft/2020-12/json-schema-core#section-12.3.
+type ValidationError struct {
+	InstanceLocation        jsonpointer.Ptr // location of the value in the instance
+	KeywordLocation         jsonpointer.Ptr // location of the keyword in the schema, following references
+	AbsoluteKeywordLocation string          // absolute URI of the keyword, empty if the schema has no absolute URI
+	Keyword                 string
+	Message                 string // message of the fallback locale
+
+	params []string
+}
+
+// NewValidationError returns a ValidationError of keyword at the root of the instance and of the schema.
+//
+// The message is the translation of keyword with params, see Translate.
+func NewValidationError(keyword string, params ...string) *ValidationError {
+	e := &ValidationError{
+		KeywordLocation: jsonpointer.New(keyword),
+		Keyword:         keyword,
+		params:          params,
+	}
+	e.Message = e.Translate(i18n.GetFallback())
+	return e
+}
+
+// Error implements error.
+func (e *ValidationError) Error() string {
+	return fmt.Sprintf("%q: %s", e.InstanceLocation, e.Message)
+}
+
+// Translate returns the message translated by trans, or Message if trans has no translation of Keyword.
+//
+// Translations are registered under the keyword name, with parameters as described by Messages.
+func (e *ValidationError) Translate(trans ut.Translator) string {
+	s, err := trans.T(e.Keyword, e.params...)
+	if err != nil {
+		return e.Message
+	}
+	return s
+}
+
+// ValidationErrors is the list of ValidationError returned by Keyword.Validate.
+type ValidationErrors []*ValidationError
+
+// Error implements error.
+func (e ValidationErrors) Error() string {
+	b := strings.Builder{}
+	for i, err := range e {
+		if i > 0 {
+			b.WriteRune('\n')
+		}
+		b.WriteString(err.Error())
+	}
+	return b.String()
+}
+
+// Translate returns the messages of the errors translated by trans.
+func (e ValidationErrors) Translate(trans ut.Translator) []string {
+	messages := make([]string, len(e))
+	for i, err := range e {
+		messages[i] = err.Translate(trans)
+	}
+	return messages
+}
+
+// Append adds the errors in err, which is returned by Keyword.Validate, to e.
+//
+// It returns false if err is not made of validation errors, in which case e is returned as is.
+func (e ValidationErrors) Append(err error) (ValidationErrors, bool) {
+	var single *ValidationError
+	var multiple ValidationErrors
+	switch {
+	case err == nil:
+		return e, true
+	case errors.As(err, &multiple):
+		return append(e, multiple...), true
+	case errors.As(err, &single):
+		return append(e, single), true
+	default:
+		return e, false
+	}
+}
+
+// Err returns e as an error, or nil if e is empty.
+func (e ValidationErrors) Err() error {
+	if len(e) == 0 {
+		return nil
+	}
+	return e
+}
+
+// Messages are the messages of the fallback locale by keyword, where {0}, {1}... are replaced by the parameters
+// of a ValidationError.
+var Messages = map[string]string{
+	"type":                  "value must be of type {0}, got {1}",
+	"const":                 "value must be {0}",
+	"enum":                  "value must be one of {0}",
+	"format":                "value must be of format {0}",
+	"maxLength":             "string must have at most {0} characters, got {1}",
+	"minLength":             "string must have at least {0} characters, got {1}",
+	"pattern":               "string must match pattern {0}",
+	"multipleOf":            "number must be a multiple of {0}",
+	"maximum":               "number must be at most {0}",
+	"exclusiveMaximum":      "number must be less than {0}",
+	"minimum":               "number must be at least {0}",
+	"exclusiveMinimum":      "number must be greater than {0}",
+	"maxItems":              "array must have at most {0} items, got {1}",
+	"minItems":              "array must have at least {0} items, got {1}",
+	"uniqueItems":           "array items at {0} and {1} must be unique",
+	"items":                 "array must not have items from index {0}",
+	"additionalItems":       "array must not have additional items from index {0}",
+	"contains":              "array must contain a matching item",
+	"minContains":           "array must contain at least {0} matching items, got {1}",
+	"maxContains":           "array must contain at most {0} matching items, got {1}",
+	"maxProperties":         "object must have at most {0} properties, got {1}",
+	"minProperties":         "object must have at least {0} properties, got {1}",
+	"required":              "object must have properties {0}",
+	"dependencies":          "object must have properties {0} as it has property {1}",
+	"dependentRequired":     "object must have properties {0} as it has property {1}",
+	"additionalProperties":  "object must not have additional properties {0}",
+	"unevaluatedProperties": "object must not have unevaluated properties {0}",
+	"unevaluatedItems":      "array must not have unevaluated items at {0}",
+	"anyOf":                 "value must match at least one schema",
+	"oneOf":                 "value must match exactly one schema, matched {0}",
+	"not":                   "value must not match the schema",
+	"false":                 "value is not allowed by the schema false",
+}
+
+func init() {
+	trans := i18n.GetFallback()
+	for keyword, text := range Messages {
+		if err := trans.Add(keyword, text, false); err != nil {
+			panic(err)
+		}
+	}
+}
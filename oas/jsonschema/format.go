@@ -1,5 +1,21 @@
 package jsonschema
 
+import (
+	"context"
+	"net/mail"
+	"net/netip"
+	"net/url"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+	"unicode/utf8"
+
+	"github.com/MaiMee1/go-apispec/oas/internal/ecma"
+	"github.com/MaiMee1/go-apispec/oas/jsonpointer"
+)
+
 type Format string
 
 const (
@@ -10,6 +26,7 @@ const (
 	TimeFormat                Format = "time"                  // as defined by the "full-time" ABNF rule in RFC 3339
 	DurationFormat            Format = "duration"              // as defined by the "duration" ABNF rule in RFC 3339
 	EmailFormat               Format = "email"                 // as defined by the "Mailbox" ABNF rule in RFC 5321
+	HostnameFormat            Format = "hostname"              // as defined by section 2.1 of RFC 1123
 	IdnEmailFormat            Format = "idn-email"             // as defined by the "Mailbox" ABNF rule in RFC 5321 extended by RFC 6531
 	Ipv4Format                Format = "ipv4"                  // as defined by the "dotted-quad" ABNF rule in RFC 2673
 	Ipv6Format                Format = "ipv6"                  // as defined by RFC 4291
@@ -20,6 +37,233 @@ const (
 	UuidFormat                Format = "uuid"                  // as defined by the "UUID" ABNF rule in RFC 4122
 	UriTemplateFormat         Format = "uri-template"          // as defined by the "URI-Template" ABNF rule in RFC 6570
 	JsonPointerFormat         Format = "json-pointer"          // as defined by RFC 6901
-	RelativeJsonPointerFormat Format = "relative-json-pointer" // as defined by RFC 6901
+	RelativeJsonPointerFormat Format = "relative-json-pointer" // as defined by draft-bhutton-relative-json-pointer-00
 	RegexFormat               Format = "regex"                 // regular expression in the ECMA-262 dialect
 )
+
+// FormatChecker reports whether v conforms to a format. Values of types the format does not apply to conform, e.g.
+// numbers to the date-time format.
+type FormatChecker func(v interface{}) bool
+
+var (
+	formatsMu sync.RWMutex
+	formats   = map[Format]FormatChecker{
+		DateTimeFormat:            stringChecker(isDateTime),
+		DateFormat:                stringChecker(isDate),
+		TimeFormat:                stringChecker(isTime),
+		DurationFormat:            stringChecker(durationRe.MatchString),
+		EmailFormat:               stringChecker(isEmail),
+		IdnEmailFormat:            stringChecker(isEmail),
+		HostnameFormat:            stringChecker(isHostname),
+		Ipv4Format:                stringChecker(isIPv4),
+		Ipv6Format:                stringChecker(isIPv6),
+		UriFormat:                 stringChecker(isURI),
+		UriReferenceFormat:        stringChecker(isURIReference),
+		IriFormat:                 stringChecker(isIRI),
+		IriReferenceFormat:        stringChecker(isIRIReference),
+		UuidFormat:                stringChecker(uuidRe.MatchString),
+		UriTemplateFormat:         stringChecker(isURITemplate),
+		JsonPointerFormat:         stringChecker(jsonPointerRe.MatchString),
+		RelativeJsonPointerFormat: stringChecker(isRelativeJsonPointer),
+		RegexFormat:               stringChecker(isRegex),
+	}
+)
+
+// RegisterFormat registers checker of format f, replacing the checker of f if any, including a built-in one. It is
+// meant for formats of a dialect, registered on initialization; checkers of a single evaluation are given to
+// AssertFormat instead.
+func RegisterFormat(f Format, checker FormatChecker) {
+	formatsMu.Lock()
+	defer formatsMu.Unlock()
+	formats[f] = checker
+}
+
+// Checker returns the checker of f, or false if f is unknown.
+func (f Format) Checker() (FormatChecker, bool) {
+	formatsMu.RLock()
+	defer formatsMu.RUnlock()
+	checker, ok := formats[f]
+	return checker, ok
+}
+
+// Check reports whether v conforms to f. Any value conforms to an unknown format.
+func (f Format) Check(v interface{}) bool {
+	checker, ok := f.Checker()
+	return !ok || checker(v)
+}
+
+type assertFormatKey struct{}
+
+// AssertFormat returns ctx in which format is an assertion, as with the format-assertion vocabulary, rather than
+// only an annotation, which is the default, see https://json-schema.org/draft/2020-12/json-schema-validation#section-7.2.
+//
+// Formats are checked by checkers, if any, and otherwise by the built-in and registered checkers.
+func AssertFormat(ctx context.Context, checkers map[Format]FormatChecker) context.Context {
+	return context.WithValue(ctx, assertFormatKey{}, checkers)
+}
+
+// CheckFormat reports whether v conforms to f within ctx, which it does unless format is an assertion, see
+// AssertFormat.
+func CheckFormat(ctx context.Context, f Format, v interface{}) bool {
+	checkers, ok := ctx.Value(assertFormatKey{}).(map[Format]FormatChecker)
+	if !ok {
+		return true
+	}
+	if checker, ok := checkers[f]; ok {
+		return checker(v)
+	}
+	return f.Check(v)
+}
+
+// stringChecker returns a FormatChecker of a format which applies to strings.
+func stringChecker(check func(s string) bool) FormatChecker {
+	return func(v interface{}) bool {
+		s, ok := v.(string)
+		return !ok || check(s)
+	}
+}
+
+var (
+	dateRe        = regexp.MustCompile(`^(\d{4})-(\d{2})-(\d{2})$`)
+	timeRe        = regexp.MustCompile(`^(\d{2}):(\d{2}):(\d{2})(\.\d+)?([Zz]|([+-])(\d{2}):(\d{2}))$`)
+	durationRe    = regexp.MustCompile(`^P(((\d+D)|(\d+M(\d+D)?)|(\d+Y(\d+M(\d+D)?)?))(T((\d+H(\d+M(\d+S)?)?)|(\d+M(\d+S)?)|(\d+S)))?|T((\d+H(\d+M(\d+S)?)?)|(\d+M(\d+S)?)|(\d+S))|\d+W)$`)
+	uuidRe        = regexp.MustCompile(`^[0-9a-fA-F]{8}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{12}$`)
+	jsonPointerRe = regexp.MustCompile(`^(/([^/~]|~[01])*)*$`)
+	labelRe       = regexp.MustCompile(`^[0-9A-Za-z]([0-9A-Za-z-]{0,61}[0-9A-Za-z])?$`)
+)
+
+func isDateTime(s string) bool {
+	date, t, ok := strings.Cut(strings.ToUpper(s), "T")
+	return ok && isDate(date) && isTime(t)
+}
+
+func isDate(s string) bool {
+	m := dateRe.FindStringSubmatch(s)
+	if m == nil {
+		return false
+	}
+	year, _ := strconv.Atoi(m[1])
+	month, _ := strconv.Atoi(m[2])
+	day, _ := strconv.Atoi(m[3])
+	if month < 1 || month > 12 || day < 1 {
+		return false
+	}
+	// the day before the first day of the next month is the last day of the month
+	return day <= time.Date(year, time.Month(month)+1, 0, 0, 0, 0, 0, time.UTC).Day()
+}
+
+func isTime(s string) bool {
+	m := timeRe.FindStringSubmatch(s)
+	if m == nil {
+		return false
+	}
+	hour, _ := strconv.Atoi(m[1])
+	minute, _ := strconv.Atoi(m[2])
+	second, _ := strconv.Atoi(m[3])
+	offsetHour, _ := strconv.Atoi(m[7])
+	offsetMinute, _ := strconv.Atoi(m[8])
+	if hour > 23 || minute > 59 || second > 60 || offsetHour > 23 || offsetMinute > 59 {
+		return false
+	}
+	if second == 60 {
+		// a leap second is the last second of a day in UTC
+		utc := hour*60 + minute
+		switch m[6] {
+		case "+":
+			utc -= offsetHour*60 + offsetMinute
+		case "-":
+			utc += offsetHour*60 + offsetMinute
+		}
+		return (utc+24*60)%(24*60) == 23*60+59
+	}
+	return true
+}
+
+func isEmail(s string) bool {
+	addr, err := mail.ParseAddress(s)
+	return err == nil && addr.Name == "" && addr.Address == s
+}
+
+// isHostname reports whether s is made of labels of at most 63 letters, digits and hyphens, which do not start or end
+// with a hyphen, and has at most 253 characters.
+func isHostname(s string) bool {
+	if len(s) > 253 {
+		return false
+	}
+	for _, label := range strings.Split(s, ".") {
+		if !labelRe.MatchString(label) {
+			return false
+		}
+	}
+	return true
+}
+
+func isIPv4(s string) bool {
+	addr, err := netip.ParseAddr(s)
+	return err == nil && addr.Is4()
+}
+
+func isIPv6(s string) bool {
+	addr, err := netip.ParseAddr(s)
+	return err == nil && addr.Is6() && addr.Zone() == ""
+}
+
+func isURI(s string) bool {
+	return isASCII(s) && isIRI(s)
+}
+
+func isURIReference(s string) bool {
+	return isASCII(s) && isIRIReference(s)
+}
+
+func isIRI(s string) bool {
+	uri, err := url.Parse(s)
+	return err == nil && uri.IsAbs() && isIRIReference(s)
+}
+
+func isIRIReference(s string) bool {
+	if strings.ContainsAny(s, " \\<>\"{}|^`") || !utf8.ValidString(s) {
+		return false
+	}
+	uri, err := url.Parse(s)
+	// an IPv6 address must be enclosed in brackets, as a host is otherwise followed by a port after a colon
+	return err == nil && !(strings.Contains(uri.Hostname(), ":") && !strings.HasPrefix(uri.Host, "["))
+}
+
+func isASCII(s string) bool {
+	for i := 0; i < len(s); i++ {
+		if s[i] >= utf8.RuneSelf {
+			return false
+		}
+	}
+	return true
+}
+
+// isURITemplate reports whether s has balanced, non-nested expressions.
+func isURITemplate(s string) bool {
+	open := false
+	for _, r := range s {
+		switch r {
+		case '{':
+			if open {
+				return false
+			}
+			open = true
+		case '}':
+			if !open {
+				return false
+			}
+			open = false
+		}
+	}
+	return !open
+}
+
+func isRelativeJsonPointer(s string) bool {
+	return jsonpointer.RelativePtr(s).Validate() == nil
+}
+
+func isRegex(s string) bool {
+	_, err := ecma.Compile(s)
+	return err == nil
+}
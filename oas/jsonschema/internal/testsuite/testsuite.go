@@ -0,0 +1,110 @@
+// Package testsuite runs test cases in the format of the JSON-Schema-Test-Suite, see
+// https://github.com/json-schema-org/JSON-Schema-Test-Suite.
+package testsuite
+
+import (
+	"encoding/json"
+	"errors"
+	"net/url"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/MaiMee1/go-apispec/oas/jsonschema"
+	"github.com/MaiMee1/go-apispec/oas/resolve"
+)
+
+// Case is a schema with the instances to validate against it.
+type Case struct {
+	Description string          `json:"description"`
+	Schema      json.RawMessage `json:"schema"`
+	Tests       []struct {
+		Description string          `json:"description"`
+		Data        json.RawMessage `json:"data"`
+		Valid       bool            `json:"valid"`
+	} `json:"tests"`
+}
+
+// Skip are the reasons to skip test files, cases or tests, keyed by "file.json", "file.json/case description" or
+// "file.json/case description/test description".
+type Skip map[string]string
+
+// Remotes returns a loader of the documents the tests refer to at http://localhost:1234, which are the files of dir,
+// such as the remotes directory of the suite, loading any other URI through loader.
+func Remotes(dir string, loader resolve.Loader) resolve.Loader {
+	return resolve.LoaderFunc(func(uri *url.URL) ([]byte, error) {
+		if uri.Scheme == "http" && uri.Host == "localhost:1234" {
+			return os.ReadFile(filepath.Join(dir, filepath.FromSlash(uri.Path)))
+		}
+		return loader.Load(uri)
+	})
+}
+
+// Validator validates instances, such as a jsonschema.Keyword or a jsonschema.Validator.
+type Validator interface {
+	Validate(v interface{}) error
+}
+
+// Run runs the test cases of each JSON file in dir, not in subdirectories, against schemas decoded by decode.
+//
+// Tests not skipped fail if decoding fails, or if validation fails with an error other than
+// jsonschema.ValidationErrors or does not give the expected result.
+func Run(t *testing.T, dir string, skip Skip, decode func(b []byte) (Validator, error)) {
+	files, err := filepath.Glob(filepath.Join(dir, "*.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(files) == 0 {
+		t.Fatalf("no test files in %s", dir)
+	}
+	for _, file := range files {
+		name := filepath.Base(file)
+		t.Run(name, func(t *testing.T) {
+			if reason, ok := skip[name]; ok {
+				t.Skip(reason)
+			}
+			b, err := os.ReadFile(file)
+			if err != nil {
+				t.Fatal(err)
+			}
+			var cases []Case
+			if err := json.Unmarshal(b, &cases); err != nil {
+				t.Fatal(err)
+			}
+			for _, c := range cases {
+				t.Run(c.Description, func(t *testing.T) {
+					runCase(t, name+"/"+c.Description, c, skip, decode)
+				})
+			}
+		})
+	}
+}
+
+func runCase(t *testing.T, key string, c Case, skip Skip, decode func(b []byte) (Validator, error)) {
+	if reason, ok := skip[key]; ok {
+		t.Skip(reason)
+	}
+	schema, err := decode(c.Schema)
+	if err != nil {
+		t.Fatalf("decode schema: %v", err)
+	}
+	for _, tt := range c.Tests {
+		t.Run(tt.Description, func(t *testing.T) {
+			if reason, ok := skip[key+"/"+tt.Description]; ok {
+				t.Skip(reason)
+			}
+			var instance interface{}
+			if err := json.Unmarshal(tt.Data, &instance); err != nil {
+				t.Fatal(err)
+			}
+			err := schema.Validate(instance)
+			var errs jsonschema.ValidationErrors
+			if err != nil && !errors.As(err, &errs) {
+				t.Fatal(err)
+			}
+			if (err == nil) != tt.Valid {
+				t.Errorf("got valid %v, want %v: %v", err == nil, tt.Valid, err)
+			}
+		})
+	}
+}
@@ -1,6 +1,7 @@
 package jsonschema
 
 import (
+	"context"
 	"iter"
 	"maps"
 
@@ -38,3 +39,30 @@ func (k Kind) Has(ands ...Kind) bool {
 func (k Kind) Range() iter.Seq[Kind] {
 	return flag.Range(k, maps.Keys(kindToString))
 }
+
+// Evaluator is a Keyword which reports the output units of evaluating its keywords, located relative to the schema.
+type Evaluator interface {
+	Keyword
+	Evaluate(v interface{}) ([]*Output, error)
+}
+
+// ContextEvaluator is an Evaluator which evaluates subschemas within the context of an evaluation, such as to track
+// the dynamic scope of "$dynamicRef", see EvaluateContext.
+type ContextEvaluator interface {
+	Evaluator
+	EvaluateContext(ctx context.Context, v interface{}) ([]*Output, error)
+}
+
+// AdjacentEvaluator is a Keyword which depends on the output units of the adjacent keywords of the schema, such as
+// unevaluatedProperties, and is evaluated after them.
+type AdjacentEvaluator interface {
+	Keyword
+	EvaluateAdjacent(ctx context.Context, v interface{}, adjacent []*Output) ([]*Output, error)
+}
+
+// Compiler is a Keyword which prepares for evaluation once, such as by compiling patterns and resolving references,
+// instead of on every evaluation. It must not be modified after Compile.
+type Compiler interface {
+	Keyword
+	Compile() error
+}
@@ -0,0 +1,160 @@
+// Package oas30 implements the OpenAPI 3.0 Schema Object, which is an extended subset of JSON Schema draft-04 with
+// nullable, the boolean exclusiveMaximum and exclusiveMinimum, and a single example, see
+// https://spec.openapis.org/oas/v3.0.3#schema-object.
+package oas30
+
+import (
+	"context"
+	"iter"
+	"reflect"
+	"slices"
+
+	"github.com/MaiMee1/go-apispec/oas/jsonschema"
+	"github.com/MaiMee1/go-apispec/oas/jsonschema/abc"
+	"github.com/MaiMee1/go-apispec/oas/jsonschema/draft07"
+	"github.com/MaiMee1/go-apispec/oas/jsonschema/draft2020"
+	"github.com/MaiMee1/go-apispec/oas/jsonschema/oas31"
+	"github.com/MaiMee1/go-apispec/oas/resolve"
+	"github.com/MaiMee1/go-apispec/oas/ser"
+)
+
+// Dialect identifies the OpenAPI 3.0 Schema Object dialect, which OpenAPI 3.0 does not publish a meta-schema of, to
+// select Schema for the schemas of OpenAPI 3.0 documents, see jsonschema.Decode.
+const Dialect = "https://spec.openapis.org/oas/3.0/dialect"
+
+func init() {
+	jsonschema.RegisterDialect(Dialect, jsonschema.DecoderOf[Schema]())
+}
+
+var zero Schema
+
+type Schema struct {
+	draft2020.ReferenceMixin[*Schema]
+	draft2020.MetaDataMixin
+	ValidationMixin
+	draft2020.StringMixin
+	draft07.NumericMixin
+	draft2020.ObjectMixin[*Schema]
+	draft07.ArrayMixin[*Schema]
+	draft2020.ApplicatorMixin[*Schema]
+	oas31.OASMixin
+
+	keywords []jsonschema.Keyword // cached by Compile
+}
+
+// MarshalJSON inlines OASMixin.Extensions as "x-" prefixed members.
+//
+//goland:noinspection GoMixedReceiverTypes
+func (m Schema) MarshalJSON() ([]byte, error) {
+	type schema Schema
+	return ser.MarshalExtended(schema(m), m.Extensions)
+}
+
+//goland:noinspection GoMixedReceiverTypes
+func (m *Schema) UnmarshalJSON(b []byte) (err error) {
+	type schema Schema
+	m.Extensions, err = ser.UnmarshalExtended(b, (*schema)(m))
+	return err
+}
+
+// Keywords yields the mixins of m, which is only ReferenceMixin if m has a "$ref", as the other properties of a
+// Reference Object are ignored.
+//
+//goland:noinspection GoMixedReceiverTypes
+func (m *Schema) Keywords() iter.Seq[jsonschema.Keyword] {
+	if m.keywords != nil {
+		return slices.Values(m.keywords)
+	}
+	return func(yield func(jsonschema.Keyword) bool) {
+		if m.Ref != "" {
+			yield(&m.ReferenceMixin)
+			return
+		}
+		if !reflect.DeepEqual(m.MetaDataMixin, zero.MetaDataMixin) {
+			if !yield(&m.MetaDataMixin) {
+				return
+			}
+		}
+		if !reflect.DeepEqual(m.ValidationMixin, zero.ValidationMixin) {
+			if !yield(&m.ValidationMixin) {
+				return
+			}
+		}
+		if !reflect.DeepEqual(m.StringMixin, zero.StringMixin) {
+			if !yield(&m.StringMixin) {
+				return
+			}
+		}
+		if !reflect.DeepEqual(m.NumericMixin, zero.NumericMixin) {
+			if !yield(&m.NumericMixin) {
+				return
+			}
+		}
+		if !reflect.DeepEqual(m.ObjectMixin, zero.ObjectMixin) {
+			if !yield(&m.ObjectMixin) {
+				return
+			}
+		}
+		if !reflect.DeepEqual(m.ArrayMixin, zero.ArrayMixin) {
+			if !yield(&m.ArrayMixin) {
+				return
+			}
+		}
+		if !reflect.DeepEqual(m.ApplicatorMixin, zero.ApplicatorMixin) {
+			if !yield(&m.ApplicatorMixin) {
+				return
+			}
+		}
+		if !reflect.DeepEqual(m.OASMixin, zero.OASMixin) {
+			if !yield(&m.OASMixin) {
+				return
+			}
+		}
+	}
+}
+
+//goland:noinspection GoMixedReceiverTypes
+func (m *Schema) Kind() jsonschema.Kind {
+	return abc.Kind(m)
+}
+
+//goland:noinspection GoMixedReceiverTypes
+func (m *Schema) AppliesTo(t jsonschema.Type) bool {
+	return abc.AppliesTo(m, t)
+}
+
+//goland:noinspection GoMixedReceiverTypes
+func (m *Schema) Validate(v interface{}) error {
+	return jsonschema.Errors(m.Evaluate(v))
+}
+
+// Evaluate returns the unit of evaluating m against v, see jsonschema.Output.Format.
+//
+//goland:noinspection GoMixedReceiverTypes
+func (m *Schema) Evaluate(v interface{}) ([]*jsonschema.Output, error) {
+	return m.EvaluateContext(context.Background(), v)
+}
+
+// EvaluateContext is Evaluate within the context of an evaluation, in which m enters the dynamic scope, see
+// resolve.EnterScope.
+//
+//goland:noinspection GoMixedReceiverTypes
+func (m *Schema) EvaluateContext(ctx context.Context, v interface{}) ([]*jsonschema.Output, error) {
+	return abc.EvaluateContext(resolve.EnterScope(ctx, m.Context()), m, v)
+}
+
+// Compile compiles the keywords of m and of its subschemas, and caches the keywords of m, see jsonschema.Compile.
+//
+// If m is not bound to the scope of a resolver, it is bound as a standalone schema, see resolve.Standalone.
+//
+//goland:noinspection GoMixedReceiverTypes
+func (m *Schema) Compile() error {
+	if m.keywords != nil {
+		return nil // compiled, or being compiled through a cyclic reference
+	}
+	if _, _, ok := resolve.Scope(m.Context()); !ok {
+		resolve.Standalone(m, "", nil)
+	}
+	m.keywords = slices.AppendSeq(make([]jsonschema.Keyword, 0), m.Keywords())
+	return abc.Compile(m)
+}
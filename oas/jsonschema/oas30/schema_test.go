@@ -0,0 +1,78 @@
+package oas30
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"slices"
+	"testing"
+
+	"github.com/MaiMee1/go-apispec/oas/jsonschema"
+)
+
+func TestSchema_MarshalJSON(t *testing.T) {
+	var s Schema
+	data := `{"type":"integer","nullable":true,"minimum":0,"exclusiveMinimum":true,"example":1,"x-internal":true}`
+	err := json.Unmarshal([]byte(data), &s)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !s.Nullable || !s.ExclusiveMinimum.Exclusive {
+		t.Errorf("got nullable %v and exclusiveMinimum %v, want true", s.Nullable, s.ExclusiveMinimum.Exclusive)
+	}
+	// Act
+	b, err := json.Marshal(s)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(b) != data {
+		t.Errorf("got %s, want %s", b, data)
+	}
+}
+
+func TestSchema_Validate(t *testing.T) {
+	tests := []struct {
+		schema   string
+		instance string
+		want     []string // keyword location and message of the errors
+	}{
+		{`{"type": "string", "nullable": true}`, `null`, nil},
+		{`{"type": "string"}`, `null`, []string{"/type value must be of type string, got null"}},
+		{`{"type": "string", "nullable": true, "enum": ["a"]}`, `null`, []string{`/enum value must be one of ["a"]`}},
+		{`{"type": "integer", "minimum": 1, "exclusiveMinimum": true}`, `1`, []string{"/exclusiveMinimum number must be greater than 1"}},
+		{`{"type": "integer", "maximum": 1, "exclusiveMaximum": false}`, `1`, nil},
+		{`{"type": "array", "items": {"type": "string", "nullable": true}}`, `["a", null, 1]`,
+			[]string{"/items/type value must be of type (null|string), got number"}},
+		{`{"properties": {"pet": {"$ref": "#/properties/name", "nullable": true}, "name": {"type": "string"}}}`, `{"pet": null}`,
+			[]string{"/properties/pet/$ref/type value must be of type string, got null"}},
+	}
+	for i, tt := range tests {
+		t.Run(fmt.Sprintf("%d", i), func(t *testing.T) {
+			s, err := jsonschema.Decode([]byte(tt.schema), Dialect)
+			if err != nil {
+				t.Fatal(err)
+			}
+			v, err := jsonschema.Compile(s)
+			if err != nil {
+				t.Fatal(err)
+			}
+			var instance interface{}
+			if err := json.Unmarshal([]byte(tt.instance), &instance); err != nil {
+				t.Fatal(err)
+			}
+			// Act
+			err = v.Validate(instance)
+			var errs jsonschema.ValidationErrors
+			if err != nil && !errors.As(err, &errs) {
+				t.Fatal(err)
+			}
+			var got []string
+			for _, e := range errs {
+				got = append(got, string(e.KeywordLocation)+" "+e.Message)
+			}
+			if !slices.Equal(got, tt.want) {
+				t.Errorf("got %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
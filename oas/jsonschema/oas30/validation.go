@@ -0,0 +1,34 @@
+package oas30
+
+import (
+	"context"
+
+	"github.com/MaiMee1/go-apispec/oas/jsonschema"
+	"github.com/MaiMee1/go-apispec/oas/jsonschema/draft2020"
+)
+
+// ValidationMixin is draft2020.ValidationMixin with nullable, which allows null in addition to the type of the schema,
+// see https://spec.openapis.org/oas/v3.0.3#fixed-fields-19.
+type ValidationMixin struct {
+	draft2020.ValidationMixin
+	Nullable bool `json:"nullable,omitempty"`
+}
+
+func (m *ValidationMixin) Validate(v interface{}) error {
+	return jsonschema.Errors(m.Evaluate(v))
+}
+
+// Evaluate evaluates type as also allowing null if Nullable. Nullable has no effect on a schema without type, which
+// allows null already, nor on enum, which must list null to allow it.
+func (m *ValidationMixin) Evaluate(v interface{}) ([]*jsonschema.Output, error) {
+	return m.EvaluateContext(context.Background(), v)
+}
+
+// EvaluateContext is Evaluate within the context of an evaluation, see draft2020.ValidationMixin.EvaluateContext.
+func (m *ValidationMixin) EvaluateContext(ctx context.Context, v interface{}) ([]*jsonschema.Output, error) {
+	mixin := m.ValidationMixin
+	if m.Nullable && mixin.Type != 0 {
+		mixin.Type |= jsonschema.NullType
+	}
+	return mixin.EvaluateContext(ctx, v)
+}
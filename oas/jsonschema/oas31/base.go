@@ -2,12 +2,13 @@ package oas31
 
 import (
 	"github.com/MaiMee1/go-apispec/oas/jsonschema"
+	"github.com/MaiMee1/go-apispec/oas/ser"
 )
 
 type SpecificationExtension map[string]interface{}
 
 type OASMixin struct {
-	Example       interface{}            `json:"examples,omitempty"`
+	Example       interface{}            `json:"example,omitempty"` // Deprecated: use MetaDataMixin.Examples
 	ExternalDocs  *ExternalDocumentation `json:"externalDocs,omitempty"`
 	Discriminator *Discriminator         `json:"discriminator,omitempty"`
 	Xml           *XML                   `json:"xml,omitempty"`
@@ -32,6 +33,19 @@ type ExternalDocumentation struct {
 	Extensions  SpecificationExtension `json:"-"`
 }
 
+//goland:noinspection GoMixedReceiverTypes
+func (d ExternalDocumentation) MarshalJSON() ([]byte, error) {
+	type externalDocumentation ExternalDocumentation
+	return ser.MarshalExtended(externalDocumentation(d), d.Extensions)
+}
+
+//goland:noinspection GoMixedReceiverTypes
+func (d *ExternalDocumentation) UnmarshalJSON(b []byte) (err error) {
+	type externalDocumentation ExternalDocumentation
+	d.Extensions, err = ser.UnmarshalExtended(b, (*externalDocumentation)(d))
+	return err
+}
+
 type Discriminator struct {
 	PropertyName string            `json:"propertyName,omitempty" validate:"required"`
 	Mapping      map[string]string `json:"mapping,omitempty" validate:"dive,uri-reference"`
@@ -45,3 +59,16 @@ type XML struct {
 	Wrapped    bool                   `json:"wrapped,omitempty"`
 	Extensions SpecificationExtension `json:"-"`
 }
+
+//goland:noinspection GoMixedReceiverTypes
+func (x XML) MarshalJSON() ([]byte, error) {
+	type xml XML
+	return ser.MarshalExtended(xml(x), x.Extensions)
+}
+
+//goland:noinspection GoMixedReceiverTypes
+func (x *XML) UnmarshalJSON(b []byte) (err error) {
+	type xml XML
+	x.Extensions, err = ser.UnmarshalExtended(b, (*xml)(x))
+	return err
+}
@@ -0,0 +1,69 @@
+package oas31
+
+import (
+	"encoding/base64"
+	"math"
+	"reflect"
+
+	"github.com/MaiMee1/go-apispec/oas/jsonschema"
+)
+
+// Formats defined by the OpenAPI Format Registry, see https://spec.openapis.org/registry/format/.
+const (
+	Int32Format  jsonschema.Format = "int32"  // signed 32 bits
+	Int64Format  jsonschema.Format = "int64"  // signed 64 bits (a.k.a. long)
+	FloatFormat  jsonschema.Format = "float"  // single precision floating-point number
+	DoubleFormat jsonschema.Format = "double" // double precision floating-point number
+
+	ByteFormat     jsonschema.Format = "byte"     // base64 encoded data as defined in RFC 4648
+	Base64Format   jsonschema.Format = "base64"   // base64 encoded characters
+	BinaryFormat   jsonschema.Format = "binary"   // octet-stream
+	PasswordFormat jsonschema.Format = "password" // A hint to UIs to obscure input.
+)
+
+func init() {
+	jsonschema.RegisterFormat(Int32Format, numberChecker(func(x float64) bool {
+		return x == math.Trunc(x) && x >= math.MinInt32 && x <= math.MaxInt32
+	}))
+	jsonschema.RegisterFormat(Int64Format, numberChecker(func(x float64) bool {
+		// float64 rounds math.MaxInt64 up to 1 << 63, which is out of range
+		return x == math.Trunc(x) && x >= math.MinInt64 && x < math.MaxInt64
+	}))
+	jsonschema.RegisterFormat(FloatFormat, numberChecker(func(x float64) bool {
+		return math.Abs(x) <= math.MaxFloat32
+	}))
+	jsonschema.RegisterFormat(DoubleFormat, numberChecker(func(x float64) bool {
+		return true
+	}))
+	jsonschema.RegisterFormat(ByteFormat, isBase64)
+	jsonschema.RegisterFormat(Base64Format, isBase64)
+	// any value is octet-stream or can be a password
+	jsonschema.RegisterFormat(BinaryFormat, func(v interface{}) bool { return true })
+	jsonschema.RegisterFormat(PasswordFormat, func(v interface{}) bool { return true })
+}
+
+// numberChecker returns a jsonschema.FormatChecker of a format which applies to numbers.
+func numberChecker(check func(x float64) bool) jsonschema.FormatChecker {
+	return func(v interface{}) bool {
+		rv := reflect.ValueOf(v)
+		switch {
+		case rv.CanInt():
+			return check(float64(rv.Int()))
+		case rv.CanUint():
+			return check(float64(rv.Uint()))
+		case rv.CanFloat():
+			return check(rv.Float())
+		default:
+			return true
+		}
+	}
+}
+
+func isBase64(v interface{}) bool {
+	s, ok := v.(string)
+	if !ok {
+		return true
+	}
+	_, err := base64.StdEncoding.DecodeString(s)
+	return err == nil
+}
@@ -0,0 +1,45 @@
+package oas31
+
+import (
+	"embed"
+	"maps"
+
+	"github.com/MaiMee1/go-apispec/oas/jsonschema"
+	"github.com/MaiMee1/go-apispec/oas/jsonschema/draft2020"
+	"github.com/MaiMee1/go-apispec/oas/resolve"
+)
+
+// Dialect is the URI of the OpenAPI 3.1 Schema Object dialect, which schemas without "$schema" are validated against by
+// ValidateSchema.
+const Dialect = "https://spec.openapis.org/oas/3.1/dialect/base"
+
+func init() {
+	jsonschema.RegisterDialect(Dialect, jsonschema.DecoderOf[Schema]())
+}
+
+// Vocabularies are the URIs of the vocabularies implemented by the mixins, which are the 2020-12 vocabularies and the
+// OAS base vocabulary.
+var Vocabularies = append(draft2020.Vocabularies[:len(draft2020.Vocabularies):len(draft2020.Vocabularies)],
+	"https://spec.openapis.org/oas/3.1/vocab/base")
+
+//go:embed metaschema
+var metaSchemaFS embed.FS
+
+// MetaSchemas loads the OpenAPI 3.1 dialect and base vocabulary meta-schemas along with draft2020.MetaSchemas by their
+// "$id".
+var MetaSchemas = func() resolve.MapLoader {
+	loader, err := draft2020.LoadMetaSchemas(metaSchemaFS)
+	if err != nil {
+		panic(err)
+	}
+	maps.Copy(loader, draft2020.MetaSchemas)
+	return loader
+}()
+
+var metaValidator = &draft2020.MetaValidator{Dialect: Dialect, Vocabularies: Vocabularies, Loader: MetaSchemas}
+
+// ValidateSchema validates document against the OpenAPI 3.1 dialect, or the meta-schema of MetaSchemas identified by
+// its "$schema", see draft2020.MetaValidator.Validate.
+func ValidateSchema(document interface{}) error {
+	return metaValidator.Validate(document)
+}
@@ -1,17 +1,23 @@
 package oas31
 
 import (
+	"context"
+	"encoding/json"
 	"iter"
 	"reflect"
+	"slices"
 
 	"github.com/MaiMee1/go-apispec/oas/jsonschema"
 	"github.com/MaiMee1/go-apispec/oas/jsonschema/abc"
 	"github.com/MaiMee1/go-apispec/oas/jsonschema/draft2020"
+	"github.com/MaiMee1/go-apispec/oas/resolve"
+	"github.com/MaiMee1/go-apispec/oas/ser"
 )
 
 var zero Schema
 
 type Schema struct {
+	draft2020.BooleanMixin
 	draft2020.MetaSchemaMixin[*Schema]
 	draft2020.ReferenceMixin[Schema]
 	draft2020.MetaDataMixin
@@ -22,12 +28,48 @@ type Schema struct {
 	draft2020.ArrayMixin[*Schema]
 	draft2020.UnevaluatedMixin[*Schema]
 	draft2020.ApplicatorMixin[*Schema]
+	draft2020.ContentMixin[*Schema]
 	OASMixin
+
+	keywords []jsonschema.Keyword // cached by Compile
+}
+
+// MarshalJSON inlines OASMixin.Extensions as "x-" prefixed members, and encodes a boolean schema as true or false.
+//
+//goland:noinspection GoMixedReceiverTypes
+func (m Schema) MarshalJSON() ([]byte, error) {
+	if m.Boolean != nil {
+		return json.Marshal(*m.Boolean)
+	}
+	type schema Schema
+	return ser.MarshalExtended(schema(m), m.Extensions)
 }
 
+// UnmarshalJSON decodes true and false as boolean schemas, see draft2020.BooleanMixin.
+//
+//goland:noinspection GoMixedReceiverTypes
+func (m *Schema) UnmarshalJSON(b []byte) (err error) {
+	if value, ok := draft2020.ParseBoolean(b); ok {
+		*m = Schema{BooleanMixin: draft2020.BooleanMixin{Boolean: &value}}
+		return nil
+	}
+	type schema Schema
+	m.Extensions, err = ser.UnmarshalExtended(b, (*schema)(m))
+	return err
+}
+
+//goland:noinspection GoMixedReceiverTypes
 func (m *Schema) Keywords() iter.Seq[jsonschema.Keyword] {
+	if m.keywords != nil {
+		return slices.Values(m.keywords)
+	}
 	return func(yield func(jsonschema.Keyword) bool) {
-		if !reflect.DeepEqual(m.MetaSchemaMixin, zero.MetaDataMixin) {
+		if !reflect.DeepEqual(m.BooleanMixin, zero.BooleanMixin) {
+			if !yield(&m.BooleanMixin) {
+				return
+			}
+		}
+		if !reflect.DeepEqual(m.MetaSchemaMixin, zero.MetaSchemaMixin) {
 			if !yield(&m.MetaSchemaMixin) {
 				return
 			}
@@ -77,6 +119,11 @@ func (m *Schema) Keywords() iter.Seq[jsonschema.Keyword] {
 				return
 			}
 		}
+		if !reflect.DeepEqual(m.ContentMixin, zero.ContentMixin) {
+			if !yield(&m.ContentMixin) {
+				return
+			}
+		}
 		if !reflect.DeepEqual(m.OASMixin, zero.OASMixin) {
 			if !yield(&m.OASMixin) {
 				return
@@ -85,14 +132,50 @@ func (m *Schema) Keywords() iter.Seq[jsonschema.Keyword] {
 	}
 }
 
+//goland:noinspection GoMixedReceiverTypes
 func (m *Schema) Kind() jsonschema.Kind {
 	return abc.Kind(m)
 }
 
+//goland:noinspection GoMixedReceiverTypes
 func (m *Schema) AppliesTo(t jsonschema.Type) bool {
 	return abc.AppliesTo(m, t)
 }
 
+//goland:noinspection GoMixedReceiverTypes
 func (m *Schema) Validate(v interface{}) error {
-	return abc.Validate(m, v)
+	return jsonschema.Errors(m.Evaluate(v))
+}
+
+// Evaluate returns the unit of evaluating m against v, see jsonschema.Output.Format.
+//
+//goland:noinspection GoMixedReceiverTypes
+func (m *Schema) Evaluate(v interface{}) ([]*jsonschema.Output, error) {
+	return m.EvaluateContext(context.Background(), v)
+}
+
+// EvaluateContext is Evaluate within the context of an evaluation, in which m enters the dynamic scope, see
+// resolve.EnterScope.
+//
+//goland:noinspection GoMixedReceiverTypes
+func (m *Schema) EvaluateContext(ctx context.Context, v interface{}) ([]*jsonschema.Output, error) {
+	units, err := abc.EvaluateContext(resolve.EnterScope(ctx, m.Context()), m, v)
+	return jsonschema.Absolute(units, m.Id), err
+}
+
+// Compile compiles the keywords of m and of its subschemas, and caches the keywords of m, see jsonschema.Compile.
+//
+// If m is not bound to the scope of a resolver, it is bound as a standalone schema, see resolve.Standalone, which may
+// refer to the MetaSchemas.
+//
+//goland:noinspection GoMixedReceiverTypes
+func (m *Schema) Compile() error {
+	if m.keywords != nil {
+		return nil // compiled, or being compiled through a cyclic reference
+	}
+	if _, _, ok := resolve.Scope(m.Context()); !ok {
+		resolve.Standalone(m, m.Id, MetaSchemas)
+	}
+	m.keywords = slices.AppendSeq(make([]jsonschema.Keyword, 0), m.Keywords())
+	return abc.Compile(m)
 }
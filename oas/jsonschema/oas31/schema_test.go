@@ -2,7 +2,13 @@ package oas31
 
 import (
 	"encoding/json"
+	"fmt"
+	"regexp"
 	"testing"
+
+	"github.com/MaiMee1/go-apispec/oas/jsonschema"
+	"github.com/MaiMee1/go-apispec/oas/jsonschema/internal/testsuite"
+	"github.com/MaiMee1/go-apispec/oas/resolve"
 )
 
 func TestSchema_UnmarshalJSON(t *testing.T) {
@@ -29,7 +35,7 @@ func TestSchema_UnmarshalJSON(t *testing.T) {
 
 func TestSchema_MarshalJSON(t *testing.T) {
 	var s Schema
-	data := `{"$schema":"https://example.com/schema-required","$id":"https://my-schema.com"}`
+	data := `{"$schema":"https://example.com/schema-required","$id":"https://my-schema.com","$comment":"pets of the store"}`
 	err := json.Unmarshal([]byte(data), &s)
 	if err != nil {
 		t.Fatal(err)
@@ -44,3 +50,108 @@ func TestSchema_MarshalJSON(t *testing.T) {
 	}
 	t.Log(string(b))
 }
+
+func TestSchema_Extensions(t *testing.T) {
+	var s Schema
+	data := `{"type":"string","x-codegen":{"name":"Pet"},"x-internal":true}`
+	err := json.Unmarshal([]byte(data), &s)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if s.Extensions["x-internal"] != true {
+		t.Errorf("got x-internal %v, want true", s.Extensions["x-internal"])
+	}
+	s.Extensions["GoType"] = struct{}{}
+	// Act
+	b, err := json.Marshal(s)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(b) != data {
+		t.Errorf("got %s, want %s", b, data)
+	}
+}
+
+func TestSuite(t *testing.T) {
+	testsuite.Run(t, "../testdata/JSON-Schema-Test-Suite/tests/draft2020-12", nil, func(b []byte) (testsuite.Validator, error) {
+		var s Schema
+		if err := json.Unmarshal(b, &s); err != nil {
+			return &s, err
+		}
+		resolve.Standalone(&s, s.Id, testsuite.Remotes("../testdata/JSON-Schema-Test-Suite/remotes", MetaSchemas))
+		return &s, nil
+	})
+}
+
+func TestSchema_Validate_format(t *testing.T) {
+	ulid := regexp.MustCompile(`^[0-9A-HJKMNP-TV-Z]{26}$`)
+	checkers := map[jsonschema.Format]jsonschema.FormatChecker{
+		"x-ulid": func(v interface{}) bool {
+			s, ok := v.(string)
+			return !ok || ulid.MatchString(s)
+		},
+	}
+	tests := []struct {
+		format   jsonschema.Format
+		instance interface{}
+		valid    bool
+	}{
+		{Int32Format, float64(2147483647), true},
+		{Int32Format, float64(2147483648), false},
+		{Int32Format, 1.5, false},
+		{Int64Format, float64(-9007199254740993), true},
+		{Int64Format, 1e19, false},
+		{FloatFormat, 3.4e38, true},
+		{FloatFormat, 3.5e38, false},
+		{DoubleFormat, 1.7e308, true},
+		{ByteFormat, "U3dhZ2dlciByb2Nrcw==", true},
+		{ByteFormat, "U3dhZ2dlciByb2Nrcw", false},
+		{BinaryFormat, "\x00\xff", true},
+		{PasswordFormat, "hunter2", true},
+		{Int32Format, "not a number", true},
+		{"x-ulid", "01ARZ3NDEKTSV4RRFFQ69G5FAV", true},
+		{"x-ulid", "01ARZ3NDEKTSV4RRFFQ69G5FAU", false},
+		{"x-unknown", "anything", true},
+	}
+	for i, tt := range tests {
+		t.Run(fmt.Sprintf("%d", i), func(t *testing.T) {
+			s := Schema{}
+			s.Format = tt.format
+			v, err := jsonschema.Compile(&s, jsonschema.WithFormatAssertion(checkers))
+			if err != nil {
+				t.Fatal(err)
+			}
+			// Act
+			err = v.Validate(tt.instance)
+			if (err == nil) != tt.valid {
+				t.Errorf("%s %v: got valid %v, want %v: %v", tt.format, tt.instance, err == nil, tt.valid, err)
+			}
+			if err := s.Validate(tt.instance); err != nil {
+				t.Errorf("%s %v: got error without format assertion: %v", tt.format, tt.instance, err)
+			}
+		})
+	}
+}
+
+func TestValidateSchema(t *testing.T) {
+	tests := []struct {
+		document string
+		want     string
+	}{
+		{`{"$comment":"pets of the store","type":"object"}`, "<nil>"},
+		{`{"type":"object","discriminator":{"propertyName":"kind","x-go":true},"xml":{"name":"pet"},"example":{"kind":"cat"}}`, "<nil>"},
+		{`{"type":"object","properties":{"pet":{"discriminator":{"mapping":{"cat":"#/$defs/cat"}}}}}`, `"/properties/pet/discriminator": object must have properties "propertyName"`},
+		{`{"externalDocs":{"url":"https://example.com","title":"docs"}}`, `"/externalDocs": object must not have unevaluated properties "title"`},
+		{`{"$schema":"https://json-schema.org/draft/2020-12/schema","xml":{"wrapped":"yes"},"minLength":-1}`, `"/minLength": number must be at least 0`},
+	}
+	for i, tt := range tests {
+		t.Run(fmt.Sprintf("%d", i), func(t *testing.T) {
+			// Act
+			err := ValidateSchema([]byte(tt.document))
+
+			if fmt.Sprint(err) != tt.want {
+				t.Errorf("got error %v, want %s", err, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,336 @@
+package jsonschema
+
+import (
+	"context"
+	"encoding/json"
+	"net/url"
+	"strings"
+
+	"github.com/MaiMee1/go-apispec/oas/internal/i18n"
+	"github.com/MaiMee1/go-apispec/oas/jsonpointer"
+)
+
+// OutputFormat is a structure of the results of evaluating a schema, see
+// https://json-schema.org/draft/2020-12/json-schema-core#section-12.4.
+type OutputFormat uint8
+
+const (
+	FlagOutput     OutputFormat = iota // a boolean which simply indicates the overall validation result
+	BasicOutput                        // a flat list of output units
+	DetailedOutput                     // a hierarchy of output units following the schema, without nodes with a single child
+	VerboseOutput                      // a hierarchy of output units following exactly the schema
+)
+
+// Output is an output unit of evaluating a schema or a keyword against an instance, see
+// https://json-schema.org/draft/2020-12/json-schema-core#section-12.3.
+type Output struct {
+	Valid                   bool            `json:"valid"`
+	KeywordLocation         jsonpointer.Ptr `json:"keywordLocation"`
+	AbsoluteKeywordLocation string          `json:"absoluteKeywordLocation,omitempty"`
+	InstanceLocation        jsonpointer.Ptr `json:"instanceLocation"`
+	Error                   string          `json:"error,omitempty"`
+	Annotation              interface{}     `json:"annotation,omitempty"`
+	Errors                  []*Output       `json:"errors,omitempty"`      // nested units of an invalid unit
+	Annotations             []*Output       `json:"annotations,omitempty"` // nested units of a valid unit
+
+	flag    bool
+	keyword string
+	params  []string
+
+	// instanceOffset and keywordOffset are prepended to the locations of nested units, see Prefix
+	instanceOffset jsonpointer.Ptr
+	keywordOffset  jsonpointer.Ptr
+	// base is the absolute URI of the location of nested units, see Absolute
+	base *url.URL
+}
+
+// NewOutput returns the unit of keyword, or of a schema if keyword is empty, with nested units, which are located
+// relative to the same schema. The unit is valid if all nested units are valid.
+func NewOutput(keyword string, units ...*Output) *Output {
+	o := &Output{Valid: true, keyword: keyword}
+	if keyword != "" {
+		o.KeywordLocation = jsonpointer.New(keyword)
+	}
+	for _, u := range units {
+		o.Valid = o.Valid && u.Valid
+	}
+	if o.Valid {
+		o.Annotations = units
+	} else {
+		o.Errors = units
+	}
+	return o
+}
+
+// Fail marks o as invalid with the message of its keyword with params, see ValidationError.Translate.
+func (o *Output) Fail(params ...string) *Output {
+	o.Valid = false
+	o.Annotation = nil
+	o.params = params
+	o.Error = (&ValidationError{Keyword: o.keyword, params: params}).Translate(i18n.GetFallback())
+	if o.Errors == nil {
+		o.Errors, o.Annotations = o.Annotations, nil
+	}
+	return o
+}
+
+// Pass marks o as valid regardless of its nested units, as applicators such as anyOf do.
+func (o *Output) Pass() *Output {
+	o.Valid = true
+	o.Error, o.params = "", nil
+	if o.Annotations == nil {
+		o.Errors, o.Annotations = nil, o.Errors
+	}
+	return o
+}
+
+// Annotate attaches value to the instance location of o unless o is invalid, as annotations of failed units are
+// dropped.
+func (o *Output) Annotate(value interface{}) *Output {
+	if o.Valid {
+		o.Annotation = value
+	}
+	return o
+}
+
+// Keyword returns the keyword of o, or an empty string if o is the unit of a schema.
+func (o *Output) Keyword() string {
+	return o.keyword
+}
+
+// Units returns the nested units of o.
+func (o *Output) Units() []*Output {
+	if o.Valid {
+		return o.Annotations
+	}
+	return o.Errors
+}
+
+// Format returns o in format f, with the nested units located relative to o.
+func (o *Output) Format(f OutputFormat) *Output {
+	if f != FlagOutput {
+		o = o.locate("", "", nil, "")
+	}
+	switch f {
+	case FlagOutput:
+		return &Output{Valid: o.Valid, flag: true}
+	case BasicOutput:
+		root := o.unit()
+		o.flatten(root)
+		return root
+	case DetailedOutput:
+		return o.detailed()
+	default:
+		return o
+	}
+}
+
+// unit returns a copy of o without nested units.
+func (o *Output) unit() *Output {
+	u := *o
+	u.Errors, u.Annotations = nil, nil
+	return &u
+}
+
+// flatten adds the errors of o or, if it is valid, the annotations of o to root.
+func (o *Output) flatten(root *Output) {
+	if !o.Valid {
+		if o.Error != "" {
+			root.Errors = append(root.Errors, o.unit())
+		}
+		for _, u := range o.Errors {
+			if !u.Valid {
+				u.flatten(root)
+			}
+		}
+		return
+	}
+	if o.Annotation != nil {
+		root.Annotations = append(root.Annotations, o.unit())
+	}
+	for _, u := range o.Annotations {
+		if u.Valid {
+			u.flatten(root)
+		}
+	}
+}
+
+// detailed returns o with only the nested units that fail it or, if it is valid, that annotate the instance,
+// replacing any nested unit with its single nested unit.
+func (o *Output) detailed() *Output {
+	root := o.unit()
+	for _, u := range o.Units() {
+		if u.Valid != o.Valid || (u.Valid && !u.annotates()) {
+			continue
+		}
+		u = u.detailed()
+		if u.Error == "" && u.Annotation == nil && len(u.Units()) == 1 {
+			u = u.Units()[0]
+		}
+		if o.Valid {
+			root.Annotations = append(root.Annotations, u)
+		} else {
+			root.Errors = append(root.Errors, u)
+		}
+	}
+	return root
+}
+
+func (o *Output) annotates() bool {
+	if o.Annotation != nil {
+		return true
+	}
+	for _, u := range o.Annotations {
+		if u.Valid && u.annotates() {
+			return true
+		}
+	}
+	return false
+}
+
+// MarshalJSON omits everything but Valid in FlagOutput.
+func (o *Output) MarshalJSON() ([]byte, error) {
+	if o.flag {
+		return json.Marshal(struct {
+			Valid bool `json:"valid"`
+		}{o.Valid})
+	}
+	type output Output
+	return json.Marshal((*output)(o))
+}
+
+// Evaluate returns the units of keyword k evaluated against v, converting errors of Keyword.Validate if k is not an
+// Evaluator.
+func Evaluate(k Keyword, v interface{}) ([]*Output, error) {
+	if e, ok := k.(Evaluator); ok {
+		return e.Evaluate(v)
+	}
+	var errs ValidationErrors
+	err := k.Validate(v)
+	errs, ok := errs.Append(err)
+	if !ok {
+		return nil, err
+	}
+	units := make([]*Output, len(errs))
+	for i, e := range errs {
+		units[i] = &Output{
+			KeywordLocation:         e.KeywordLocation,
+			AbsoluteKeywordLocation: e.AbsoluteKeywordLocation,
+			InstanceLocation:        e.InstanceLocation,
+			Error:                   e.Message,
+			keyword:                 e.Keyword,
+			params:                  e.params,
+		}
+	}
+	return units, nil
+}
+
+// EvaluateContext is Evaluate within the context of an evaluation, if k is a ContextEvaluator.
+func EvaluateContext(ctx context.Context, k Keyword, v interface{}) ([]*Output, error) {
+	if e, ok := k.(ContextEvaluator); ok {
+		return e.EvaluateContext(ctx, v)
+	}
+	return Evaluate(k, v)
+}
+
+// Errors returns ValidationErrors of the units, or err if it is not nil, which makes Keyword.Validate out of
+// Evaluator.Evaluate.
+func Errors(units []*Output, err error) error {
+	if err != nil {
+		return err
+	}
+	var errs ValidationErrors
+	for _, u := range units {
+		for _, e := range u.Format(BasicOutput).Errors {
+			errs = append(errs, &ValidationError{
+				InstanceLocation:        e.InstanceLocation,
+				KeywordLocation:         e.KeywordLocation,
+				AbsoluteKeywordLocation: e.AbsoluteKeywordLocation,
+				Keyword:                 e.keyword,
+				Message:                 e.Error,
+				params:                  e.params,
+			})
+		}
+	}
+	return errs.Err()
+}
+
+// Prefix prepends the instance token, if not empty, and the keyword tokens to the locations of the units and
+// their nested units, which are evaluated by a subschema. Only the units are changed; the locations of their nested
+// units are prefixed when the units are formatted, see Output.Format.
+func Prefix(units []*Output, instance string, keyword ...string) []*Output {
+	var instancePtr, keywordPtr jsonpointer.Ptr
+	if instance != "" {
+		instancePtr = jsonpointer.New(instance)
+	}
+	for _, k := range keyword {
+		keywordPtr += jsonpointer.New(k)
+	}
+	for _, u := range units {
+		u.InstanceLocation = instancePtr + u.InstanceLocation
+		u.KeywordLocation = keywordPtr + u.KeywordLocation
+		u.instanceOffset = instancePtr + u.instanceOffset
+		u.keywordOffset = keywordPtr + u.keywordOffset
+	}
+	return units
+}
+
+// Absolute sets the absolute keyword location of the units and their nested units, which are evaluated by a
+// schema identified by id, unless id is not an absolute URI or the location is set by a subschema. id may have a
+// JSON pointer fragment locating the schema within its resource, but not a plain name fragment. Like Prefix, only the
+// units are changed; their nested units are located when the units are formatted.
+func Absolute(units []*Output, id string) []*Output {
+	uri, err := url.Parse(id)
+	if err != nil || !uri.IsAbs() || (uri.Fragment != "" && !strings.HasPrefix(uri.Fragment, "/")) {
+		return units
+	}
+	for _, u := range units {
+		if u.AbsoluteKeywordLocation != "" {
+			continue // set by a subschema with $id, as are its nested units
+		}
+		base := *uri
+		base.Fragment = uri.Fragment + string(u.keywordOffset)
+		base.RawFragment = ""
+		abs := *uri
+		abs.Fragment = uri.Fragment + string(u.KeywordLocation)
+		abs.RawFragment = ""
+		u.AbsoluteKeywordLocation = abs.String()
+		u.base = &base
+	}
+	return units
+}
+
+// locate returns a copy of o and its nested units located absolutely, given the offsets of the ancestors of o and,
+// if the schema of o has an absolute URI, the URI of the nearest ancestor set by Absolute with the keyword location of
+// o relative to it.
+func (o *Output) locate(instance, keyword jsonpointer.Ptr, base *url.URL, relative jsonpointer.Ptr) *Output {
+	u := *o
+	u.InstanceLocation = instance + o.InstanceLocation
+	u.KeywordLocation = keyword + o.KeywordLocation
+	u.instanceOffset, u.keywordOffset, u.base = "", "", nil
+	if o.base != nil {
+		base, relative = o.base, ""
+	} else {
+		if base != nil {
+			abs := *base
+			abs.Fragment = base.Fragment + string(relative+o.KeywordLocation)
+			u.AbsoluteKeywordLocation = abs.String()
+		}
+		relative += o.keywordOffset
+	}
+	instance += o.instanceOffset
+	keyword += o.keywordOffset
+	if o.Errors != nil {
+		u.Errors = make([]*Output, len(o.Errors))
+		for i, e := range o.Errors {
+			u.Errors[i] = e.locate(instance, keyword, base, relative)
+		}
+	}
+	if o.Annotations != nil {
+		u.Annotations = make([]*Output, len(o.Annotations))
+		for i, a := range o.Annotations {
+			u.Annotations[i] = a.locate(instance, keyword, base, relative)
+		}
+	}
+	return &u
+}
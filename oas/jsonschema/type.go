@@ -6,6 +6,7 @@ import (
 	"iter"
 	"maps"
 	"reflect"
+	"slices"
 	"strings"
 
 	"github.com/MaiMee1/go-apispec/oas/internal/flag"
@@ -56,6 +57,9 @@ var kindToType = []Type{
 }
 
 func TypeOf(v any) Type {
+	if v == nil {
+		return NullType
+	}
 	return kindToType[reflect.TypeOf(v).Kind()]
 }
 
@@ -70,14 +74,17 @@ var typeToString = map[Type]string{
 }
 var stringToType map[string]Type
 
+// types are the single types in bit order, which is the order of a combination.
+var types = slices.Sorted(maps.Keys(typeToString))
+
 //goland:noinspection GoMixedReceiverTypes
 func (t Type) Has(ands ...Type) bool {
-	return flag.Has(t, maps.Keys(typeToString), ands...)
+	return flag.Has(t, slices.Values(types), ands...)
 }
 
 //goland:noinspection GoMixedReceiverTypes
 func (t Type) Range() iter.Seq[Type] {
-	return flag.Range(t, maps.Keys(typeToString))
+	return flag.Range(t, slices.Values(types))
 }
 
 //goland:noinspection GoMixedReceiverTypes
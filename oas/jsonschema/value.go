@@ -0,0 +1,100 @@
+package jsonschema
+
+import (
+	"encoding/json"
+	"fmt"
+	"maps"
+	"math"
+	"slices"
+)
+
+// Normalize returns v as a value decoded from JSON by encoding/json into an interface{}, so that keywords can
+// validate any Go value with the semantics of its JSON encoding, including struct tags and json.Marshaler.
+//
+// Values which are already decoded JSON are returned as is: booleans, strings, numbers of a built-in type, and
+// []interface{} and map[string]interface{} of such values. An array or object with elements of other types is
+// copied with its elements normalized. A json.Number becomes a float64. Any other value is encoded to JSON and
+// decoded back.
+func Normalize(v interface{}) (interface{}, error) {
+	normalized, _, err := normalize(v)
+	return normalized, err
+}
+
+// normalize is Normalize, which also reports whether v is changed, so that arrays and objects are only copied when
+// any of their elements is.
+func normalize(v interface{}) (interface{}, bool, error) {
+	switch v := v.(type) {
+	case nil, bool, string, float64, float32,
+		int, int8, int16, int32, int64, uint, uint8, uint16, uint32, uint64:
+		return v, false, nil
+	case []interface{}:
+		var arr []interface{}
+		for i, e := range v {
+			n, changed, err := normalize(e)
+			if err != nil {
+				return nil, false, err
+			}
+			if changed && arr == nil {
+				arr = slices.Clone(v)
+			}
+			if changed {
+				arr[i] = n
+			}
+		}
+		if arr == nil {
+			return v, false, nil
+		}
+		return arr, true, nil
+	case map[string]interface{}:
+		var obj map[string]interface{}
+		for k, e := range v {
+			n, changed, err := normalize(e)
+			if err != nil {
+				return nil, false, err
+			}
+			if changed && obj == nil {
+				obj = maps.Clone(v)
+			}
+			if changed {
+				obj[k] = n
+			}
+		}
+		if obj == nil {
+			return v, false, nil
+		}
+		return obj, true, nil
+	case json.Number:
+		f, err := v.Float64()
+		if err != nil {
+			return nil, false, fmt.Errorf("jsonschema: invalid number %q: %w", v, err)
+		}
+		return f, true, nil
+	}
+	b, err := json.Marshal(v)
+	if err != nil {
+		return nil, false, fmt.Errorf("jsonschema: cannot normalize %T: %w", v, err)
+	}
+	var normalized interface{}
+	if err := json.Unmarshal(b, &normalized); err != nil {
+		return nil, false, fmt.Errorf("jsonschema: cannot normalize %T: %w", v, err)
+	}
+	return normalized, true, nil
+}
+
+// Int is the value of keywords which must be a non-negative integer, such as maxLength, decoded from any JSON number
+// with a zero fractional part, such as 2.0, as JSON Schema considers it an integer.
+type Int int
+
+func (i *Int) UnmarshalJSON(b []byte) error {
+	var n int
+	if err := json.Unmarshal(b, &n); err == nil {
+		*i = Int(n)
+		return nil
+	}
+	var f float64
+	if err := json.Unmarshal(b, &f); err != nil || f != math.Trunc(f) || f < math.MinInt || f > math.MaxInt {
+		return fmt.Errorf("jsonschema: cannot unmarshal %s into Int, want an integer", b)
+	}
+	*i = Int(f)
+	return nil
+}
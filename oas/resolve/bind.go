@@ -0,0 +1,183 @@
+package resolve
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"reflect"
+	"strings"
+)
+
+var contextType = reflect.TypeFor[context.Context]()
+
+// Bind recursively finds values of v with a WithContext(context.Context) method and calls it with ctx.
+//
+// Structs with a non-empty "$id" field change the base URI of ctx for themselves and their fields, and are
+// registered along with any "$anchor" and "$dynamicAnchor" field to the Resolver of ctx. v must be a pointer.
+func Bind(v any, ctx context.Context) {
+	bindAt(v, ctx, "")
+}
+
+// bindAt is Bind for v located at the JSON pointer ptr within the schema resource of the base URI of ctx.
+func bindAt(v any, ctx context.Context, ptr string) {
+	bind(reflect.ValueOf(v), ctx, make(map[uintptr]struct{}), ptr, false)
+}
+
+// bind binds v, located at ptr, which is an embedded field of a struct if embedded. The identifiers of embedded
+// fields are promoted to, and so registered as, the struct.
+func bind(v reflect.Value, ctx context.Context, visited map[uintptr]struct{}, ptr string, embedded bool) {
+	switch v.Kind() {
+	case reflect.Pointer:
+		if v.IsNil() {
+			return
+		}
+		if _, ok := visited[v.Pointer()]; ok {
+			return
+		}
+		visited[v.Pointer()] = struct{}{}
+		bind(v.Elem(), ctx, visited, ptr, false)
+	case reflect.Interface:
+		if !v.IsNil() && v.Elem().Kind() == reflect.Pointer {
+			bind(v.Elem(), ctx, visited, ptr, false)
+		}
+	case reflect.Struct:
+		if v.CanAddr() {
+			if !embedded {
+				ctx, ptr = bindIdentifiers(v, ctx, ptr)
+			}
+			if m := v.Addr().MethodByName("WithContext"); m.IsValid() && m.Type().NumIn() == 1 && m.Type().In(0) == contextType {
+				m.Call([]reflect.Value{reflect.ValueOf(ctx)})
+			}
+		}
+		for i := 0; i < v.NumField(); i++ {
+			f, sf := v.Field(i), v.Type().Field(i)
+			if !f.CanSet() || !mayBind(f.Type()) {
+				continue
+			}
+			if sf.Anonymous && sf.Tag.Get("json") == "" {
+				bind(f, ctx, visited, ptr, true)
+			} else if name, ok := jsonName(sf); ok {
+				bind(f, ctx, visited, ptr+"/"+escaper.Replace(name), false)
+			}
+		}
+	case reflect.Slice, reflect.Array:
+		if !mayBind(v.Type().Elem()) {
+			return
+		}
+		for i := 0; i < v.Len(); i++ {
+			bind(v.Index(i), ctx, visited, fmt.Sprintf("%s/%d", ptr, i), false)
+		}
+	case reflect.Map:
+		if !mayBind(v.Type().Elem()) {
+			return
+		}
+		// map values are not addressable, so bind a copy and put it back
+		it := v.MapRange()
+		for it.Next() {
+			p := reflect.New(it.Value().Type())
+			p.Elem().Set(it.Value())
+			bind(p.Elem(), ctx, visited, ptr+"/"+escaper.Replace(fmt.Sprint(it.Key().Interface())), false)
+			v.SetMapIndex(it.Key(), p.Elem())
+		}
+	default:
+	}
+}
+
+// Standalone binds document, a pointer, to the scope of a new Resolver loading other documents through loader, which
+// may be nil, and in which document is registered as identified by id, a URI reference which may be empty, so that
+// references within document resolve. The Resolver is returned to register other documents.
+func Standalone(document any, id string, loader Loader) *Resolver {
+	base, err := url.Parse(id)
+	if err != nil {
+		base = &url.URL{}
+	}
+	base = withoutFragment(base)
+	r := New(loader)
+	r.Register(base, document)
+	Bind(document, WithScope(context.Background(), r, base))
+	return r
+}
+
+// mayBind reports whether values of t may contain structs.
+func mayBind(t reflect.Type) bool {
+	switch t.Kind() {
+	case reflect.Pointer, reflect.Slice, reflect.Array, reflect.Map:
+		return mayBind(t.Elem())
+	case reflect.Struct:
+		return true
+	default:
+		return false
+	}
+}
+
+// bindIdentifiers registers v, located at ptr, by its "$id", "$anchor" and "$dynamicAnchor" fields and returns ctx
+// with the base URI of v, and the location of v within the schema resource of that base URI.
+//
+// The draft-04 "id" field is an "$id", and the plain name fragment of an "$id" is an "$anchor", as in draft-07 and
+// earlier.
+func bindIdentifiers(v reflect.Value, ctx context.Context, ptr string) (context.Context, string) {
+	r, base, ok := Scope(ctx)
+	if !ok {
+		return ctx, ptr
+	}
+	id := stringFieldByJsonName(v, "Id", "$id")
+	if id == "" {
+		id = stringFieldByJsonName(v, "LegacyId", "id") // draft-04
+	}
+	anchor := stringFieldByJsonName(v, "Anchor", "$anchor")
+	if u, err := url.Parse(id); id != "" && err == nil {
+		if u.Fragment != "" && !strings.HasPrefix(u.Fragment, "/") {
+			anchor = u.Fragment // a plain name fragment of draft-07 and earlier
+		}
+		if u = withoutFragment(u); u.String() != "" {
+			if base != nil {
+				u = base.ResolveReference(u)
+			}
+			base = u
+			ctx = WithScope(ctx, r, base)
+			ptr = ""
+			r.mu.Lock()
+			r.resources[base.String()] = v.Addr()
+			r.mu.Unlock()
+		}
+	}
+	if anchor != "" && base != nil {
+		key := withFragment(base, anchor).String()
+		r.mu.Lock()
+		r.anchors[key] = v.Addr()
+		r.locations[key] = withFragment(base, ptr).String()
+		r.mu.Unlock()
+	}
+	if anchor := stringFieldByJsonName(v, "DynamicAnchor", "$dynamicAnchor"); anchor != "" && base != nil {
+		key := withFragment(base, anchor).String()
+		r.mu.Lock()
+		r.anchors[key] = v.Addr()
+		r.dynamic[key] = v.Addr()
+		r.locations[key] = withFragment(base, ptr).String()
+		r.mu.Unlock()
+	}
+	return ctx, ptr
+}
+
+// jsonName returns the name encoding/json encodes the field as, if it is encoded.
+func jsonName(f reflect.StructField) (string, bool) {
+	tag := f.Tag.Get("json")
+	if tag == "-" {
+		return "", false
+	}
+	if name, _, _ := strings.Cut(tag, ","); name != "" {
+		return name, true
+	}
+	return f.Name, true
+}
+
+func stringFieldByJsonName(v reflect.Value, field string, name string) string {
+	sf, ok := v.Type().FieldByName(field)
+	if !ok || sf.Type.Kind() != reflect.String {
+		return ""
+	}
+	if tag := sf.Tag.Get("json"); tag != name && !(len(tag) > len(name) && tag[:len(name)+1] == name+",") {
+		return ""
+	}
+	return v.FieldByIndex(sf.Index).String()
+}
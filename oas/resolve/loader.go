@@ -0,0 +1,69 @@
+package resolve
+
+import (
+	"fmt"
+	"io/fs"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// Loader loads the raw content of the document identified by an absolute URI without fragment.
+//
+// The content may be either JSON or YAML.
+type Loader interface {
+	Load(uri *url.URL) ([]byte, error)
+}
+
+// LoaderFunc wraps a func so it satisfies the Loader interface.
+type LoaderFunc func(uri *url.URL) ([]byte, error)
+
+func (f LoaderFunc) Load(uri *url.URL) ([]byte, error) {
+	return f(uri)
+}
+
+// FileLoader loads "file" URIs from the file system of the operating system.
+type FileLoader struct{}
+
+func (FileLoader) Load(uri *url.URL) ([]byte, error) {
+	if uri.Scheme != "file" {
+		return nil, fmt.Errorf("resolve.FileLoader: unsupported scheme %q", uri.Scheme)
+	}
+	return os.ReadFile(filepath.FromSlash(uri.Path))
+}
+
+// FSLoader loads URIs with no scheme from a fs.FS, using the path of the URI relative to the root of the FS.
+type FSLoader struct {
+	FS fs.FS
+}
+
+func (l FSLoader) Load(uri *url.URL) ([]byte, error) {
+	if uri.Scheme != "" || uri.Host != "" {
+		return nil, fmt.Errorf("resolve.FSLoader: unsupported URI %q", uri)
+	}
+	return fs.ReadFile(l.FS, strings.TrimPrefix(uri.Path, "/"))
+}
+
+// MapLoader loads documents from memory by their URI.
+type MapLoader map[string][]byte
+
+func (l MapLoader) Load(uri *url.URL) ([]byte, error) {
+	if b, ok := l[uri.String()]; ok {
+		return b, nil
+	}
+	return nil, fmt.Errorf("resolve.MapLoader: %q: %w", uri, fs.ErrNotExist)
+}
+
+// FileUri returns the absolute "file" URI of filename.
+func FileUri(filename string) (*url.URL, error) {
+	abs, err := filepath.Abs(filename)
+	if err != nil {
+		return nil, err
+	}
+	uri := &url.URL{Scheme: "file", Path: filepath.ToSlash(abs)}
+	if strings.HasSuffix(filename, string(filepath.Separator)) || filename == "." {
+		uri.Path += "/"
+	}
+	return uri, nil
+}
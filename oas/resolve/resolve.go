@@ -0,0 +1,411 @@
+// Package resolve implements resolution of "$ref" URI references across documents.
+//
+// Documents are identified by absolute URIs, loaded through a Loader and cached by a Resolver. Values decoded from a
+// document are bound to a context holding the Resolver and the base URI used to resolve relative references.
+package resolve
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/url"
+	"reflect"
+	"slices"
+	"strings"
+	"sync"
+
+	"github.com/MaiMee1/go-apispec/oas/jsonpointer"
+	"github.com/MaiMee1/go-apispec/oas/ser"
+)
+
+// Error describes a reference that could not be resolved.
+type Error struct {
+	Ref  string
+	Base string
+	Err  error
+}
+
+func (e *Error) Error() string {
+	if e.Base == "" {
+		return fmt.Sprintf("resolve: %q: %v", e.Ref, e.Err)
+	}
+	return fmt.Sprintf("resolve: %q from %q: %v", e.Ref, e.Base, e.Err)
+}
+
+func (e *Error) Unwrap() error {
+	return e.Err
+}
+
+// Resolver loads, caches and indexes documents. It is safe for concurrent use.
+type Resolver struct {
+	loader Loader
+
+	mu        sync.Mutex
+	documents map[string]reflect.Value // document URI -> root value
+	resources map[string]reflect.Value // "$id" URI -> schema resource
+	anchors   map[string]reflect.Value // URI with plain name fragment -> value
+	dynamic   map[string]reflect.Value // URI with "$dynamicAnchor" fragment -> value
+	locations map[string]string        // URI with plain name fragment -> URI with JSON pointer fragment
+	converted map[convertedKey]any     // value converted by Ref
+}
+
+type convertedKey struct {
+	uri string
+	t   reflect.Type
+}
+
+// New returns a Resolver loading documents through loader, which may be nil to only resolve registered documents.
+func New(loader Loader) *Resolver {
+	return &Resolver{
+		loader:    loader,
+		documents: make(map[string]reflect.Value),
+		resources: make(map[string]reflect.Value),
+		anchors:   make(map[string]reflect.Value),
+		dynamic:   make(map[string]reflect.Value),
+		locations: make(map[string]string),
+		converted: make(map[convertedKey]any),
+	}
+}
+
+// Register adds document to the cache as the document identified by uri.
+//
+// If document is a pointer, references into it observe later changes to the document.
+func (r *Resolver) Register(uri *url.URL, document any) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.documents[withoutFragment(uri).String()] = reflect.ValueOf(document)
+}
+
+// Resolve returns the value referred to by ref relative to base, along with the base URI of the value.
+func (r *Resolver) Resolve(base *url.URL, ref string) (reflect.Value, *url.URL, error) {
+	v, uri, err := r.resolve(base, ref)
+	if err != nil {
+		var b string
+		if base != nil {
+			b = base.String()
+		}
+		return reflect.Value{}, nil, &Error{Ref: ref, Base: b, Err: err}
+	}
+	return v, uri, nil
+}
+
+func (r *Resolver) resolve(base *url.URL, ref string) (reflect.Value, *url.URL, error) {
+	u, err := url.Parse(ref)
+	if err != nil {
+		return reflect.Value{}, nil, err
+	}
+	target := u
+	if base != nil {
+		target = base.ResolveReference(u)
+	}
+	docUri := withoutFragment(target)
+
+	fragment := target.Fragment
+	if fragment != "" && !strings.HasPrefix(fragment, "/") {
+		r.mu.Lock()
+		v, ok := r.anchors[target.String()]
+		r.mu.Unlock()
+		if !ok {
+			// anchors are indexed when their document is loaded
+			if _, err := r.document(docUri); err != nil {
+				return reflect.Value{}, nil, err
+			}
+			r.mu.Lock()
+			v, ok = r.anchors[target.String()]
+			r.mu.Unlock()
+		}
+		if !ok {
+			return reflect.Value{}, nil, fmt.Errorf("anchor %q not found", fragment)
+		}
+		return v, docUri, nil
+	}
+
+	root, err := r.document(docUri)
+	if err != nil {
+		return reflect.Value{}, nil, err
+	}
+	v, err := jsonpointer.Ptr(fragment).Access(root.Interface())
+	if err != nil {
+		return reflect.Value{}, nil, err
+	}
+	return v, docUri, nil
+}
+
+// document returns the root value of the document or schema resource identified by uri, loading it if needed.
+func (r *Resolver) document(uri *url.URL) (reflect.Value, error) {
+	key := uri.String()
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if v, ok := r.documents[key]; ok {
+		return v, nil
+	}
+	if v, ok := r.resources[key]; ok {
+		return v, nil
+	}
+	if r.loader == nil {
+		return reflect.Value{}, fmt.Errorf("document %q not registered", key)
+	}
+
+	b, err := r.loader.Load(uri)
+	if err != nil {
+		return reflect.Value{}, err
+	}
+	if b, err = ser.ToJson(b); err != nil {
+		return reflect.Value{}, err
+	}
+	var document interface{}
+	if err := json.Unmarshal(b, &document); err != nil {
+		return reflect.Value{}, err
+	}
+	v := reflect.ValueOf(document)
+	r.documents[key] = v
+	r.index(uri, "", document)
+	return v, nil
+}
+
+// index records the "$id", "$anchor" and "$dynamicAnchor" of decoded JSON values, v being located at the JSON pointer
+// ptr within the schema resource of base. r.mu must be held.
+func (r *Resolver) index(base *url.URL, ptr string, v interface{}) {
+	switch v := v.(type) {
+	case map[string]interface{}:
+		anchor, _ := v["$anchor"].(string)
+		if id, ok := v["$id"].(string); ok && id != "" {
+			if u, err := url.Parse(id); err == nil {
+				if u.Fragment != "" && !strings.HasPrefix(u.Fragment, "/") {
+					anchor = u.Fragment // a plain name fragment of draft-07 and earlier
+				}
+				if u = withoutFragment(u); u.String() != "" {
+					base = base.ResolveReference(u)
+					ptr = ""
+					r.resources[base.String()] = reflect.ValueOf(v)
+				}
+			}
+		}
+		if anchor != "" {
+			key := withFragment(base, anchor).String()
+			r.anchors[key] = reflect.ValueOf(v)
+			r.locations[key] = withFragment(base, ptr).String()
+		}
+		if anchor, ok := v["$dynamicAnchor"].(string); ok && anchor != "" {
+			// a dynamic anchor is also a plain name fragment
+			key := withFragment(base, anchor).String()
+			r.anchors[key] = reflect.ValueOf(v)
+			r.dynamic[key] = reflect.ValueOf(v)
+			r.locations[key] = withFragment(base, ptr).String()
+		}
+		for k, e := range v {
+			r.index(base, ptr+"/"+escaper.Replace(k), e)
+		}
+	case []interface{}:
+		for i, e := range v {
+			r.index(base, fmt.Sprintf("%s/%d", ptr, i), e)
+		}
+	}
+}
+
+type scopeKey struct{}
+
+type scope struct {
+	resolver *Resolver
+	base     *url.URL
+}
+
+// WithScope returns a copy of ctx in which references are resolved by r relative to base.
+func WithScope(ctx context.Context, r *Resolver, base *url.URL) context.Context {
+	return context.WithValue(ctx, scopeKey{}, &scope{r, base})
+}
+
+// Scope returns the Resolver and base URI of ctx.
+func Scope(ctx context.Context) (r *Resolver, base *url.URL, ok bool) {
+	if ctx == nil {
+		return nil, nil, false
+	}
+	s, ok := ctx.Value(scopeKey{}).(*scope)
+	if !ok {
+		return nil, nil, false
+	}
+	return s.resolver, s.base, true
+}
+
+// Ref resolves ref in the scope of ctx and returns the value as S.
+//
+// Values of a different Go type, such as those of documents loaded by the Resolver, are converted through their JSON
+// encoding and bound to the scope of the document they were found in.
+func Ref[S any](ctx context.Context, ref string) (S, error) {
+	var s S
+	r, base, ok := Scope(ctx)
+	if !ok {
+		return s, &Error{Ref: ref, Err: errors.New("no resolver in context")}
+	}
+	v, uri, err := r.Resolve(base, ref)
+	if err != nil {
+		return s, err
+	}
+	key := ref
+	if u, err := url.Parse(ref); err == nil && base != nil {
+		key = base.ResolveReference(u).String()
+	}
+	return convert[S](ctx, r, v, uri, key)
+}
+
+// Canonical returns the absolute URI ref refers to in the scope of ctx. A plain name fragment is replaced by the JSON
+// pointer of the value it names within its schema resource, if the value was indexed or bound.
+func Canonical(ctx context.Context, ref string) string {
+	u, err := url.Parse(ref)
+	if err != nil {
+		return ref
+	}
+	r, base, ok := Scope(ctx)
+	if !ok {
+		return u.String()
+	}
+	if base != nil {
+		u = base.ResolveReference(u)
+	}
+	return r.canonical(u.String())
+}
+
+// canonical returns the URI with JSON pointer fragment of uri, an absolute URI.
+func (r *Resolver) canonical(uri string) string {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if location, ok := r.locations[uri]; ok {
+		return location
+	}
+	return uri
+}
+
+// DynamicRef resolves ref in the scope of ctx as a "$dynamicRef" evaluated in the dynamic scope of evaluation, see
+// EnterScope, and returns the value as S along with its Canonical URI.
+//
+// If ref refers to a "$dynamicAnchor", it resolves to the outermost schema resource of the dynamic scope with a
+// "$dynamicAnchor" of the same name, see https://json-schema.org/draft/2020-12/json-schema-core#section-8.2.3.2.
+// Otherwise, it resolves as Ref.
+func DynamicRef[S any](ctx context.Context, evaluation context.Context, ref string) (S, string, error) {
+	s, err := Ref[S](ctx, ref)
+	if err != nil {
+		return s, "", err
+	}
+	canonical := Canonical(ctx, ref)
+	r, base, _ := Scope(ctx)
+	u, err := url.Parse(ref)
+	if err != nil || u.Fragment == "" || strings.HasPrefix(u.Fragment, "/") {
+		return s, canonical, nil
+	}
+	if base != nil {
+		u = base.ResolveReference(u)
+	}
+	r.mu.Lock()
+	_, ok := r.dynamic[u.String()]
+	r.mu.Unlock()
+	if !ok {
+		return s, canonical, nil // the initial target has no such "$dynamicAnchor"
+	}
+	for _, resource := range dynamicScope(evaluation) {
+		key := withFragment(resource, u.Fragment).String()
+		r.mu.Lock()
+		v, ok := r.dynamic[key]
+		r.mu.Unlock()
+		if ok {
+			s, err := convert[S](ctx, r, v, resource, key)
+			return s, r.canonical(key), err
+		}
+	}
+	return s, canonical, nil
+}
+
+// convert returns v, found at uri by resolving the absolute reference key, as S.
+func convert[S any](ctx context.Context, r *Resolver, v reflect.Value, uri *url.URL, key string) (S, error) {
+	var s S
+	t := reflect.TypeFor[S]()
+	for v.Kind() == reflect.Interface && !v.IsNil() {
+		v = v.Elem()
+	}
+	switch {
+	case v.Type().AssignableTo(t):
+		return v.Interface().(S), nil
+	case v.Kind() == reflect.Pointer && v.Elem().Type().AssignableTo(t):
+		return v.Elem().Interface().(S), nil
+	case reflect.PointerTo(v.Type()).AssignableTo(t):
+		p := reflect.New(v.Type())
+		p.Elem().Set(v)
+		return p.Interface().(S), nil
+	}
+
+	// converted values are cached, so that references to the same value, including cyclic ones, resolve to the same
+	// pointers
+	cacheKey := convertedKey{uri: key, t: t}
+	r.mu.Lock()
+	c, ok := r.converted[cacheKey]
+	r.mu.Unlock()
+	if ok {
+		return c.(S), nil
+	}
+	b, err := json.Marshal(v.Interface())
+	if err != nil {
+		return s, &Error{Ref: key, Err: err}
+	}
+	if err := json.Unmarshal(b, &s); err != nil {
+		return s, &Error{Ref: key, Err: err}
+	}
+	var ptr string
+	if u, err := url.Parse(r.canonical(key)); err == nil && withoutFragment(u).String() == uri.String() {
+		ptr = u.Fragment
+	}
+	bindAt(&s, WithScope(ctx, r, uri), ptr)
+	r.mu.Lock()
+	r.converted[cacheKey] = s
+	r.mu.Unlock()
+	return s, nil
+}
+
+type dynamicScopeKey struct{}
+
+// resource is a schema resource entered during an evaluation.
+type resource struct {
+	base   *url.URL
+	parent *resource
+}
+
+// EnterScope returns a copy of evaluation, the context of evaluating a schema, in which the schema resource with the
+// base URI of ctx, the context the schema is bound to, is the innermost resource of the dynamic scope.
+//
+// evaluation is returned as is if ctx has no scope or the resource is already the innermost one.
+func EnterScope(evaluation context.Context, ctx context.Context) context.Context {
+	_, base, ok := Scope(ctx)
+	if !ok || base == nil {
+		return evaluation
+	}
+	parent, _ := evaluation.Value(dynamicScopeKey{}).(*resource)
+	if parent != nil && parent.base.String() == base.String() {
+		return evaluation
+	}
+	return context.WithValue(evaluation, dynamicScopeKey{}, &resource{base, parent})
+}
+
+// dynamicScope returns the base URIs of the resources of the dynamic scope of evaluation, outermost first.
+func dynamicScope(evaluation context.Context) []*url.URL {
+	var uris []*url.URL
+	for r, _ := evaluation.Value(dynamicScopeKey{}).(*resource); r != nil; r = r.parent {
+		uris = append(uris, r.base)
+	}
+	slices.Reverse(uris)
+	return uris
+}
+
+var escaper = strings.NewReplacer("~", "~0", "/", "~1")
+
+func withoutFragment(uri *url.URL) *url.URL {
+	u := *uri
+	u.Fragment = ""
+	u.RawFragment = ""
+	return &u
+}
+
+func withFragment(uri *url.URL, fragment string) *url.URL {
+	u := *uri
+	u.Fragment = fragment
+	u.RawFragment = ""
+	return &u
+}
@@ -0,0 +1,161 @@
+package resolve
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"net/url"
+	"testing"
+	"testing/fstest"
+)
+
+func TestResolver_Resolve(t *testing.T) {
+	loader := MapLoader{
+		"https://example.com/api.json":         []byte(`{"components":{"schemas":{"Pet":{"$ref":"schemas/pet.yaml#/Pet"}}}}`),
+		"https://example.com/schemas/pet.yaml": []byte("Pet:\n  type: object\nTag:\n  $id: https://example.com/tag\n  $defs:\n    name:\n      $anchor: name\n      type: string\n"),
+	}
+	r := New(loader)
+	base, _ := url.Parse("https://example.com/api.json")
+
+	var testCases = []struct {
+		base *url.URL
+		ref  string
+		want interface{}
+		err  error
+	}{
+		{base, "#/components/schemas/Pet/$ref", "schemas/pet.yaml#/Pet", nil},
+		{base, "schemas/pet.yaml#/Pet/type", "object", nil},
+		{base, "./schemas/pet.yaml#/Pet/type", "object", nil},
+		{base, "https://example.com/tag#/$defs/name/type", "string", nil},
+		{base, "https://example.com/tag#name", map[string]interface{}{"$anchor": "name", "type": "string"}, nil},
+		{base, "missing.json", nil, fs.ErrNotExist},
+	}
+	for i, tt := range testCases {
+		t.Run(fmt.Sprintf("%d", i), func(t *testing.T) {
+			v, _, err := r.Resolve(tt.base, tt.ref)
+			if !errors.Is(err, tt.err) {
+				t.Fatalf("got error %v, want %v", err, tt.err)
+			}
+			if err != nil {
+				return
+			}
+			if fmt.Sprint(v) != fmt.Sprint(tt.want) {
+				t.Errorf("got %v, want %v", v, tt.want)
+			}
+		})
+	}
+}
+
+type node struct {
+	Id   string           `json:"$id,omitempty"`
+	Ref  string           `json:"$ref,omitempty"`
+	Type string           `json:"type,omitempty"`
+	Defs map[string]*node `json:"$defs,omitempty"`
+
+	ctx context.Context
+}
+
+func (n *node) WithContext(ctx context.Context) {
+	n.ctx = ctx
+}
+
+func TestRef(t *testing.T) {
+	fsys := fstest.MapFS{
+		"api/root.json":     {Data: []byte(`{"$defs":{"a":{"$ref":"../common.json#/b"},"c":{"$id":"nested/","$ref":"d.json"}}}`)},
+		"common.json":       {Data: []byte(`{"b":{"type":"string","$ref":"#/e"},"e":{"type":"integer"}}`)},
+		"api/nested/d.json": {Data: []byte(`{"type":"boolean"}`)},
+	}
+	r := New(FSLoader{fsys})
+	base := &url.URL{Path: "api/root.json"}
+	root, err := Ref[*node](WithScope(context.TODO(), r, base), "")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	b, err := Ref[node](root.Defs["a"].ctx, root.Defs["a"].Ref)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if b.Type != "string" {
+		t.Errorf("got type %q, want %q", b.Type, "string")
+	}
+	e, err := Ref[node](b.ctx, b.Ref)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if e.Type != "integer" {
+		t.Errorf("got type %q, want %q", e.Type, "integer")
+	}
+	d, err := Ref[node](root.Defs["c"].ctx, root.Defs["c"].Ref)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if d.Type != "boolean" {
+		t.Errorf("got type %q, want %q", d.Type, "boolean")
+	}
+
+	if _, err := Ref[node](context.TODO(), "#"); err == nil {
+		t.Error("got error <nil>, want no resolver in context")
+	}
+}
+
+type dynamicNode struct {
+	Id            string                  `json:"$id,omitempty"`
+	DynamicAnchor string                  `json:"$dynamicAnchor,omitempty"`
+	Type          string                  `json:"type,omitempty"`
+	Defs          map[string]*dynamicNode `json:"$defs,omitempty"`
+
+	ctx context.Context
+}
+
+func (n *dynamicNode) WithContext(ctx context.Context) {
+	n.ctx = ctx
+}
+
+func TestDynamicRef(t *testing.T) {
+	root := &dynamicNode{
+		Id: "https://example.com/root",
+		Defs: map[string]*dynamicNode{
+			"item": {DynamicAnchor: "item", Type: "string"},
+			"list": {Id: "list", Defs: map[string]*dynamicNode{"item": {DynamicAnchor: "item"}}},
+			"tree": {Id: "tree", Defs: map[string]*dynamicNode{"node": {Type: "object"}}},
+		},
+	}
+	Standalone(root, root.Id, nil)
+	list := root.Defs["list"]
+
+	var testCases = []struct {
+		scope    []*dynamicNode // outermost first
+		ref      string
+		want     *dynamicNode
+		location string
+	}{
+		{[]*dynamicNode{list}, "#item", list.Defs["item"], "https://example.com/list#/$defs/item"},
+		{[]*dynamicNode{root, list}, "#item", root.Defs["item"], "https://example.com/root#/$defs/item"},
+		{[]*dynamicNode{root, root.Defs["tree"], list}, "#item", root.Defs["item"], "https://example.com/root#/$defs/item"},
+		{[]*dynamicNode{root, list}, "#/$defs/item", list.Defs["item"], "https://example.com/list#/$defs/item"},
+		{[]*dynamicNode{root, list}, "tree#/$defs/node", root.Defs["tree"].Defs["node"], "https://example.com/tree#/$defs/node"},
+	}
+	for i, tt := range testCases {
+		t.Run(fmt.Sprintf("%d", i), func(t *testing.T) {
+			evaluation := context.Background()
+			for _, n := range tt.scope {
+				evaluation = EnterScope(evaluation, n.ctx)
+			}
+
+			// Act
+			got, location, err := DynamicRef[*dynamicNode](list.ctx, evaluation, tt.ref)
+
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got != tt.want {
+				t.Errorf("got %+v, want %+v", got, tt.want)
+			}
+			if location != tt.location {
+				t.Errorf("got location %q, want %q", location, tt.location)
+			}
+		})
+	}
+}
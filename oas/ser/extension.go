@@ -0,0 +1,80 @@
+package ser
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+)
+
+// ExtensionPrefix is the prefix shared by all specification extension keys.
+const ExtensionPrefix = "x-"
+
+// MarshalExtended returns the JSON encoding of v with extensions inlined as members of the resulting object.
+//
+// Only keys prefixed by ExtensionPrefix are serialized so that other keys may be used to carry internal data.
+func MarshalExtended(v any, extensions map[string]interface{}) ([]byte, error) {
+	b, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+	if len(extensions) == 0 {
+		return b, nil
+	}
+	if len(b) < 2 || b[0] != '{' || b[len(b)-1] != '}' {
+		return nil, fmt.Errorf("ser: cannot inline extensions into %T, want JSON object", v)
+	}
+
+	buf := bytes.NewBuffer(b[:len(b)-1])
+	empty := len(b) == 2
+	for _, key := range slices.Sorted(maps.Keys(extensions)) {
+		if !strings.HasPrefix(key, ExtensionPrefix) {
+			continue
+		}
+		value, err := json.Marshal(extensions[key])
+		if err != nil {
+			return nil, fmt.Errorf("ser: extension %q: %w", key, err)
+		}
+		if !empty {
+			buf.WriteByte(',')
+		}
+		name, _ := json.Marshal(key)
+		buf.Write(name)
+		buf.WriteByte(':')
+		buf.Write(value)
+		empty = false
+	}
+	buf.WriteByte('}')
+	return buf.Bytes(), nil
+}
+
+// UnmarshalExtended parses b into v and returns the members of the JSON object prefixed by ExtensionPrefix.
+//
+// The returned map is nil when there are no such members.
+func UnmarshalExtended(b []byte, v any) (map[string]interface{}, error) {
+	if err := json.Unmarshal(b, v); err != nil {
+		return nil, err
+	}
+
+	var members map[string]json.RawMessage
+	if err := json.Unmarshal(b, &members); err != nil {
+		return nil, err
+	}
+	var extensions map[string]interface{}
+	for key, raw := range members {
+		if !strings.HasPrefix(key, ExtensionPrefix) {
+			continue
+		}
+		var value interface{}
+		if err := json.Unmarshal(raw, &value); err != nil {
+			return nil, err
+		}
+		if extensions == nil {
+			extensions = make(map[string]interface{})
+		}
+		extensions[key] = value
+	}
+	return extensions, nil
+}
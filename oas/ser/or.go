@@ -9,10 +9,11 @@ import (
 
 // Or allows two types to be serializable or deserialized to one value.
 //
-// Type A is tried first before type B for both serialization and deserialization.
+// Type A is tried first before type B for both serialization and deserialization, so a value which decodes as A, such
+// as true for Or[bool, *Schema] of a Schema which also decodes boolean schemas, is not decoded as B.
 type Or[A, B any] struct {
-	X A
-	Y B
+	X A `json:",inline"`
+	Y B `json:",inline"`
 }
 
 //goland:noinspection GoMixedReceiverTypes
@@ -24,14 +25,12 @@ func (o *Or[A, B]) UnmarshalJSON(b []byte) error {
 	err1 := json.Unmarshal(b, &x)
 	if err1 == nil {
 		o.X = x
+		return nil
 	}
 
 	err2 := json.Unmarshal(b, &y)
 	if err2 == nil {
 		o.Y = y
-	}
-
-	if err2 == nil || err1 == nil {
 		return nil
 	}
 	return fmt.Errorf("or[%T, %T]: %w: %w", x, y, err2, err1)
@@ -39,8 +38,12 @@ func (o *Or[A, B]) UnmarshalJSON(b []byte) error {
 
 //goland:noinspection GoMixedReceiverTypes
 func (o Or[A, B]) MarshalJSON() ([]byte, error) {
-	var x A
-	if !reflect.DeepEqual(o.X, x) {
+	var (
+		x A
+		y B
+	)
+	// X is also the zero value of both, such as false rather than null for Or[bool, *Schema]
+	if !reflect.DeepEqual(o.X, x) || reflect.DeepEqual(o.Y, y) {
 		return json.Marshal(o.X)
 	}
 	return json.Marshal(o.Y)
@@ -0,0 +1,28 @@
+package ser
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+)
+
+// Text is a string which also accepts a JSON number, keeping its text, as documents often leave values such as
+// versions unquoted in YAML.
+type Text string
+
+//goland:noinspection GoMixedReceiverTypes
+func (t *Text) UnmarshalJSON(b []byte) error {
+	var s string
+	if err := json.Unmarshal(b, &s); err == nil {
+		*t = Text(s)
+		return nil
+	}
+	var n json.Number
+	dec := json.NewDecoder(bytes.NewReader(b))
+	dec.UseNumber()
+	if err := dec.Decode(&n); err != nil {
+		return fmt.Errorf("ser: cannot unmarshal %s into Text, want string or number", b)
+	}
+	*t = Text(n)
+	return nil
+}
@@ -0,0 +1,289 @@
+package ser
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"strconv"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+const yamlMergeKey = "<<"
+
+// JsonToYaml converts a JSON document to a YAML node.
+//
+// Members of objects keep their order in b, and multi-line strings use the literal style for readability.
+func JsonToYaml(b []byte) (*yaml.Node, error) {
+	dec := json.NewDecoder(bytes.NewReader(b))
+	dec.UseNumber()
+	node, err := jsonToYaml(dec)
+	if err != nil {
+		return nil, fmt.Errorf("ser: %w", err)
+	}
+	if _, err := dec.Token(); err != io.EOF {
+		return nil, errors.New("ser: invalid JSON, want single value")
+	}
+	return node, nil
+}
+
+func jsonToYaml(dec *json.Decoder) (*yaml.Node, error) {
+	tok, err := dec.Token()
+	if err != nil {
+		return nil, err
+	}
+	switch v := tok.(type) {
+	case json.Delim:
+		switch v {
+		case '{':
+			node := &yaml.Node{Kind: yaml.MappingNode, Tag: "!!map"}
+			for dec.More() {
+				tok, err := dec.Token()
+				if err != nil {
+					return nil, err
+				}
+				key := &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: tok.(string)}
+				value, err := jsonToYaml(dec)
+				if err != nil {
+					return nil, err
+				}
+				node.Content = append(node.Content, key, value)
+			}
+			_, err = dec.Token()
+			return node, err
+		case '[':
+			node := &yaml.Node{Kind: yaml.SequenceNode, Tag: "!!seq"}
+			for dec.More() {
+				value, err := jsonToYaml(dec)
+				if err != nil {
+					return nil, err
+				}
+				node.Content = append(node.Content, value)
+			}
+			_, err = dec.Token()
+			return node, err
+		}
+		return nil, fmt.Errorf("unexpected delimiter %v", v)
+	case string:
+		node := &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: v}
+		if bytes.ContainsRune([]byte(v), '\n') {
+			node.Style = yaml.LiteralStyle
+		}
+		return node, nil
+	case json.Number:
+		tag := "!!int"
+		if _, err := v.Int64(); err != nil {
+			tag = "!!float"
+		}
+		return &yaml.Node{Kind: yaml.ScalarNode, Tag: tag, Value: v.String()}, nil
+	case bool:
+		return &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!bool", Value: strconv.FormatBool(v)}, nil
+	case nil:
+		return &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!null", Value: "null"}, nil
+	}
+	return nil, fmt.Errorf("unexpected token %v", tok)
+}
+
+// YamlToJson converts a YAML node to a JSON document.
+//
+// Members of mappings keep their order in node. Mapping keys are converted to strings, timestamps are kept as
+// strings, and aliases and merge keys are expanded.
+func YamlToJson(node *yaml.Node) ([]byte, error) {
+	var buf bytes.Buffer
+	if err := yamlToJson(&buf, node); err != nil {
+		return nil, fmt.Errorf("ser: %w", err)
+	}
+	return buf.Bytes(), nil
+}
+
+func yamlToJson(buf *bytes.Buffer, node *yaml.Node) error {
+	switch node.Kind {
+	case yaml.DocumentNode:
+		if len(node.Content) == 0 {
+			buf.WriteString("null")
+			return nil
+		}
+		return yamlToJson(buf, node.Content[0])
+	case yaml.AliasNode:
+		return yamlToJson(buf, node.Alias)
+	case yaml.MappingNode:
+		members, err := yamlMembers(node)
+		if err != nil {
+			return err
+		}
+		buf.WriteByte('{')
+		for i := 0; i < len(members); i += 2 {
+			if i > 0 {
+				buf.WriteByte(',')
+			}
+			key, _ := json.Marshal(members[i].Value)
+			buf.Write(key)
+			buf.WriteByte(':')
+			if err := yamlToJson(buf, members[i+1]); err != nil {
+				return err
+			}
+		}
+		buf.WriteByte('}')
+		return nil
+	case yaml.SequenceNode:
+		buf.WriteByte('[')
+		for i, item := range node.Content {
+			if i > 0 {
+				buf.WriteByte(',')
+			}
+			if err := yamlToJson(buf, item); err != nil {
+				return err
+			}
+		}
+		buf.WriteByte(']')
+		return nil
+	case yaml.ScalarNode:
+		return yamlScalarToJson(buf, node)
+	}
+	return fmt.Errorf("line %d: unexpected YAML node kind %v", node.Line, node.Kind)
+}
+
+// yamlMembers returns the key and value nodes of a mapping with merge keys expanded and duplicate keys removed.
+//
+// Explicit keys take precedence over merged keys regardless of order.
+func yamlMembers(node *yaml.Node) ([]*yaml.Node, error) {
+	var members []*yaml.Node
+	index := make(map[string]int)
+	set := func(key, value *yaml.Node, override bool) {
+		if i, ok := index[key.Value]; ok {
+			if override {
+				members[i+1] = value
+			}
+			return
+		}
+		index[key.Value] = len(members)
+		members = append(members, key, value)
+	}
+
+	var merged []*yaml.Node
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		key, value := node.Content[i], node.Content[i+1]
+		if key.Kind == yaml.AliasNode {
+			key = key.Alias
+		}
+		if key.Kind != yaml.ScalarNode {
+			return nil, fmt.Errorf("line %d: unsupported non-scalar mapping key", key.Line)
+		}
+		if key.Value == yamlMergeKey && key.ShortTag() == "!!merge" {
+			merged = append(merged, value)
+			continue
+		}
+		set(key, value, true)
+	}
+	for _, value := range merged {
+		if value.Kind == yaml.AliasNode {
+			value = value.Alias
+		}
+		var sources []*yaml.Node
+		switch value.Kind {
+		case yaml.MappingNode:
+			sources = []*yaml.Node{value}
+		case yaml.SequenceNode:
+			sources = value.Content
+		default:
+			return nil, fmt.Errorf("line %d: merge key requires a mapping or sequence of mappings", value.Line)
+		}
+		for _, source := range sources {
+			if source.Kind == yaml.AliasNode {
+				source = source.Alias
+			}
+			if source.Kind != yaml.MappingNode {
+				return nil, fmt.Errorf("line %d: merge key requires a mapping or sequence of mappings", source.Line)
+			}
+			sourceMembers, err := yamlMembers(source)
+			if err != nil {
+				return nil, err
+			}
+			for i := 0; i < len(sourceMembers); i += 2 {
+				set(sourceMembers[i], sourceMembers[i+1], false)
+			}
+		}
+	}
+	return members, nil
+}
+
+func yamlScalarToJson(buf *bytes.Buffer, node *yaml.Node) error {
+	tag := node.ShortTag()
+	// a plain number which is already in JSON syntax keeps its text, such as 1.0 for a version
+	if (tag == "!!int" || tag == "!!float") && node.Style == 0 && isJsonNumber(node.Value) {
+		buf.WriteString(node.Value)
+		return nil
+	}
+	switch tag {
+	case "!!null":
+		buf.WriteString("null")
+		return nil
+	case "!!bool":
+		var v bool
+		if err := node.Decode(&v); err != nil {
+			return err
+		}
+		buf.WriteString(strconv.FormatBool(v))
+		return nil
+	case "!!int":
+		var v interface{}
+		if err := node.Decode(&v); err != nil {
+			return err
+		}
+		b, err := json.Marshal(v)
+		if err != nil {
+			return err
+		}
+		buf.Write(b)
+		return nil
+	case "!!float":
+		var v float64
+		if err := node.Decode(&v); err != nil {
+			return err
+		}
+		if math.IsInf(v, 0) || math.IsNaN(v) {
+			return fmt.Errorf("line %d: %s cannot be represented in JSON", node.Line, node.Value)
+		}
+		b, err := json.Marshal(v)
+		if err != nil {
+			return err
+		}
+		buf.Write(b)
+		return nil
+	case "!!str", "!!timestamp", "!!binary":
+		b, _ := json.Marshal(node.Value)
+		buf.Write(b)
+		return nil
+	}
+	return fmt.Errorf("line %d: unsupported YAML tag %s", node.Line, tag)
+}
+
+// isJsonNumber reports whether s is a number in JSON syntax.
+func isJsonNumber(s string) bool {
+	var n json.Number
+	dec := json.NewDecoder(strings.NewReader(s))
+	dec.UseNumber()
+	return dec.Decode(&n) == nil && !dec.More()
+}
+
+// ToJson returns b unchanged if it looks like a JSON object or array, and converts it from YAML otherwise.
+func ToJson(b []byte) ([]byte, error) {
+	if IsJson(b) {
+		return b, nil
+	}
+	var node yaml.Node
+	if err := yaml.Unmarshal(b, &node); err != nil {
+		return nil, err
+	}
+	return YamlToJson(&node)
+}
+
+// IsJson reports whether b looks like a JSON object or array rather than YAML.
+func IsJson(b []byte) bool {
+	b = bytes.TrimLeft(b, " \t\r\n")
+	return len(b) > 0 && (b[0] == '{' || b[0] == '[')
+}
@@ -0,0 +1,287 @@
+package oas
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/url"
+	"path"
+	"reflect"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/MaiMee1/go-apispec/oas/jsonpointer"
+	"github.com/MaiMee1/go-apispec/oas/resolve"
+)
+
+// componentsKeys maps referable types to the name of their Components field.
+var componentsKeys = map[reflect.Type]string{
+	reflect.TypeFor[Schema]():      "schemas",
+	reflect.TypeFor[Response]():    "responses",
+	reflect.TypeFor[Parameter]():   "parameters",
+	reflect.TypeFor[Example]():     "examples",
+	reflect.TypeFor[RequestBody](): "requestBodies",
+	reflect.TypeFor[Header]():      "headers",
+	reflect.TypeFor[Link]():        "links",
+	reflect.TypeFor[Callback]():    "callbacks",
+	reflect.TypeFor[PathItem]():    "pathItems",
+}
+
+// Bundle moves the values referred to in other documents into Components and rewrites all references to point to
+// them, making doc self-contained. As OpenAPI 3.0 has no path items in Components, path items referred to by an
+// OpenAPI 3.0 document are copied in place of their references instead.
+//
+// Components are named after the last token of the reference, or the document name when the reference has no
+// fragment, and suffixed with a number when the name is taken.
+func (doc *OpenAPI) Bundle() error {
+	_, base, ok := resolve.Scope(doc.ctx)
+	if !ok {
+		return errors.New("oas.OpenAPI: no resolver in context, see Load")
+	}
+	b := &bundler{
+		doc:     doc,
+		base:    base.String(),
+		refs:    make(map[string]string),
+		inlined: make(map[string][]byte),
+		names:   make(map[string]struct{}),
+	}
+	for key, m := range doc.components() {
+		for _, name := range m.MapKeys() {
+			b.names[key+"/"+name.String()] = struct{}{}
+		}
+	}
+	if err := walkRefs(reflect.ValueOf(doc).Elem(), nil, b.bundle); err != nil {
+		return err
+	}
+	for _, p := range b.pending {
+		m := doc.components()[p.key]
+		if m.IsNil() {
+			m.Set(reflect.MakeMap(m.Type()))
+		}
+		m.SetMapIndex(reflect.ValueOf(p.name), p.value)
+	}
+	resolve.Bind(doc, doc.ctx)
+	return nil
+}
+
+type bundler struct {
+	doc     *OpenAPI
+	base    string
+	refs    map[string]string   // absolute URI -> local reference
+	inlined map[string][]byte   // absolute URI -> JSON encoding of a path item copied in place
+	names   map[string]struct{} // "key/name" of taken component names
+	pending []component
+}
+
+type component struct {
+	key   string
+	name  string
+	value reflect.Value
+}
+
+func (b *bundler) bundle(v reflect.Value, loc []string) error {
+	field := v.FieldByName("Ref")
+	target, err := refTarget(v)
+	if err != nil {
+		return err
+	}
+	doc := *target
+	doc.Fragment, doc.RawFragment = "", ""
+
+	if doc.String() == b.base {
+		field.SetString("#" + target.EscapedFragment())
+		return walkFields(v, loc, b.bundle)
+	}
+	if ref, ok := b.refs[target.String()]; ok {
+		field.SetString(ref)
+		return walkFields(v, loc, b.bundle)
+	}
+
+	if v.Type() == reflect.TypeFor[PathItem]() && b.doc.Version.Major() == 3 && b.doc.Version.Minor() == 0 {
+		return b.inline(v, target, loc)
+	}
+
+	key, ok := componentsKeys[v.Type()]
+	if !ok {
+		return fmt.Errorf("oas.OpenAPI: cannot bundle %q into components, unsupported type %v", field.String(), v.Type())
+	}
+	name := b.name(key, target)
+	ref := fmt.Sprintf("#/components/%s/%s", key, name)
+	b.refs[target.String()] = ref
+
+	value, err := resolveValue(v)
+	if err != nil {
+		return err
+	}
+	if err := walkRefs(value, loc, b.bundle); err != nil {
+		return err
+	}
+	b.pending = append(b.pending, component{key, name, value})
+
+	field.SetString(ref)
+	return walkFields(v, loc, b.bundle)
+}
+
+// inline replaces v, which refers to target, with a copy of the value referred to, whose references are bundled.
+func (b *bundler) inline(v reflect.Value, target *url.URL, loc []string) error {
+	data, ok := b.inlined[target.String()]
+	if !ok {
+		value, err := resolveValue(v)
+		if err != nil {
+			return err
+		}
+		if err := walkRefs(value, loc, b.bundle); err != nil {
+			return err
+		}
+		if data, err = json.Marshal(value.Interface()); err != nil {
+			return err
+		}
+		b.inlined[target.String()] = data
+	}
+	p := reflect.New(v.Type())
+	if err := json.Unmarshal(data, p.Interface()); err != nil {
+		return err
+	}
+	v.Set(p.Elem())
+	return nil
+}
+
+// name returns an unused name for a component of key referred to by target.
+func (b *bundler) name(key string, target *url.URL) string {
+	name := target.Fragment // a plain name
+	if tokens, err := jsonpointer.Ptr(target.Fragment).Tokens(); err == nil && len(tokens) > 0 {
+		name = tokens[len(tokens)-1]
+	}
+	if name == "" {
+		name = strings.TrimSuffix(path.Base(target.Path), path.Ext(target.Path))
+	}
+	if !isValidComponentsKey(name) {
+		name = strings.Map(func(r rune) rune {
+			if isValidComponentsKey(string(r)) {
+				return r
+			}
+			return '_'
+		}, name)
+	}
+
+	unique := name
+	for i := 2; ; i++ {
+		if _, ok := b.names[key+"/"+unique]; !ok {
+			break
+		}
+		unique = name + "_" + strconv.Itoa(i)
+	}
+	b.names[key+"/"+unique] = struct{}{}
+	return unique
+}
+
+// Dereference bundles doc and replaces all references with a copy of the values they refer to.
+//
+// References to a value from within itself are left intact, pointing into Components. A schema with keywords next to
+// "$ref" keeps them and applies the value referred to through "allOf" instead.
+func (doc *OpenAPI) Dereference() error {
+	if err := doc.Bundle(); err != nil {
+		return err
+	}
+	_, base, _ := resolve.Scope(doc.ctx)
+	d := &dereferencer{ctx: doc.ctx, base: base}
+
+	v := reflect.ValueOf(doc).Elem()
+	for i := 0; i < v.NumField(); i++ {
+		if f := v.Field(i); f.CanSet() && f.Type() != reflect.TypeFor[Components]() {
+			if err := walkRefs(f, nil, d.dereference); err != nil {
+				return err
+			}
+		}
+	}
+	for key, m := range doc.components() {
+		// a component is an ancestor of its own value
+		it := m.MapRange()
+		for it.Next() {
+			uri := base.ResolveReference(&url.URL{Fragment: "/components/" + key + "/" + it.Key().String()})
+			d.stack = append(d.stack, uri.String())
+			p := reflect.New(it.Value().Type()).Elem()
+			p.Set(it.Value())
+			if err := walkRefs(p, nil, d.dereference); err != nil {
+				return err
+			}
+			m.SetMapIndex(it.Key(), p)
+			d.stack = d.stack[:len(d.stack)-1]
+		}
+	}
+	return nil
+}
+
+type dereferencer struct {
+	ctx   context.Context
+	base  *url.URL
+	stack []string // absolute URIs of the values being dereferenced
+}
+
+func (d *dereferencer) dereference(v reflect.Value, loc []string) error {
+	target, err := refTarget(v)
+	if err != nil {
+		return err
+	}
+	if slices.Contains(d.stack, target.String()) {
+		return walkFields(v, loc, d.dereference)
+	}
+
+	value, err := resolveValue(v)
+	if err != nil {
+		return err
+	}
+	// copy so that dereferencing does not change the value referred to
+	b, err := json.Marshal(value.Interface())
+	if err != nil {
+		return err
+	}
+	p := reflect.New(value.Type())
+	if err := json.Unmarshal(b, p.Interface()); err != nil {
+		return err
+	}
+	resolve.Bind(p.Interface(), d.ctx)
+
+	d.stack = append(d.stack, target.String())
+	defer func() { d.stack = d.stack[:len(d.stack)-1] }()
+
+	if schema, ok := v.Addr().Interface().(*Schema); ok && !isRefOnly(schema) {
+		schema.Ref = ""
+		schema.AllOf = append(schema.AllOf, p.Interface().(*Schema))
+		return walkFields(v, loc, d.dereference)
+	}
+	if err := walkRefs(p.Elem(), loc, d.dereference); err != nil {
+		return err
+	}
+	v.Set(p.Elem())
+	return nil
+}
+
+// isRefOnly reports whether schema has no keyword other than "$ref".
+func isRefOnly(schema *Schema) bool {
+	b, err := json.Marshal(schema)
+	if err != nil {
+		return false
+	}
+	var m map[string]json.RawMessage
+	if err := json.Unmarshal(b, &m); err != nil {
+		return false
+	}
+	return len(m) == 1
+}
+
+// components returns the settable maps of doc.Components by their JSON name.
+func (doc *OpenAPI) components() map[string]reflect.Value {
+	v := reflect.ValueOf(&doc.Components).Elem()
+	m := make(map[string]reflect.Value)
+	for i := 0; i < v.NumField(); i++ {
+		if v.Field(i).Kind() != reflect.Map || v.Type().Field(i).Tag.Get("json") == "-" {
+			continue
+		}
+		name, _, _ := strings.Cut(v.Type().Field(i).Tag.Get("json"), ",")
+		m[name] = v.Field(i)
+	}
+	return m
+}
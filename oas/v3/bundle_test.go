@@ -0,0 +1,141 @@
+package oas
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+func TestOpenAPI_Bundle(t *testing.T) {
+	document, err := New("testdata/split/openapi.yaml")
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Act
+	if err := document.Bundle(); err != nil {
+		t.Fatal(err)
+	}
+
+	b, err := json.Marshal(document)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(string(b), ".yaml") || strings.Contains(string(b), ".json") {
+		t.Errorf("got external references in %s", b)
+	}
+	for _, name := range []string{"Pet", "Category", "Owner"} {
+		if _, ok := document.Components.Schemas[name]; !ok {
+			t.Errorf("got schemas %v, want %s", document.Components.Schemas, name)
+		}
+	}
+	if _, ok := document.Components.Responses["Error"]; !ok {
+		t.Errorf("got responses %v, want Error", document.Components.Responses)
+	}
+
+	bundled, err := ParseJson(b)
+	if err != nil {
+		t.Fatal(err)
+	}
+	pet, err := bundled.Components.Schemas["Pet"].Properties["owner"].Resolve()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := pet.Properties["name"]; !ok {
+		t.Errorf("got properties %v, want name", pet.Properties)
+	}
+}
+
+func TestOpenAPI_Bundle_v30(t *testing.T) {
+	document, err := New("testdata/split30/openapi.yaml")
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Act
+	if err := document.Bundle(); err != nil {
+		t.Fatal(err)
+	}
+
+	if len(document.Components.PathItems) != 0 {
+		t.Errorf("got path items %v, want none in OpenAPI 3.0", document.Components.PathItems)
+	}
+	tests := []struct {
+		path        string
+		operationId string
+		schemaRef   string
+	}{
+		{"/pet", "getPet", "#/components/schemas/Pet"},
+		{"/pets/{id}", "getPetById", "#/components/schemas/Pet"},
+	}
+	for _, tt := range tests {
+		item := document.Paths[tt.path]
+		if item.Ref != "" || item.Get == nil || item.Get.OperationId != tt.operationId {
+			t.Errorf("got %s %+v, want operation %s in place", tt.path, item, tt.operationId)
+			continue
+		}
+		if ref := item.Get.Responses["200"].Content["application/json"].Schema.Ref; ref != tt.schemaRef {
+			t.Errorf("got %s schema $ref %q, want %q", tt.path, ref, tt.schemaRef)
+		}
+	}
+	if err := document.Validate(); err != nil {
+		t.Error(err)
+	}
+	b, err := json.Marshal(document)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(string(b), ".yaml") || strings.Contains(string(b), ".json") {
+		t.Errorf("got external references in %s", b)
+	}
+	if _, err := ParseJson(b); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestOpenAPI_Bundle_Collision(t *testing.T) {
+	document, err := New("testdata/split/recursive.yaml")
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Act
+	if err := document.Bundle(); err != nil {
+		t.Fatal(err)
+	}
+
+	pet := document.Components.Schemas["Node"].Properties["pet"]
+	if pet.Ref != "#/components/schemas/Pet_2" {
+		t.Errorf("got $ref %q, want %q", pet.Ref, "#/components/schemas/Pet_2")
+	}
+	if _, ok := document.Components.Schemas["Pet_2"]; !ok {
+		t.Errorf("got schemas %v, want Pet_2", document.Components.Schemas)
+	}
+	owner := document.Components.Schemas["Node"].Properties["owner"]
+	if owner.Ref != "#/components/schemas/pets_owner" {
+		t.Errorf("got $ref %q, want %q", owner.Ref, "#/components/schemas/pets_owner")
+	}
+}
+
+func TestOpenAPI_Dereference(t *testing.T) {
+	document, err := New("testdata/split/recursive.yaml")
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Act
+	if err := document.Dereference(); err != nil {
+		t.Fatal(err)
+	}
+
+	schema := document.Paths["/tree"].Get.Responses["200"].Content["application/json"].Schema
+	if schema.Ref != "" {
+		t.Errorf("got $ref %q, want inlined", schema.Ref)
+	}
+	if pet := schema.Properties["pet"]; pet.Ref != "" || len(pet.Properties) == 0 {
+		t.Errorf("got pet %+v, want inlined", pet)
+	}
+	if items := schema.Properties["children"].Items.Y; items.Ref != "#/components/schemas/Node" {
+		t.Errorf("got $ref %q, want recursive reference intact", items.Ref)
+	}
+	node := document.Components.Schemas["Node"]
+	if items := node.Properties["children"].Items.Y; items.Ref != "#/components/schemas/Node" {
+		t.Errorf("got $ref %q, want recursive reference intact", items.Ref)
+	}
+}
@@ -0,0 +1,471 @@
+package oas
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"maps"
+	"slices"
+	"strconv"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/MaiMee1/go-apispec/oas/jsonpointer"
+	"github.com/MaiMee1/go-apispec/oas/ser"
+)
+
+// Warning describes a lossy conversion at a location of the document, see Upgrade and OpenAPI.Downgrade.
+type Warning struct {
+	Location jsonpointer.Ptr
+	Message  string
+}
+
+func (w *Warning) String() string {
+	return fmt.Sprintf("%q: %s", w.Location, w.Message)
+}
+
+// Upgrade parses the OpenAPI 3.0 document b, in either JSON or YAML, as an OpenAPI 3.1 document. References to other
+// files are resolved relative to the working directory.
+//
+// The Schema Objects are converted from the OpenAPI 3.0 dialect: nullable becomes a "null" type, example becomes
+// examples, the boolean exclusiveMaximum and exclusiveMinimum become numbers, and the binary and byte formats become
+// contentMediaType and contentEncoding. Keywords next to "$ref", which OpenAPI 3.0 ignores, are dropped with a warning
+// unless they are annotations.
+//
+// As Parse, the document is returned along with the error of OpenAPI.Validate.
+func Upgrade(b []byte) (*OpenAPI, []*Warning, error) {
+	b, err := ser.ToJson(b)
+	if err != nil {
+		return nil, nil, err
+	}
+	var document map[string]interface{}
+	if err := json.Unmarshal(b, &document); err != nil {
+		return nil, nil, err
+	}
+	if version, _ := document["openapi"].(string); SemanticVersion(version).Major() != 3 || SemanticVersion(version).Minor() != 0 {
+		return nil, nil, fmt.Errorf("oas: cannot upgrade OpenAPI %q, want 3.0", version)
+	}
+	c := &converter{}
+	c.walk(document, nil, c.upgradeSchema)
+	document["openapi"] = "3.1.0"
+	if b, err = json.Marshal(document); err != nil {
+		return nil, nil, err
+	}
+	doc, err := ParseJson(b)
+	return doc, c.warnings, err
+}
+
+// Downgrade returns the JSON encoding of doc as an OpenAPI 3.0.3 document, for tools which only read OpenAPI 3.0.
+//
+// The Schema Objects are converted to the OpenAPI 3.0 dialect, reversing Upgrade. What OpenAPI 3.0 cannot express,
+// such as webhooks, type arrays other than with "null", or keywords added after draft-04, is dropped or loosened with a
+// warning.
+func (doc *OpenAPI) Downgrade() ([]byte, []*Warning, error) {
+	b, err := json.Marshal(doc)
+	if err != nil {
+		return nil, nil, err
+	}
+	var document map[string]interface{}
+	if err := json.Unmarshal(b, &document); err != nil {
+		return nil, nil, err
+	}
+	c := &converter{}
+	if _, ok := document["webhooks"]; ok {
+		delete(document, "webhooks")
+		c.warn([]string{"webhooks"}, "webhooks are not supported by OpenAPI 3.0 and dropped")
+	}
+	if _, ok := document["paths"]; !ok {
+		document["paths"] = map[string]interface{}{} // required by OpenAPI 3.0
+	}
+	if components, ok := document["components"].(map[string]interface{}); ok {
+		if _, ok := components["pathItems"]; ok {
+			delete(components, "pathItems")
+			c.warn([]string{"components", "pathItems"}, "path items are not supported by OpenAPI 3.0 components and dropped")
+		}
+		schemes, _ := components["securitySchemes"].(map[string]interface{})
+		for _, name := range slices.Sorted(maps.Keys(schemes)) {
+			if scheme, _ := schemes[name].(map[string]interface{}); scheme["type"] == "mutualTLS" {
+				delete(schemes, name)
+				c.warn([]string{"components", "securitySchemes", name}, "the mutualTLS security scheme is not supported by OpenAPI 3.0 and dropped")
+			}
+		}
+	}
+	c.walk(document, nil, c.downgradeSchema)
+	document["openapi"] = "3.0.3"
+	b, err = json.Marshal(document)
+	if err != nil {
+		return nil, nil, err
+	}
+	return b, c.warnings, nil
+}
+
+// converter converts the Schema Objects of a decoded OpenAPI document in place, collecting warnings.
+type converter struct {
+	warnings []*Warning
+}
+
+func (c *converter) warn(loc []string, format string, args ...any) {
+	c.warnings = append(c.warnings, &Warning{jsonpointer.New(loc...), fmt.Sprintf(format, args...)})
+}
+
+// walk calls convert with the Schema Objects of v, located at loc, and their subschemas. Schema Objects are the
+// values of "schema" members and of the schemas of the components. Example values and extensions are not walked.
+func (c *converter) walk(v interface{}, loc []string, convert func(s map[string]interface{}, loc []string)) {
+	switch v := v.(type) {
+	case map[string]interface{}:
+		for _, k := range slices.Sorted(maps.Keys(v)) {
+			l := append(slices.Clip(loc), k)
+			switch {
+			case strings.HasPrefix(k, "x-"), k == "example", k == "examples", k == "links":
+			case k == "schema":
+				if s, ok := v[k].(map[string]interface{}); ok {
+					c.schema(s, l, convert)
+				}
+			case k == "schemas" && slices.Equal(loc, []string{"components"}):
+				schemas, _ := v[k].(map[string]interface{})
+				for _, name := range slices.Sorted(maps.Keys(schemas)) {
+					if s, ok := schemas[name].(map[string]interface{}); ok {
+						c.schema(s, append(slices.Clip(l), name), convert)
+					}
+				}
+			default:
+				c.walk(v[k], l, convert)
+			}
+		}
+	case []interface{}:
+		for i, e := range v {
+			c.walk(e, append(slices.Clip(loc), strconv.Itoa(i)), convert)
+		}
+	}
+}
+
+// schema calls convert with s, located at loc, and then with the subschemas left by convert.
+func (c *converter) schema(s map[string]interface{}, loc []string, convert func(s map[string]interface{}, loc []string)) {
+	convert(s, loc)
+	for _, k := range slices.Sorted(maps.Keys(s)) {
+		l := append(slices.Clip(loc), k)
+		switch k {
+		case "properties", "patternProperties", "$defs", "definitions", "dependentSchemas":
+			schemas, _ := s[k].(map[string]interface{})
+			for _, name := range slices.Sorted(maps.Keys(schemas)) {
+				if sub, ok := schemas[name].(map[string]interface{}); ok {
+					c.schema(sub, append(slices.Clip(l), name), convert)
+				}
+			}
+		case "allOf", "anyOf", "oneOf", "prefixItems", "items", "additionalProperties", "additionalItems", "not",
+			"if", "then", "else", "contains", "propertyNames", "unevaluatedItems", "unevaluatedProperties", "contentSchema":
+			switch sub := s[k].(type) {
+			case map[string]interface{}:
+				c.schema(sub, l, convert)
+			case []interface{}:
+				for i, e := range sub {
+					if sub, ok := e.(map[string]interface{}); ok {
+						c.schema(sub, append(slices.Clip(l), strconv.Itoa(i)), convert)
+					}
+				}
+			}
+		}
+	}
+}
+
+// annotations are the keywords of the OpenAPI 3.0 dialect which do not affect validation.
+var annotations = []string{"title", "description", "default", "example", "deprecated", "readOnly", "writeOnly",
+	"externalDocs", "xml"}
+
+// bounds are the exclusive keywords by the inclusive keyword they modify in OpenAPI 3.0.
+var bounds = map[string]string{"maximum": "exclusiveMaximum", "minimum": "exclusiveMinimum"}
+
+// upgradeSchema converts s, located at loc, from the OpenAPI 3.0 dialect to the OpenAPI 3.1 dialect.
+func (c *converter) upgradeSchema(s map[string]interface{}, loc []string) {
+	c.upgradeKeywords(s, loc)
+	if example, ok := s["example"]; ok {
+		delete(s, "example")
+		s["examples"] = []interface{}{example}
+	}
+	switch s["format"] {
+	case string(BinaryFormat):
+		delete(s, "format")
+		s["contentMediaType"] = "application/octet-stream"
+	case string(ByteFormat):
+		delete(s, "format")
+		s["contentEncoding"] = "base64"
+	}
+}
+
+// upgradeKeywords converts the keywords of s, located at loc, whose meaning differs between the OpenAPI 3.0 and 3.1
+// dialects. The deprecated keywords which the OpenAPI 3.1 dialect still understands are kept.
+func (c *converter) upgradeKeywords(s map[string]interface{}, loc []string) {
+	if _, ok := s["$ref"]; ok {
+		var dropped []string
+		for k := range s {
+			if k != "$ref" && !strings.HasPrefix(k, "x-") && !slices.Contains(annotations, k) {
+				dropped = append(dropped, k)
+				delete(s, k)
+			}
+		}
+		if len(dropped) > 0 {
+			slices.Sort(dropped)
+			c.warn(loc, "keywords next to $ref are ignored by OpenAPI 3.0 and dropped: %s", strings.Join(dropped, ", "))
+		}
+	}
+	if nullable, ok := s["nullable"].(bool); ok {
+		delete(s, "nullable")
+		// nullable has no effect without type
+		if t, ok := s["type"].(string); ok && nullable {
+			s["type"] = []interface{}{t, "null"}
+			// enum is checked separately from type, so it has to allow null too
+			if enum, ok := s["enum"].([]interface{}); ok && !slices.Contains(enum, nil) {
+				s["enum"] = append(enum, nil)
+			}
+		}
+	}
+	for _, bound := range slices.Sorted(maps.Keys(bounds)) {
+		exclusive := bounds[bound]
+		if b, ok := s[exclusive].(bool); ok {
+			delete(s, exclusive)
+			if limit, ok := s[bound]; ok && b {
+				s[exclusive] = limit
+				delete(s, bound)
+			}
+		}
+	}
+}
+
+// downgradeKeywords reverses upgradeKeywords, which cannot restore the keywords it dropped next to "$ref".
+func (c *converter) downgradeKeywords(s map[string]interface{}, loc []string) {
+	if types, ok := s["type"].([]interface{}); ok && len(types) == 2 && slices.Contains(types, "null") {
+		others := slices.DeleteFunc(slices.Clone(types), func(t interface{}) bool { return t == "null" })
+		s["type"] = others[0]
+		s["nullable"] = true
+	}
+	for _, bound := range slices.Sorted(maps.Keys(bounds)) {
+		exclusive := bounds[bound]
+		limit, ok := number(s[exclusive])
+		if !ok {
+			continue
+		}
+		// the inclusive bound is kept if it is the stricter one
+		inclusive, ok := number(s[bound])
+		if !ok || (bound == "maximum" && limit <= inclusive) || (bound == "minimum" && limit >= inclusive) {
+			s[bound] = s[exclusive]
+			s[exclusive] = true
+		} else {
+			delete(s, exclusive)
+		}
+	}
+}
+
+// number returns v, a number decoded from JSON, as float64.
+func number(v interface{}) (float64, bool) {
+	switch v := v.(type) {
+	case float64:
+		return v, true
+	case json.Number:
+		x, err := v.Float64()
+		return x, err == nil
+	}
+	return 0, false
+}
+
+// upgradeKeywords converts the Schema Objects of the OpenAPI 3.0 document b with converter.upgradeKeywords.
+func upgradeKeywords(b []byte) ([]byte, error) {
+	document, err := decodeNumbers(b)
+	if err != nil {
+		return nil, err
+	}
+	c := &converter{}
+	c.walk(document, nil, c.upgradeKeywords)
+	return json.Marshal(document)
+}
+
+// downgradeKeywords converts the Schema Objects of the OpenAPI 3.0 document b with converter.downgradeKeywords,
+// keeping the order of members in b.
+func downgradeKeywords(b []byte) ([]byte, error) {
+	document, err := decodeNumbers(b)
+	if err != nil {
+		return nil, err
+	}
+	c := &converter{}
+	c.walk(document, nil, c.downgradeKeywords)
+	like, err := ser.JsonToYaml(b)
+	if err != nil {
+		return nil, err
+	}
+	node, err := orderLike(document, like)
+	if err != nil {
+		return nil, err
+	}
+	return ser.YamlToJson(node)
+}
+
+// decodeNumbers decodes the JSON object b, keeping the precision of numbers through a conversion.
+func decodeNumbers(b []byte) (map[string]interface{}, error) {
+	var document map[string]interface{}
+	d := json.NewDecoder(bytes.NewReader(b))
+	d.UseNumber()
+	if err := d.Decode(&document); err != nil {
+		return nil, err
+	}
+	return document, nil
+}
+
+// orderLike returns v, a value decoded from JSON, as a YAML node whose members are in the order of the same members
+// in like, the YAML node of a JSON document, followed by the other members in the order of their keys.
+func orderLike(v interface{}, like *yaml.Node) (*yaml.Node, error) {
+	switch v := v.(type) {
+	case map[string]interface{}:
+		var keys []string
+		members := make(map[string]*yaml.Node)
+		if like != nil && like.Kind == yaml.MappingNode {
+			for i := 0; i+1 < len(like.Content); i += 2 {
+				k := like.Content[i].Value
+				_, ok := v[k]
+				if _, found := members[k]; ok && !found {
+					keys = append(keys, k)
+					members[k] = like.Content[i+1]
+				}
+			}
+		}
+		for _, k := range slices.Sorted(maps.Keys(v)) {
+			if _, found := members[k]; !found {
+				keys = append(keys, k)
+			}
+		}
+		node := &yaml.Node{Kind: yaml.MappingNode, Tag: "!!map"}
+		for _, k := range keys {
+			value, err := orderLike(v[k], members[k])
+			if err != nil {
+				return nil, err
+			}
+			node.Content = append(node.Content, &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: k}, value)
+		}
+		return node, nil
+	case []interface{}:
+		node := &yaml.Node{Kind: yaml.SequenceNode, Tag: "!!seq"}
+		for i, e := range v {
+			var l *yaml.Node
+			if like != nil && like.Kind == yaml.SequenceNode && i < len(like.Content) {
+				l = like.Content[i]
+			}
+			value, err := orderLike(e, l)
+			if err != nil {
+				return nil, err
+			}
+			node.Content = append(node.Content, value)
+		}
+		return node, nil
+	}
+	b, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+	return ser.JsonToYaml(b)
+}
+
+// unsupported are the keywords of the OpenAPI 3.1 dialect which the OpenAPI 3.0 dialect does not have.
+var unsupported = []string{"$schema", "$id", "$anchor", "$dynamicAnchor", "$dynamicRef", "$defs", "$comment",
+	"$vocabulary", "if", "then", "else", "dependentRequired", "dependentSchemas", "prefixItems", "contains",
+	"minContains", "maxContains", "propertyNames", "patternProperties", "unevaluatedItems", "unevaluatedProperties",
+	"contentSchema"}
+
+// downgradeSchema converts s, located at loc, from the OpenAPI 3.1 dialect to the OpenAPI 3.0 dialect.
+func (c *converter) downgradeSchema(s map[string]interface{}, loc []string) {
+	prefixItems, _ := s["prefixItems"].([]interface{})
+	for _, k := range unsupported {
+		if _, ok := s[k]; ok {
+			delete(s, k)
+			c.warn(append(slices.Clip(loc), k), "%s is not supported by OpenAPI 3.0 and dropped", k)
+		}
+	}
+	if types, ok := s["type"].([]interface{}); ok {
+		others := slices.DeleteFunc(slices.Clone(types), func(t interface{}) bool { return t == "null" })
+		switch len(others) {
+		case 1:
+			s["type"] = others[0]
+			if len(others) < len(types) {
+				s["nullable"] = true
+			}
+		default:
+			delete(s, "type")
+			c.warn(append(slices.Clip(loc), "type"), "type %s cannot be expressed by OpenAPI 3.0 and is dropped", marshal(types))
+		}
+	} else if s["type"] == "null" {
+		delete(s, "type")
+		c.warn(append(slices.Clip(loc), "type"), "type \"null\" cannot be expressed by OpenAPI 3.0 and is dropped")
+	}
+	for _, bound := range slices.Sorted(maps.Keys(bounds)) {
+		exclusive := bounds[bound]
+		limit, ok := s[exclusive].(float64)
+		if !ok {
+			continue
+		}
+		delete(s, exclusive)
+		// the inclusive bound is kept if it is the stricter one
+		inclusive, ok := s[bound].(float64)
+		if !ok || (bound == "maximum" && limit <= inclusive) || (bound == "minimum" && limit >= inclusive) {
+			s[bound] = limit
+			s[exclusive] = true
+		}
+	}
+	if examples, ok := s["examples"].([]interface{}); ok {
+		delete(s, "examples")
+		if len(examples) > 0 {
+			s["example"] = examples[0]
+		}
+		if len(examples) > 1 {
+			c.warn(append(slices.Clip(loc), "examples"), "OpenAPI 3.0 has a single example, only the first of %d is kept", len(examples))
+		}
+	}
+	if v, ok := s["const"]; ok {
+		delete(s, "const")
+		s["enum"] = []interface{}{v}
+	}
+	if encoding, ok := s["contentEncoding"]; ok {
+		delete(s, "contentEncoding")
+		if encoding == "base64" {
+			s["format"] = string(ByteFormat)
+		} else {
+			c.warn(append(slices.Clip(loc), "contentEncoding"), "contentEncoding %q cannot be expressed by OpenAPI 3.0 and is dropped", encoding)
+		}
+	}
+	if _, ok := s["contentMediaType"]; ok {
+		delete(s, "contentMediaType")
+		if _, ok := s["format"]; !ok {
+			s["format"] = string(BinaryFormat)
+		}
+	}
+	switch items := s["items"].(type) {
+	case bool:
+		s["items"] = map[string]interface{}{}
+		if !items {
+			n := len(prefixItems)
+			if limit, ok := number(s["maxItems"]); !ok || float64(n) < limit {
+				s["maxItems"] = n
+			}
+			if n > 0 {
+				c.warn(append(slices.Clip(loc), "items"), "items false after %d prefixItems cannot be expressed by OpenAPI 3.0 and is replaced by maxItems", n)
+			}
+		}
+	case nil:
+		if s["type"] == "array" {
+			s["items"] = map[string]interface{}{} // required by OpenAPI 3.0
+		}
+	}
+	if ref, ok := s["$ref"]; ok && slices.ContainsFunc(slices.Collect(maps.Keys(s)), func(k string) bool {
+		return k != "$ref" && !strings.HasPrefix(k, "x-") && !slices.Contains(annotations, k)
+	}) {
+		// OpenAPI 3.0 ignores keywords next to $ref, but not next to allOf
+		delete(s, "$ref")
+		allOf, _ := s["allOf"].([]interface{})
+		s["allOf"] = append([]interface{}{map[string]interface{}{"$ref": ref}}, allOf...)
+	}
+}
+
+func marshal(v interface{}) string {
+	b, err := json.Marshal(v)
+	if err != nil {
+		return fmt.Sprint(v)
+	}
+	return string(b)
+}
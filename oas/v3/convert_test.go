@@ -0,0 +1,157 @@
+package oas
+
+import (
+	"encoding/json"
+	"slices"
+	"testing"
+)
+
+func TestUpgrade(t *testing.T) {
+	data := `{
+  "openapi": "3.0.3",
+  "info": {"title": "Pets", "version": "1.0.0"},
+  "paths": {
+    "/pets": {
+      "get": {
+        "parameters": [
+          {"name": "limit", "in": "query", "schema": {"type": "integer", "nullable": true, "maximum": 100, "exclusiveMaximum": true}}
+        ],
+        "responses": {
+          "200": {
+            "description": "pets",
+            "content": {
+              "application/json": {
+                "schema": {"type": "array", "items": {"$ref": "#/components/schemas/Pet"}},
+                "example": [{"schema": {"nullable": true}}]
+              }
+            }
+          }
+        }
+      }
+    }
+  },
+  "components": {
+    "schemas": {
+      "Pet": {
+        "type": "object",
+        "required": ["name"],
+        "properties": {
+          "name": {"type": "string", "example": "Tom"},
+          "photo": {"type": "string", "format": "binary"},
+          "size": {"type": "string", "enum": ["small", "large"], "nullable": true},
+          "owner": {"$ref": "#/components/schemas/Owner", "nullable": true, "description": "the owner"}
+        }
+      },
+      "Owner": {"type": "object", "minProperties": 1, "exclusiveMinimum": false}
+    }
+  }
+}`
+	// Act
+	doc, warnings, err := Upgrade([]byte(data))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if doc.Version != "3.1.0" {
+		t.Errorf("got version %q", doc.Version)
+	}
+	tests := []struct {
+		schema interface{}
+		want   string
+	}{
+		{doc.Paths["/pets"].Get.Parameters[0].Schema, `{"type":["null","integer"],"exclusiveMaximum":100}`},
+		{doc.Paths["/pets"].Get.Responses["200"].Content["application/json"].Example, `[{"schema":{"nullable":true}}]`},
+		{doc.Components.Schemas["Pet"], `{"type":"object","required":["name"],"properties":{` +
+			`"name":{"examples":["Tom"],"type":"string"},` +
+			`"owner":{"$ref":"#/components/schemas/Owner","description":"the owner"},` +
+			`"photo":{"type":"string","contentMediaType":"application/octet-stream"},` +
+			`"size":{"type":["null","string"],"enum":["small","large",null]}}}`},
+		{doc.Components.Schemas["Owner"], `{"type":"object","minProperties":1}`},
+	}
+	for _, tt := range tests {
+		b, err := json.Marshal(tt.schema)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if string(b) != tt.want {
+			t.Errorf("got %s, want %s", b, tt.want)
+		}
+	}
+	want := []Warning{{"/components/schemas/Pet/properties/owner", "keywords next to $ref are ignored by OpenAPI 3.0 and dropped: nullable"}}
+	if !slices.EqualFunc(warnings, want, func(a *Warning, b Warning) bool { return *a == b }) {
+		t.Errorf("got %v, want %v", warnings, want)
+	}
+}
+
+func TestOpenAPI_Downgrade(t *testing.T) {
+	data := `{
+  "openapi": "3.1.0",
+  "info": {"title": "Pets", "version": "1.0.0"},
+  "webhooks": {"newPet": {"post": {"responses": {"200": {"description": "ok"}}}}},
+  "components": {
+    "schemas": {
+      "Pet": {
+        "type": "object",
+        "properties": {
+          "name": {"type": ["string", "null"], "examples": ["Tom", "Garfield"]},
+          "age": {"type": "integer", "minimum": 1, "exclusiveMinimum": 1},
+          "kind": {"const": "cat"},
+          "photo": {"type": "string", "contentEncoding": "base64"},
+          "owner": {"$ref": "#/components/schemas/Owner", "description": "the owner"},
+          "nick": {"$ref": "#/components/schemas/Name", "maxLength": 8},
+          "pair": {"type": "array", "prefixItems": [{"type": "string"}], "items": false}
+        }
+      },
+      "Owner": {"type": ["object", "string"]},
+      "Name": {"type": "string"}
+    }
+  }
+}`
+	doc, err := ParseJson([]byte(data))
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Act
+	b, warnings, err := doc.Downgrade()
+	if err != nil {
+		t.Fatal(err)
+	}
+	var got struct {
+		Version    string `json:"openapi"`
+		Components struct {
+			Schemas map[string]json.RawMessage `json:"schemas"`
+		} `json:"components"`
+	}
+	if err := json.Unmarshal(b, &got); err != nil {
+		t.Fatal(err)
+	}
+	if got.Version != "3.0.3" {
+		t.Errorf("got version %q", got.Version)
+	}
+	wantSchemas := map[string]string{
+		"Pet": `{"properties":{` +
+			`"age":{"exclusiveMinimum":true,"minimum":1,"type":"integer"},` +
+			`"kind":{"enum":["cat"]},` +
+			`"name":{"example":"Tom","nullable":true,"type":"string"},` +
+			`"nick":{"allOf":[{"$ref":"#/components/schemas/Name"}],"maxLength":8},` +
+			`"owner":{"$ref":"#/components/schemas/Owner","description":"the owner"},` +
+			`"pair":{"items":{},"maxItems":1,"type":"array"},` +
+			`"photo":{"format":"byte","type":"string"}},"type":"object"}`,
+		"Owner": `{}`,
+		"Name":  `{"type":"string"}`,
+	}
+	for name, want := range wantSchemas {
+		if string(got.Components.Schemas[name]) != want {
+			t.Errorf("got %s %s, want %s", name, got.Components.Schemas[name], want)
+		}
+	}
+	want := []Warning{
+		{"/webhooks", "webhooks are not supported by OpenAPI 3.0 and dropped"},
+		{"/components/schemas/Owner/type", `type ["string","object"] cannot be expressed by OpenAPI 3.0 and is dropped`},
+		{"/components/schemas/Pet/properties/name/examples", "OpenAPI 3.0 has a single example, only the first of 2 is kept"},
+		{"/components/schemas/Pet/properties/pair/prefixItems", "prefixItems is not supported by OpenAPI 3.0 and dropped"},
+		{"/components/schemas/Pet/properties/pair/items", "items false after 1 prefixItems cannot be expressed by OpenAPI 3.0 and is replaced by maxItems"},
+	}
+	if !slices.EqualFunc(warnings, want, func(a *Warning, b Warning) bool { return *a == b }) {
+		t.Errorf("got %v, want %v", warnings, want)
+	}
+}
@@ -0,0 +1,390 @@
+package oas
+
+import (
+	"encoding/json"
+
+	"github.com/MaiMee1/go-apispec/oas/ser"
+)
+
+// The methods below inline SpecificationExtension fields as "x-" prefixed members of their object.
+//
+// Each method converts the receiver to a local type without methods to avoid infinite recursion.
+
+// MarshalJSON writes the Schema Objects of an OpenAPI 3.0 document back in the OpenAPI 3.0 dialect, see UnmarshalJSON.
+//
+// Paths is written if it is not nil, and always for OpenAPI 3.0 which requires it. Components is omitted if empty.
+//
+//goland:noinspection GoMixedReceiverTypes
+func (doc OpenAPI) MarshalJSON() ([]byte, error) {
+	type openAPI OpenAPI
+	// the members before components are repeated to keep their order
+	v := struct {
+		Version    SemanticVersion     `json:"openapi,omitempty"`
+		Info       Info                `json:"info,omitempty"`
+		Servers    []Server            `json:"servers,omitempty"`
+		Paths      *Paths              `json:"paths,omitempty"`
+		Webhooks   map[string]PathItem `json:"webhooks,omitempty"`
+		Components *Components         `json:"components,omitempty"`
+		openAPI
+	}{Version: doc.Version, Info: doc.Info, Servers: doc.Servers, Webhooks: doc.Webhooks, openAPI: openAPI(doc)}
+	if doc.Paths != nil || doc.Version.Major() == 3 && doc.Version.Minor() == 0 {
+		paths := doc.Paths
+		if paths == nil {
+			paths = Paths{}
+		}
+		v.Paths = &paths
+	}
+	if !doc.Components.isEmpty() {
+		v.Components = &doc.Components
+	}
+	b, err := ser.MarshalExtended(v, doc.Extensions)
+	if err != nil || doc.Version.Major() != 3 || doc.Version.Minor() != 0 {
+		return b, err
+	}
+	return downgradeKeywords(b)
+}
+
+// UnmarshalJSON reads the Schema Objects of an OpenAPI 3.0 document in the OpenAPI 3.0 dialect: nullable becomes a
+// "null" type, the boolean exclusiveMaximum and exclusiveMinimum become numbers, and keywords next to "$ref" other
+// than annotations are ignored. They are held in the OpenAPI 3.1 dialect of Schema, see OpenAPI.Downgrade to convert
+// the whole document.
+//
+//goland:noinspection GoMixedReceiverTypes
+func (doc *OpenAPI) UnmarshalJSON(b []byte) (err error) {
+	type openAPI OpenAPI
+	var head struct {
+		Version SemanticVersion `json:"openapi"`
+	}
+	if err := json.Unmarshal(b, &head); err != nil {
+		return err
+	}
+	if head.Version.Major() == 3 && head.Version.Minor() == 0 {
+		if b, err = upgradeKeywords(b); err != nil {
+			return err
+		}
+	}
+	doc.Extensions, err = ser.UnmarshalExtended(b, (*openAPI)(doc))
+	return err
+}
+
+//goland:noinspection GoMixedReceiverTypes
+func (i Info) MarshalJSON() ([]byte, error) {
+	type info Info
+	return ser.MarshalExtended(info(i), i.Extensions)
+}
+
+//goland:noinspection GoMixedReceiverTypes
+func (i *Info) UnmarshalJSON(b []byte) (err error) {
+	type info Info
+	v := struct {
+		*info
+		Version ser.Text `json:"version,omitempty"`
+	}{info: (*info)(i)}
+	i.Extensions, err = ser.UnmarshalExtended(b, &v)
+	i.Version = string(v.Version)
+	return err
+}
+
+//goland:noinspection GoMixedReceiverTypes
+func (c Contact) MarshalJSON() ([]byte, error) {
+	type contact Contact
+	return ser.MarshalExtended(contact(c), c.Extensions)
+}
+
+//goland:noinspection GoMixedReceiverTypes
+func (c *Contact) UnmarshalJSON(b []byte) (err error) {
+	type contact Contact
+	c.Extensions, err = ser.UnmarshalExtended(b, (*contact)(c))
+	return err
+}
+
+//goland:noinspection GoMixedReceiverTypes
+func (l License) MarshalJSON() ([]byte, error) {
+	type license License
+	return ser.MarshalExtended(license(l), l.Extensions)
+}
+
+//goland:noinspection GoMixedReceiverTypes
+func (l *License) UnmarshalJSON(b []byte) (err error) {
+	type license License
+	l.Extensions, err = ser.UnmarshalExtended(b, (*license)(l))
+	return err
+}
+
+//goland:noinspection GoMixedReceiverTypes
+func (s Server) MarshalJSON() ([]byte, error) {
+	type server Server
+	return ser.MarshalExtended(server(s), s.Extensions)
+}
+
+//goland:noinspection GoMixedReceiverTypes
+func (s *Server) UnmarshalJSON(b []byte) (err error) {
+	type server Server
+	s.Extensions, err = ser.UnmarshalExtended(b, (*server)(s))
+	return err
+}
+
+//goland:noinspection GoMixedReceiverTypes
+func (s ServerVariable) MarshalJSON() ([]byte, error) {
+	type serverVariable ServerVariable
+	return ser.MarshalExtended(serverVariable(s), s.Extensions)
+}
+
+//goland:noinspection GoMixedReceiverTypes
+func (s *ServerVariable) UnmarshalJSON(b []byte) (err error) {
+	type serverVariable ServerVariable
+	v := struct {
+		*serverVariable
+		Enum    []ser.Text `json:"enum,omitempty"`
+		Default ser.Text   `json:"default,omitempty"`
+	}{serverVariable: (*serverVariable)(s)}
+	s.Extensions, err = ser.UnmarshalExtended(b, &v)
+	s.Enum = nil
+	for _, e := range v.Enum {
+		s.Enum = append(s.Enum, string(e))
+	}
+	s.Default = string(v.Default)
+	return err
+}
+
+//goland:noinspection GoMixedReceiverTypes
+func (c Components) MarshalJSON() ([]byte, error) {
+	type components Components
+	return ser.MarshalExtended(components(c), c.Extensions)
+}
+
+//goland:noinspection GoMixedReceiverTypes
+func (c *Components) UnmarshalJSON(b []byte) (err error) {
+	type components Components
+	c.Extensions, err = ser.UnmarshalExtended(b, (*components)(c))
+	return err
+}
+
+// isEmpty reports whether c has no members to write.
+//
+//goland:noinspection GoMixedReceiverTypes
+func (c *Components) isEmpty() bool {
+	return len(c.Schemas)+len(c.Responses)+len(c.Parameters)+len(c.Examples)+len(c.RequestBodies)+len(c.Headers)+
+		len(c.SecuritySchemes)+len(c.Links)+len(c.Callbacks)+len(c.PathItems)+len(c.Extensions) == 0
+}
+
+//goland:noinspection GoMixedReceiverTypes
+func (p PathItem) MarshalJSON() ([]byte, error) {
+	type pathItem PathItem
+	return ser.MarshalExtended(pathItem(p), p.Extensions)
+}
+
+//goland:noinspection GoMixedReceiverTypes
+func (p *PathItem) UnmarshalJSON(b []byte) (err error) {
+	type pathItem PathItem
+	p.Extensions, err = ser.UnmarshalExtended(b, (*pathItem)(p))
+	return err
+}
+
+//goland:noinspection GoMixedReceiverTypes
+func (o Operation) MarshalJSON() ([]byte, error) {
+	type operation Operation
+	return ser.MarshalExtended(operation(o), o.Extensions)
+}
+
+//goland:noinspection GoMixedReceiverTypes
+func (o *Operation) UnmarshalJSON(b []byte) (err error) {
+	type operation Operation
+	o.Extensions, err = ser.UnmarshalExtended(b, (*operation)(o))
+	return err
+}
+
+//goland:noinspection GoMixedReceiverTypes
+func (p Parameter) MarshalJSON() ([]byte, error) {
+	type parameter Parameter
+	return ser.MarshalExtended(parameter(p), p.Extensions)
+}
+
+//goland:noinspection GoMixedReceiverTypes
+func (p *Parameter) UnmarshalJSON(b []byte) (err error) {
+	type parameter Parameter
+	p.Extensions, err = ser.UnmarshalExtended(b, (*parameter)(p))
+	return err
+}
+
+//goland:noinspection GoMixedReceiverTypes
+func (r RequestBody) MarshalJSON() ([]byte, error) {
+	type requestBody RequestBody
+	return ser.MarshalExtended(requestBody(r), r.Extensions)
+}
+
+//goland:noinspection GoMixedReceiverTypes
+func (r *RequestBody) UnmarshalJSON(b []byte) (err error) {
+	type requestBody RequestBody
+	r.Extensions, err = ser.UnmarshalExtended(b, (*requestBody)(r))
+	return err
+}
+
+//goland:noinspection GoMixedReceiverTypes
+func (m MediaType) MarshalJSON() ([]byte, error) {
+	type mediaType MediaType
+	return ser.MarshalExtended(mediaType(m), m.Extensions)
+}
+
+//goland:noinspection GoMixedReceiverTypes
+func (m *MediaType) UnmarshalJSON(b []byte) (err error) {
+	type mediaType MediaType
+	m.Extensions, err = ser.UnmarshalExtended(b, (*mediaType)(m))
+	return err
+}
+
+//goland:noinspection GoMixedReceiverTypes
+func (e Encoding) MarshalJSON() ([]byte, error) {
+	type encoding Encoding
+	return ser.MarshalExtended(encoding(e), e.Extensions)
+}
+
+//goland:noinspection GoMixedReceiverTypes
+func (e *Encoding) UnmarshalJSON(b []byte) (err error) {
+	type encoding Encoding
+	e.Extensions, err = ser.UnmarshalExtended(b, (*encoding)(e))
+	return err
+}
+
+//goland:noinspection GoMixedReceiverTypes
+func (r Response) MarshalJSON() ([]byte, error) {
+	type response Response
+	return ser.MarshalExtended(response(r), r.Extensions)
+}
+
+//goland:noinspection GoMixedReceiverTypes
+func (r *Response) UnmarshalJSON(b []byte) (err error) {
+	type response Response
+	r.Extensions, err = ser.UnmarshalExtended(b, (*response)(r))
+	return err
+}
+
+//goland:noinspection GoMixedReceiverTypes
+func (e Example) MarshalJSON() ([]byte, error) {
+	type example Example
+	return ser.MarshalExtended(example(e), e.Extensions)
+}
+
+//goland:noinspection GoMixedReceiverTypes
+func (e *Example) UnmarshalJSON(b []byte) (err error) {
+	type example Example
+	e.Extensions, err = ser.UnmarshalExtended(b, (*example)(e))
+	return err
+}
+
+//goland:noinspection GoMixedReceiverTypes
+func (l Link) MarshalJSON() ([]byte, error) {
+	type link Link
+	return ser.MarshalExtended(link(l), l.Extensions)
+}
+
+//goland:noinspection GoMixedReceiverTypes
+func (l *Link) UnmarshalJSON(b []byte) (err error) {
+	type link Link
+	l.Extensions, err = ser.UnmarshalExtended(b, (*link)(l))
+	return err
+}
+
+//goland:noinspection GoMixedReceiverTypes
+func (h Header) MarshalJSON() ([]byte, error) {
+	type header Header
+	return ser.MarshalExtended(header(h), h.Extensions)
+}
+
+//goland:noinspection GoMixedReceiverTypes
+func (h *Header) UnmarshalJSON(b []byte) (err error) {
+	type header Header
+	h.Extensions, err = ser.UnmarshalExtended(b, (*header)(h))
+	return err
+}
+
+//goland:noinspection GoMixedReceiverTypes
+func (t Tag) MarshalJSON() ([]byte, error) {
+	type tag Tag
+	return ser.MarshalExtended(tag(t), t.Extensions)
+}
+
+//goland:noinspection GoMixedReceiverTypes
+func (t *Tag) UnmarshalJSON(b []byte) (err error) {
+	type tag Tag
+	t.Extensions, err = ser.UnmarshalExtended(b, (*tag)(t))
+	return err
+}
+
+//goland:noinspection GoMixedReceiverTypes
+func (s SecurityScheme) MarshalJSON() ([]byte, error) {
+	type securityScheme SecurityScheme
+	return ser.MarshalExtended(securityScheme(s), s.Extensions)
+}
+
+//goland:noinspection GoMixedReceiverTypes
+func (s *SecurityScheme) UnmarshalJSON(b []byte) (err error) {
+	type securityScheme SecurityScheme
+	s.Extensions, err = ser.UnmarshalExtended(b, (*securityScheme)(s))
+	return err
+}
+
+//goland:noinspection GoMixedReceiverTypes
+func (o OAuthFlows) MarshalJSON() ([]byte, error) {
+	type oAuthFlows OAuthFlows
+	return ser.MarshalExtended(oAuthFlows(o), o.Extensions)
+}
+
+//goland:noinspection GoMixedReceiverTypes
+func (o *OAuthFlows) UnmarshalJSON(b []byte) (err error) {
+	type oAuthFlows OAuthFlows
+	o.Extensions, err = ser.UnmarshalExtended(b, (*oAuthFlows)(o))
+	return err
+}
+
+//goland:noinspection GoMixedReceiverTypes
+func (i ImplicitOAuthFlow) MarshalJSON() ([]byte, error) {
+	type implicitOAuthFlow ImplicitOAuthFlow
+	return ser.MarshalExtended(implicitOAuthFlow(i), i.Extensions)
+}
+
+//goland:noinspection GoMixedReceiverTypes
+func (i *ImplicitOAuthFlow) UnmarshalJSON(b []byte) (err error) {
+	type implicitOAuthFlow ImplicitOAuthFlow
+	i.Extensions, err = ser.UnmarshalExtended(b, (*implicitOAuthFlow)(i))
+	return err
+}
+
+//goland:noinspection GoMixedReceiverTypes
+func (p PasswordOAuthFlow) MarshalJSON() ([]byte, error) {
+	type passwordOAuthFlow PasswordOAuthFlow
+	return ser.MarshalExtended(passwordOAuthFlow(p), p.Extensions)
+}
+
+//goland:noinspection GoMixedReceiverTypes
+func (p *PasswordOAuthFlow) UnmarshalJSON(b []byte) (err error) {
+	type passwordOAuthFlow PasswordOAuthFlow
+	p.Extensions, err = ser.UnmarshalExtended(b, (*passwordOAuthFlow)(p))
+	return err
+}
+
+//goland:noinspection GoMixedReceiverTypes
+func (c ClientCredentialsOAuthFlow) MarshalJSON() ([]byte, error) {
+	type clientCredentialsOAuthFlow ClientCredentialsOAuthFlow
+	return ser.MarshalExtended(clientCredentialsOAuthFlow(c), c.Extensions)
+}
+
+//goland:noinspection GoMixedReceiverTypes
+func (c *ClientCredentialsOAuthFlow) UnmarshalJSON(b []byte) (err error) {
+	type clientCredentialsOAuthFlow ClientCredentialsOAuthFlow
+	c.Extensions, err = ser.UnmarshalExtended(b, (*clientCredentialsOAuthFlow)(c))
+	return err
+}
+
+//goland:noinspection GoMixedReceiverTypes
+func (a AuthorizationCodeOAuthFlow) MarshalJSON() ([]byte, error) {
+	type authorizationCodeOAuthFlow AuthorizationCodeOAuthFlow
+	return ser.MarshalExtended(authorizationCodeOAuthFlow(a), a.Extensions)
+}
+
+//goland:noinspection GoMixedReceiverTypes
+func (a *AuthorizationCodeOAuthFlow) UnmarshalJSON(b []byte) (err error) {
+	type authorizationCodeOAuthFlow AuthorizationCodeOAuthFlow
+	a.Extensions, err = ser.UnmarshalExtended(b, (*authorizationCodeOAuthFlow)(a))
+	return err
+}
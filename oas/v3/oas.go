@@ -1,16 +1,19 @@
 package oas
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"maps"
 	"regexp"
 	"slices"
+	"strings"
 
 	"github.com/MaiMee1/go-apispec/oas/iana"
-	"github.com/MaiMee1/go-apispec/oas/internal/validate"
+	"github.com/MaiMee1/go-apispec/oas/jsonpointer"
 	"github.com/MaiMee1/go-apispec/oas/jsonschema"
 	"github.com/MaiMee1/go-apispec/oas/jsonschema/draft2020"
+	"github.com/MaiMee1/go-apispec/oas/jsonschema/oas30"
 	"github.com/MaiMee1/go-apispec/oas/jsonschema/oas31"
 )
 
@@ -33,14 +36,15 @@ type (
 )
 
 const (
-	Int32Format  Format = "int32"
-	Int64Format  Format = "int64"
-	FloatFormat  Format = "float"
-	DoubleFormat Format = "double"
-
-	Base64Format   Format = "base64"   // base64 encoded characters
-	BinaryFormat   Format = "binary"   // octet-stream
-	PasswordFormat Format = "password" // A hint to UIs to obscure input.
+	Int32Format  = oas31.Int32Format
+	Int64Format  = oas31.Int64Format
+	FloatFormat  = oas31.FloatFormat
+	DoubleFormat = oas31.DoubleFormat
+
+	ByteFormat     = oas31.ByteFormat
+	Base64Format   = oas31.Base64Format
+	BinaryFormat   = oas31.BinaryFormat
+	PasswordFormat = oas31.PasswordFormat
 )
 
 const (
@@ -108,11 +112,6 @@ func (s Scheme) String() string {
 	return schemeToString[s]
 }
 
-func (v SemanticVersion) Validate() error {
-	// TODO:
-	return nil
-}
-
 func (l Location) MarshalJSON() ([]byte, error) {
 	return json.Marshal(l.String())
 }
@@ -167,8 +166,49 @@ func (s *Scheme) UnmarshalJSON(b []byte) error {
 	return fmt.Errorf("invalid scheme %q", st)
 }
 
+// RuntimeExpression is a value of an HTTP message evaluated at runtime, such as "$request.body#/url", see
+// https://spec.openapis.org/oas/v3.1.0#runtime-expressions.
 type RuntimeExpression string
 
+// tokenRe matches a token of an HTTP header name as defined by RFC 7230.
+var tokenRe = regexp.MustCompile("^[!#$%&'*+\\-.^_`|~0-9A-Za-z]+$")
+
+// Validate returns an error if e does not follow the syntax of runtime expressions.
+func (e RuntimeExpression) Validate() error {
+	s := string(e)
+	switch s {
+	case "$url", "$method", "$statusCode":
+		return nil
+	}
+	var source string
+	if rest, ok := strings.CutPrefix(s, "$request."); ok {
+		source = rest
+	} else if rest, ok := strings.CutPrefix(s, "$response."); ok {
+		source = rest
+	} else {
+		return fmt.Errorf("invalid runtime expression %q, want $url, $method, $statusCode, $request or $response", s)
+	}
+	if name, ok := strings.CutPrefix(source, "header."); ok {
+		if !tokenRe.MatchString(name) {
+			return fmt.Errorf("invalid runtime expression %q: invalid header name %q", s, name)
+		}
+		return nil
+	}
+	if strings.HasPrefix(source, "query.") || strings.HasPrefix(source, "path.") {
+		return nil
+	}
+	if source == "body" {
+		return nil
+	}
+	if ptr, ok := strings.CutPrefix(source, "body#"); ok {
+		if _, err := jsonpointer.Ptr(ptr).Tokens(); err != nil {
+			return fmt.Errorf("invalid runtime expression %q: %w", s, err)
+		}
+		return nil
+	}
+	return fmt.Errorf("invalid runtime expression %q, want a header, query, path or body source", s)
+}
+
 type DataType struct {
 	Type   jsonschema.Type `json:"type" validate:"required"`
 	Format Format          `json:"format,omitempty"`
@@ -201,10 +241,24 @@ type OpenAPI struct {
 	Tags         []Tag                        `json:"tags,omitempty" validate:"dive"`
 	ExternalDocs *oas31.ExternalDocumentation `json:"externalDocs,omitempty"`
 	Extensions   SpecificationExtension       `json:"-"`
+
+	ctx context.Context
+}
+
+// WithContext sets the context used to resolve references, see resolve.WithScope.
+func (doc *OpenAPI) WithContext(ctx context.Context) *OpenAPI {
+	doc.ctx = ctx
+	return doc
 }
 
-func (doc *OpenAPI) Validate() error {
-	return validate.Struct(doc)
+// SchemaDialect returns the URI of the dialect of the Schema Objects of doc by its OpenAPI version, which is
+// oas30.Dialect for OpenAPI 3.0 documents and oas31.Dialect otherwise. A Schema Object with "$schema" is of the dialect
+// it identifies instead, see jsonschema.Decode.
+func (doc *OpenAPI) SchemaDialect() string {
+	if doc.Version.Major() == 3 && doc.Version.Minor() == 0 {
+		return oas30.Dialect
+	}
+	return oas31.Dialect
 }
 
 // Info provides metadata about the API. The metadata MAY be used by the clients if needed, and MAY be presented in editing or documentation generation tools for convenience.
@@ -269,7 +323,6 @@ type Paths map[string]PathItem
 
 type PathItem struct {
 	draft2020.ReferenceMixin[PathItem]
-	Ref         string                 `json:"$ref,omitempty" validate:"omitempty,uri"`
 	Summary     string                 `json:"summary,omitempty"`
 	Description RichText               `json:"description,omitempty"`
 	Get         *Operation             `json:"get,omitempty"`
@@ -332,8 +385,8 @@ type Operation struct {
 
 type Parameter struct {
 	draft2020.ReferenceMixin[Parameter]
-	Name            string                 `json:"name,omitempty" validate:"required"`
-	In              Location               `json:"in,omitempty" validate:"required"`
+	Name            string                 `json:"name,omitempty" validate:"required_without=Ref"`
+	In              Location               `json:"in,omitempty" validate:"required_without=Ref"`
 	Description     RichText               `json:"description,omitempty"`
 	Required        bool                   `json:"required,omitempty" validate:"required_if=In 3"`
 	Deprecated      bool                   `json:"deprecated,omitempty"`
@@ -351,7 +404,7 @@ type Parameter struct {
 type RequestBody struct {
 	draft2020.ReferenceMixin[RequestBody]
 	Description RichText               `json:"description,omitempty"`
-	Content     map[string]MediaType   `json:"content,omitempty" validate:"required"`
+	Content     map[string]MediaType   `json:"content,omitempty" validate:"required_without=Ref"`
 	Required    bool                   `json:"required,omitempty"`
 	Extensions  SpecificationExtension `json:"-"`
 }
@@ -400,7 +453,7 @@ func isValidContentKey(key string) bool {
 
 type Response struct {
 	draft2020.ReferenceMixin[Response]
-	Description RichText               `json:"description,omitempty" validate:"required"`
+	Description RichText               `json:"description,omitempty" validate:"required_without=Ref"`
 	Headers     map[string]Header      `json:"headers,omitempty"`
 	Content     map[string]MediaType   `json:"content,omitempty"`
 	Links       map[string]Link        `json:"links,omitempty"`
@@ -409,7 +462,7 @@ type Response struct {
 
 type Callback struct {
 	draft2020.ReferenceMixin[Callback]
-	Value map[RuntimeExpression]PathItem
+	Value map[RuntimeExpression]PathItem `json:",inline"`
 }
 
 //goland:noinspection GoMixedReceiverTypes
@@ -440,9 +493,26 @@ func (c *Callback) UnmarshalJSON(b []byte) error {
 	return nil
 }
 
+// isValidCallbackKey reports whether key is a runtime expression, or a URL in which every expression in braces is one,
+// such as "https://example.com?id={$request.body#/id}".
 func isValidCallbackKey(key string) bool {
-	// TODO: valid runtime expression
-	return true
+	if RuntimeExpression(key).Validate() == nil {
+		return true
+	}
+	for rest := key; ; {
+		start := strings.IndexByte(rest, '{')
+		if start == -1 {
+			return !strings.Contains(rest, "}")
+		}
+		end := strings.IndexByte(rest[start:], '}')
+		if end == -1 || strings.Contains(rest[:start], "}") {
+			return false
+		}
+		if RuntimeExpression(rest[start+1:start+end]).Validate() != nil {
+			return false
+		}
+		rest = rest[start+end+1:]
+	}
 }
 
 type Example struct {
@@ -456,8 +526,8 @@ type Example struct {
 
 type Link struct {
 	draft2020.ReferenceMixin[Link]
-	OperationRef string                 `json:"operationRef,omitempty" validate:"required_without=OperationId"`
-	OperationId  string                 `json:"operationId,omitempty" validate:"required_without=OperationRef"`
+	OperationRef string                 `json:"operationRef,omitempty" validate:"required_without_all=OperationId Ref"`
+	OperationId  string                 `json:"operationId,omitempty" validate:"required_without_all=OperationRef Ref"`
 	Parameters   map[string]interface{} `json:"parameters,omitempty"`
 	RequestBody  []interface{}          `json:"requestBody,omitempty"`
 	Description  string                 `json:"description,omitempty"`
@@ -1,9 +1,20 @@
 package oas
 
 import (
+	"bytes"
+	"cmp"
 	"encoding/json"
 	"fmt"
+	"reflect"
+	"strings"
 	"testing"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/MaiMee1/go-apispec/oas/jsonpointer"
+	"github.com/MaiMee1/go-apispec/oas/jsonschema"
+	"github.com/MaiMee1/go-apispec/oas/jsonschema/oas30"
+	"github.com/MaiMee1/go-apispec/oas/jsonschema/oas31"
 )
 
 func TestOpenAPI_UnmarshalJSON(t *testing.T) {
@@ -16,7 +27,11 @@ func TestOpenAPI_UnmarshalJSON(t *testing.T) {
 		t.Error(document.Version)
 	}
 	for k, m := range document.Paths["/pet"].Put.RequestBody.Content {
-		b, err := json.Marshal(m.Schema.Resolve())
+		schema, err := m.Schema.Resolve()
+		if err != nil {
+			t.Fatal(err)
+		}
+		b, err := json.Marshal(schema)
 		if err != nil {
 			t.Error(err)
 		}
@@ -25,15 +40,426 @@ func TestOpenAPI_UnmarshalJSON(t *testing.T) {
 	t.Log(document.Paths["/pet/findByStatus"].Get.Summary)
 }
 
+func TestOpenAPI_SchemaDialect(t *testing.T) {
+	tests := []struct {
+		version SemanticVersion
+		want    string
+		err     string // of validating null against a nullable string schema of the dialect
+	}{
+		{"3.0.0", oas30.Dialect, ""},
+		{"3.0.3", oas30.Dialect, ""},
+		{"3.1.0", oas31.Dialect, `"": value must be of type string, got null`},
+		{"3.1.1", oas31.Dialect, `"": value must be of type string, got null`},
+		{"", oas31.Dialect, `"": value must be of type string, got null`},
+	}
+	for i, tt := range tests {
+		t.Run(fmt.Sprintf("%d", i), func(t *testing.T) {
+			document := OpenAPI{Version: tt.version}
+			// Act
+			got := document.SchemaDialect()
+			if got != tt.want {
+				t.Errorf("got %s, want %s", got, tt.want)
+			}
+			s, err := jsonschema.Decode([]byte(`{"type": "string", "nullable": true}`), got)
+			if err != nil {
+				t.Fatal(err)
+			}
+			var msg string
+			if err := s.Validate(nil); err != nil {
+				msg = err.Error()
+			}
+			if msg != tt.err {
+				t.Errorf("got error %q, want %q", msg, tt.err)
+			}
+		})
+	}
+}
+
 func FuzzOpenAPI(f *testing.F) {
+	for _, s := range []string{"3.1.0", "3.0.3", "3.1", "03.1.0", "1.0.0-alpha.1+build.5", "1.0.0-01"} {
+		f.Add(s)
+	}
 	f.Fuzz(func(t *testing.T, s string) {
 		var document OpenAPI
-		err := json.Unmarshal([]byte(fmt.Sprintf(`{"openapi":"%s"}`, s)), &document)
+		b, _ := json.Marshal(map[string]string{"openapi": s})
+		err := json.Unmarshal(b, &document)
 		if err != nil {
 			t.Fatal(err)
 		}
-		if document.Version.Validate() != nil {
-			t.Error("expected valid openapi version")
+		v := document.Version
+		if v.Validate() != nil {
+			if v.IsSupported() {
+				t.Errorf("got invalid version %q supported", v)
+			}
+			return
+		}
+		if v.Compare(v) != 0 {
+			t.Errorf("got %q not equal to itself", v)
+		}
+		core := fmt.Sprintf("%d.%d.%d", v.Major(), v.Minor(), v.Patch())
+		if !strings.HasPrefix(string(v), core) {
+			t.Errorf("got version %s of %q", core, v)
 		}
 	})
 }
+
+func TestSemanticVersion_Validate(t *testing.T) {
+	tests := []struct {
+		version SemanticVersion
+		valid   bool
+	}{
+		{"3.1.0", true},
+		{"3.0.3", true},
+		{"1.0.0-alpha.1+build.5", true},
+		{"3.1", false},
+		{"03.1.0", false},
+		{"1.0.0-01", false},
+		{"v3.1.0", false},
+		{"3.18446744073709551616.0", false},
+	}
+	for i, tt := range tests {
+		t.Run(fmt.Sprintf("%d", i), func(t *testing.T) {
+			// Act
+			err := tt.version.Validate()
+			if (err == nil) != tt.valid {
+				t.Errorf("got %v, want valid %v", err, tt.valid)
+			}
+		})
+	}
+}
+
+func TestRuntimeExpression_Validate(t *testing.T) {
+	tests := []struct {
+		expression RuntimeExpression
+		want       string
+	}{
+		{"$url", ""},
+		{"$method", ""},
+		{"$statusCode", ""},
+		{"$request.header.X-Callback", ""},
+		{"$request.query.queryUrl", ""},
+		{"$request.path.id", ""},
+		{"$request.body", ""},
+		{"$request.body#/user/uuid", ""},
+		{"$response.body#/a~1b", ""},
+		{"$response.header.Location", ""},
+		{"", `invalid runtime expression "", want $url, $method, $statusCode, $request or $response`},
+		{"$host", `invalid runtime expression "$host", want $url, $method, $statusCode, $request or $response`},
+		{"$request.cookie.id", `invalid runtime expression "$request.cookie.id", want a header, query, path or body source`},
+		{"$request.header.X Callback", `invalid runtime expression "$request.header.X Callback": invalid header name "X Callback"`},
+		{"$request.body#user", `invalid runtime expression "$request.body#user": jsonpointer.Ptr: invalid syntax`},
+	}
+	for i, tt := range tests {
+		t.Run(fmt.Sprintf("%d", i), func(t *testing.T) {
+			// Act
+			err := tt.expression.Validate()
+			var got string
+			if err != nil {
+				got = err.Error()
+			}
+			if got != tt.want {
+				t.Errorf("got %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSemanticVersion_Compare(t *testing.T) {
+	// in order of precedence, from semver.org
+	versions := []SemanticVersion{"invalid", "1.0.0-alpha", "1.0.0-alpha.1", "1.0.0-alpha.beta", "1.0.0-beta",
+		"1.0.0-beta.2", "1.0.0-beta.11", "1.0.0-rc.1", "1.0.0", "2.0.0", "2.1.0", "2.1.1", "3.0.10", "10.0.0"}
+	for i, v := range versions {
+		for j, w := range versions {
+			// Act
+			got := v.Compare(w)
+			if want := cmp.Compare(i, j); got != want {
+				t.Errorf("got %q compare %q %d, want %d", v, w, got, want)
+			}
+		}
+	}
+	if got := SemanticVersion("1.0.0+a").Compare("1.0.0+b"); got != 0 {
+		t.Errorf("got %d, want build metadata ignored", got)
+	}
+}
+
+func TestParse_version(t *testing.T) {
+	tests := []struct {
+		document string
+		want     string // the schema of Pet, or the error
+	}{
+		{`{"openapi":"3.0.3","info":{"title":"t","version":"1"},"paths":{},"components":{"schemas":{
+			"Pet":{"type":"integer","nullable":true,"minimum":1,"exclusiveMinimum":true,"example":2}}}}`,
+			`{"type":["null","integer"],"exclusiveMinimum":1,"example":2}`},
+		{`{"openapi":"3.1.0","info":{"title":"t","version":"1"},"components":{"schemas":{
+			"Pet":{"type":"integer","nullable":true,"exclusiveMinimum":1}}}}`,
+			`{"type":"integer","exclusiveMinimum":1}`},
+		{`{"openapi":"2.0","info":{"title":"t","version":"1"}}`, `oas: unsupported OpenAPI version "2.0", want 3.0 or 3.1`},
+		{`{"openapi":"3.2.0","info":{"title":"t","version":"1"}}`, `oas: unsupported OpenAPI version "3.2.0", want 3.0 or 3.1`},
+	}
+	for i, tt := range tests {
+		t.Run(fmt.Sprintf("%d", i), func(t *testing.T) {
+			// Act
+			document, err := ParseJson([]byte(tt.document))
+			var got string
+			if err != nil {
+				got = err.Error()
+			} else {
+				b, err := json.Marshal(document.Components.Schemas["Pet"])
+				if err != nil {
+					t.Fatal(err)
+				}
+				got = string(b)
+			}
+			if got != tt.want {
+				t.Errorf("got %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestOpenAPI_MarshalJSON_version(t *testing.T) {
+	tests := []struct {
+		document string
+		want     string
+	}{
+		{`{"openapi":"3.0.3","info":{"title":"t","version":"1"},"paths":{},"components":{"schemas":{"Pet":{"type":"integer","nullable":true,"minimum":1,"exclusiveMinimum":true,"maximum":9}}}}`,
+			`{"openapi":"3.0.3","info":{"title":"t","version":"1"},"paths":{},"components":{"schemas":{"Pet":{"type":"integer","maximum":9,"exclusiveMinimum":true,"minimum":1,"nullable":true}}}}`},
+		{`{"openapi":"3.0.3","info":{"title":"t","version":"1"},"paths":{},"components":{"schemas":{"Pet":{"type":"array","items":{"type":"string","nullable":true},"maximum":9,"exclusiveMaximum":false}}}}`,
+			`{"openapi":"3.0.3","info":{"title":"t","version":"1"},"paths":{},"components":{"schemas":{"Pet":{"type":"array","maximum":9,"items":{"type":"string","nullable":true}}}}}`},
+		{`{"openapi":"3.1.0","info":{"title":"t","version":"1"},"components":{"schemas":{"Pet":{"type":["null","integer"],"exclusiveMinimum":1}}}}`,
+			`{"openapi":"3.1.0","info":{"title":"t","version":"1"},"components":{"schemas":{"Pet":{"type":["null","integer"],"exclusiveMinimum":1}}}}`},
+		{`{"openapi":"3.0.3","info":{"title":"t","version":"1"},"paths":{},"components":{}}`,
+			`{"openapi":"3.0.3","info":{"title":"t","version":"1"},"paths":{}}`},
+		{`{"openapi":"3.1.0","info":{"title":"t","version":"1"},"webhooks":{"pet":{}}}`,
+			`{"openapi":"3.1.0","info":{"title":"t","version":"1"},"webhooks":{"pet":{}}}`},
+	}
+	for i, tt := range tests {
+		t.Run(fmt.Sprintf("%d", i), func(t *testing.T) {
+			document, err := ParseJson([]byte(tt.document))
+			if err != nil {
+				t.Fatal(err)
+			}
+			// Act
+			b, err := json.Marshal(document)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if string(b) != tt.want {
+				t.Errorf("got %s, want %s", b, tt.want)
+			}
+			if _, err := ParseJson(b); err != nil {
+				t.Errorf("cannot parse %s: %v", b, err)
+			}
+		})
+	}
+}
+
+func TestOpenAPI_Extensions(t *testing.T) {
+	data := `{"openapi":"3.1.0","info":{"title":"Test","version":"1.0.0","x-logo":"logo.png"},"paths":{"/pet":{"get":{"operationId":"getPet","parameters":[{"name":"id","in":"query","schema":{"type":"integer","x-format":"id"},"x-internal":false}],"x-codegen":{"handler":"GetPet"}},"x-gateway":"public"}},"components":{"securitySchemes":{"api_key":{"type":"apiKey","name":"api_key","in":"header","x-scope":"read"}}},"x-tenant":["a","b"]}`
+	var document OpenAPI
+	if err := json.Unmarshal([]byte(data), &document); err != nil {
+		t.Fatal(err)
+	}
+	if document.Info.Extensions["x-logo"] != "logo.png" {
+		t.Errorf("got x-logo %v, want logo.png", document.Info.Extensions["x-logo"])
+	}
+	if document.Paths["/pet"].Extensions["x-gateway"] != "public" {
+		t.Errorf("got x-gateway %v, want public", document.Paths["/pet"].Extensions["x-gateway"])
+	}
+	// Act
+	b, err := json.Marshal(document)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(b) != data {
+		t.Errorf("got %s, want %s", b, data)
+	}
+}
+
+func TestOpenAPI_jsonpointer(t *testing.T) {
+	const data = `{"openapi":"3.1.0","info":{"title":"Test","version":"1.0.0","x-logo":{"url":"logo.png"}},"components":{"schemas":{"Tags":{"type":"array","items":{"type":"string"}},"Labels":{"additionalProperties":{"type":"integer"},"unevaluatedProperties":false}},"callbacks":{"onEvent":{"{$request.body#/url}":{"post":{"description":"event"}}}}}}`
+	var testCases = []struct {
+		op      string
+		pointer jsonpointer.Ptr
+		value   interface{}
+		want    string // the value at pointer, or a part of the JSON encoding of the modified document
+		err     bool
+	}{
+		{"access", "/components/schemas/Tags/items/type", nil, "string", false},
+		{"access", "/components/schemas/Labels/additionalProperties/type", nil, "integer", false},
+		{"access", "/components/schemas/Labels/unevaluatedProperties/type", nil, "", true},
+		{"access", "/components/callbacks/onEvent/{$request.body#~1url}/post/description", nil, "event", false},
+		{"access", "/info/x-logo/url", nil, "logo.png", false},
+		{"access", "/info/x-unknown", nil, "", true},
+		{"set", "/components/schemas/Tags/items/type", "integer", `"Tags":{"type":"array","items":{"type":"integer"}}`, false},
+		{"set", "/components/schemas/Labels/additionalProperties/minimum", 0, `"additionalProperties":{"type":"integer","minimum":0}`, false},
+		{"set", "/info/x-logo/url", "icon.png", `"x-logo":{"url":"icon.png"}`, false},
+		{"add", "/info/x-audience", "public", `"info":{"title":"Test","version":"1.0.0","x-audience":"public","x-logo":{"url":"logo.png"}}`, false},
+		{"add", "/components/schemas/Tags/x-go-type", "[]string", `"items":{"type":"string"},"x-go-type":"[]string"}`, false},
+		{"remove", "/info/x-logo", nil, `"info":{"title":"Test","version":"1.0.0"}`, false},
+		{"remove", "/components/schemas/Tags/items/type", nil, `"Tags":{"type":"array","items":{}}`, false},
+		{"remove", "/info/x-unknown", nil, "", true},
+	}
+	for i, tt := range testCases {
+		t.Run(fmt.Sprintf("%d", i), func(t *testing.T) {
+			document, err := ParseJson([]byte(data))
+			if err != nil {
+				t.Fatal(err)
+			}
+			// Act
+			var v reflect.Value
+			switch tt.op {
+			case "access":
+				v, err = tt.pointer.Access(document)
+			case "set":
+				err = tt.pointer.Set(document, tt.value)
+			case "add":
+				err = tt.pointer.Add(document, tt.value)
+			case "remove":
+				err = tt.pointer.Remove(document)
+			}
+
+			if (err != nil) != tt.err {
+				t.Fatalf("got error %v, want error %v", err, tt.err)
+			}
+			if err != nil {
+				return
+			}
+			if tt.op == "access" {
+				if fmt.Sprint(v) != tt.want {
+					t.Errorf("got %v, want %s", v, tt.want)
+				}
+				return
+			}
+			b, err := json.Marshal(document)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !strings.Contains(string(b), tt.want) {
+				t.Errorf("got %s, want %s", b, tt.want)
+			}
+		})
+	}
+}
+
+func TestNew_Yaml(t *testing.T) {
+	want, err := New("testdata/petstore.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	document, err := New("testdata/petstore.yaml")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	b1, _ := json.Marshal(want)
+	b2, _ := json.Marshal(document)
+	if string(b1) != string(b2) {
+		t.Errorf("got %s, want %s", b2, b1)
+	}
+	m := document.Paths["/pet"].Put.RequestBody.Content["application/json"]
+	if schema, err := m.Schema.Resolve(); err != nil || schema.Type.String() != "object" {
+		t.Errorf("got resolved type %v, want object", schema.Type)
+	}
+}
+
+func TestParseYaml_number(t *testing.T) {
+	tests := []struct {
+		document string
+		want     string // the JSON encoding of info and servers
+	}{
+		{"openapi: 3.1.0\ninfo: {title: test, version: 1.0}\n",
+			`{"title":"test","version":"1.0"} null`},
+		{"openapi: 3.1.0\ninfo: {title: test, version: 2}\n",
+			`{"title":"test","version":"2"} null`},
+		{"openapi: 3.1.0\ninfo: {title: test, version: '1.0'}\n",
+			`{"title":"test","version":"1.0"} null`},
+		{"openapi: 3.1.0\ninfo: {title: test, version: 1.0}\nservers:\n- url: https://example.com:{port}\n  variables:\n    port: {enum: [8080, 8443], default: 8443}\n",
+			`{"title":"test","version":"1.0"} [{"url":"https://example.com:{port}","variables":{"port":{"enum":["8080","8443"],"default":"8443"}}}]`},
+	}
+	for i, tt := range tests {
+		t.Run(fmt.Sprintf("%d", i), func(t *testing.T) {
+			// Act
+			doc, err := ParseYaml([]byte(tt.document))
+			if err != nil {
+				t.Fatal(err)
+			}
+			info, err := json.Marshal(doc.Info)
+			if err != nil {
+				t.Fatal(err)
+			}
+			servers, err := json.Marshal(doc.Servers)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := string(info) + " " + string(servers); got != tt.want {
+				t.Errorf("got %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestOpenAPI_MarshalYAML(t *testing.T) {
+	want, err := New("testdata/petstore.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Act
+	b, err := yaml.Marshal(want)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.HasPrefix(b, []byte("openapi: 3.0.3\ninfo:\n")) {
+		t.Errorf("got %.40q, want keys in declaration order", b)
+	}
+	document, err := Parse(b)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	b1, _ := json.Marshal(want)
+	b2, _ := json.Marshal(document)
+	if string(b1) != string(b2) {
+		t.Errorf("got %s, want %s", b2, b1)
+	}
+}
+
+func TestNew_ExternalRef(t *testing.T) {
+	document, err := New("testdata/split/openapi.yaml")
+	if err != nil {
+		t.Fatal(err)
+	}
+	responses := document.Paths["/pet"].Get.Responses
+
+	m := responses["200"].Content["application/json"]
+	pet, err := m.Schema.Resolve()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := pet.Properties["name"]; !ok {
+		t.Errorf("got properties %v, want name", pet.Properties)
+	}
+	category, err := pet.Properties["category"].Resolve()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := category.Properties["name"]; !ok {
+		t.Errorf("got category properties %v, want name", category.Properties)
+	}
+	if _, err := pet.Properties["owner"].Resolve(); err != nil {
+		t.Error(err)
+	}
+
+	r := responses["default"]
+	response, err := r.Resolve()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if response.Description != "unexpected error" {
+		t.Errorf("got description %q, want %q", response.Description, "unexpected error")
+	}
+	m = response.Content["application/json"]
+	if _, err := m.Schema.Resolve(); err != nil {
+		t.Error(err)
+	}
+}
@@ -3,27 +3,107 @@ package oas
 import (
 	"context"
 	"encoding/json"
-	"os"
-	"reflect"
+	"fmt"
+	"net/url"
+	"path"
+	"strings"
 
-	"github.com/MaiMee1/go-apispec/oas/internal/validate"
+	"gopkg.in/yaml.v3"
+
+	"github.com/MaiMee1/go-apispec/oas/resolve"
+	"github.com/MaiMee1/go-apispec/oas/ser"
 )
 
+// New reads and parses the OpenAPI document in filename. References to other files are resolved relative to it.
+//
+// Files with a ".json" extension are parsed as JSON and files with a ".yaml" or ".yml" extension as YAML. Other files
+// are detected by content.
 func New(filename string) (*OpenAPI, error) {
-	ctx := context.TODO()
+	uri, err := resolve.FileUri(filename)
+	if err != nil {
+		return nil, err
+	}
+	return Load(resolve.FileLoader{}, uri)
+}
+
+// Load loads and parses the OpenAPI document at uri through loader, which is also used to load the documents it
+// refers to.
+func Load(loader resolve.Loader, uri *url.URL) (*OpenAPI, error) {
+	file, err := loader.Load(uri)
+	if err != nil {
+		return nil, err
+	}
+	switch strings.ToLower(path.Ext(uri.Path)) {
+	case ".json":
+	case ".yaml", ".yml":
+		if file, err = yamlToJson(file); err != nil {
+			return nil, err
+		}
+	default:
+		if file, err = ser.ToJson(file); err != nil {
+			return nil, err
+		}
+	}
+	return parse(file, resolve.New(loader), uri)
+}
+
+// Parse parses an OpenAPI document in either JSON or YAML. References to other files are resolved relative to the
+// working directory.
+func Parse(b []byte) (*OpenAPI, error) {
+	if ser.IsJson(b) {
+		return ParseJson(b)
+	}
+	return ParseYaml(b)
+}
+
+// ParseJson parses an OpenAPI document in JSON. References to other files are resolved relative to the working
+// directory.
+func ParseJson(b []byte) (*OpenAPI, error) {
+	uri, err := resolve.FileUri(".")
+	if err != nil {
+		return nil, err
+	}
+	return parse(b, resolve.New(resolve.FileLoader{}), uri)
+}
 
-	file, err := os.ReadFile(filename)
+// ParseYaml parses an OpenAPI document in YAML. References to other files are resolved relative to the working
+// directory.
+func ParseYaml(b []byte) (*OpenAPI, error) {
+	file, err := yamlToJson(b)
 	if err != nil {
 		return nil, err
 	}
+	return ParseJson(file)
+}
+
+func yamlToJson(b []byte) ([]byte, error) {
+	var node yaml.Node
+	if err := yaml.Unmarshal(b, &node); err != nil {
+		return nil, err
+	}
+	return ser.YamlToJson(&node)
+}
+
+// parse parses the OpenAPI document b in JSON by the rules of its version, see OpenAPI.UnmarshalJSON.
+func parse(b []byte, r *resolve.Resolver, uri *url.URL) (*OpenAPI, error) {
+	var head struct {
+		Version SemanticVersion `json:"openapi"`
+	}
+	if err := json.Unmarshal(b, &head); err != nil {
+		return nil, err
+	}
+	if !head.Version.IsSupported() {
+		return nil, fmt.Errorf("oas: unsupported OpenAPI version %q, want 3.0 or 3.1", head.Version)
+	}
+
 	var document OpenAPI
-	if err = json.Unmarshal(file, &document); err != nil {
+	if err := json.Unmarshal(b, &document); err != nil {
 		return nil, err
 	}
-	ctx = context.WithValue(ctx, "Root", document)
-	setContext(reflect.ValueOf(&document), ctx)
+	r.Register(uri, &document)
+	resolve.Bind(&document, resolve.WithScope(context.TODO(), r, uri))
 
-	if err := validate.Struct(document); err != nil {
+	if err := document.Validate(); err != nil {
 		return &document, err
 	}
 	return &document, nil
@@ -0,0 +1,469 @@
+package oas
+
+import (
+	"encoding/json"
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+
+	"github.com/MaiMee1/go-apispec/oas/ser"
+)
+
+// ParseSwagger parses the Swagger 2.0 document b, in either JSON or YAML, as an OpenAPI 3.1 document. References to
+// other files are resolved relative to the working directory.
+//
+// The definitions, parameters, responses and securityDefinitions become the components of the document. The body and
+// formData parameters of an operation become its RequestBody, with the media types of consumes, and the schemas of its
+// responses become content with the media types of produces. The document is then upgraded from OpenAPI 3.0, see
+// Upgrade. What OpenAPI cannot express is dropped with a warning.
+//
+// As Parse, the document is returned along with the error of OpenAPI.Validate.
+func ParseSwagger(b []byte) (*OpenAPI, []*Warning, error) {
+	b, err := ser.ToJson(b)
+	if err != nil {
+		return nil, nil, err
+	}
+	var swagger map[string]interface{}
+	if err := json.Unmarshal(b, &swagger); err != nil {
+		return nil, nil, err
+	}
+	if version, _ := swagger["swagger"].(string); version != "2.0" {
+		return nil, nil, fmt.Errorf("oas: cannot import Swagger %q, want 2.0", version)
+	}
+	c := &swaggerConverter{swagger: swagger}
+	document := c.document()
+	c.walk(document, nil, c.schema30)
+	if b, err = json.Marshal(document); err != nil {
+		return nil, nil, err
+	}
+	doc, warnings, err := Upgrade(b)
+	return doc, append(c.warnings, warnings...), err
+}
+
+// swaggerConverter converts a decoded Swagger 2.0 document to an OpenAPI 3.0 document.
+type swaggerConverter struct {
+	converter
+	swagger map[string]interface{}
+}
+
+// Swagger 2.0 references rewritten to their OpenAPI 3.0 components by prefix.
+var swaggerRefs = strings.NewReplacer(
+	"#/definitions/", "#/components/schemas/",
+	"#/parameters/", "#/components/parameters/",
+	"#/responses/", "#/components/responses/",
+)
+
+var operations = []string{"get", "put", "post", "delete", "options", "head", "patch"}
+
+func (c *swaggerConverter) document() map[string]interface{} {
+	document := map[string]interface{}{"openapi": "3.0.3"}
+	for _, k := range slices.Sorted(maps.Keys(c.swagger)) {
+		if k == "info" || k == "tags" || k == "externalDocs" || k == "security" || strings.HasPrefix(k, "x-") {
+			document[k] = c.swagger[k]
+		}
+	}
+	if servers := c.servers(); servers != nil {
+		document["servers"] = servers
+	}
+	consumes, _ := c.swagger["consumes"].([]interface{})
+	produces, _ := c.swagger["produces"].([]interface{})
+
+	paths := make(map[string]interface{})
+	swaggerPaths, _ := c.swagger["paths"].(map[string]interface{})
+	for _, path := range slices.Sorted(maps.Keys(swaggerPaths)) {
+		item, ok := swaggerPaths[path].(map[string]interface{})
+		if !ok {
+			paths[path] = swaggerPaths[path] // an extension
+			continue
+		}
+		paths[path] = c.pathItem(item, []string{"paths", path}, consumes, produces)
+	}
+	document["paths"] = paths
+
+	components := make(map[string]interface{})
+	if definitions, ok := c.swagger["definitions"].(map[string]interface{}); ok {
+		components["schemas"] = definitions
+	}
+	parameters, _ := c.swagger["parameters"].(map[string]interface{})
+	for _, name := range slices.Sorted(maps.Keys(parameters)) {
+		p, _ := parameters[name].(map[string]interface{})
+		switch p["in"] {
+		case "body":
+			// referred to by the operations, which inline them as their RequestBody
+			put(components, "requestBodies", name, c.bodyRequest(p, consumes))
+		case "formData":
+			// inlined by the operations along with their other formData parameters
+		default:
+			put(components, "parameters", name, c.parameter(p, []string{"parameters", name}))
+		}
+	}
+	responses, _ := c.swagger["responses"].(map[string]interface{})
+	for _, name := range slices.Sorted(maps.Keys(responses)) {
+		r, _ := responses[name].(map[string]interface{})
+		put(components, "responses", name, c.response(r, produces))
+	}
+	schemes, _ := c.swagger["securityDefinitions"].(map[string]interface{})
+	for _, name := range slices.Sorted(maps.Keys(schemes)) {
+		s, _ := schemes[name].(map[string]interface{})
+		put(components, "securitySchemes", name, c.securityScheme(s, []string{"securityDefinitions", name}))
+	}
+	if len(components) > 0 {
+		document["components"] = components
+	}
+	return document
+}
+
+// servers returns the servers of the host, basePath and schemes of the document, or nil if it has neither host nor
+// basePath, in which case the API is served where the document is.
+func (c *swaggerConverter) servers() []interface{} {
+	host, _ := c.swagger["host"].(string)
+	basePath, _ := c.swagger["basePath"].(string)
+	if host == "" && basePath == "" {
+		return nil
+	}
+	if host == "" {
+		return []interface{}{map[string]interface{}{"url": basePath}}
+	}
+	schemes, _ := c.swagger["schemes"].([]interface{})
+	if len(schemes) == 0 {
+		// the scheme of the document
+		return []interface{}{map[string]interface{}{"url": "//" + host + basePath}}
+	}
+	servers := make([]interface{}, 0, len(schemes))
+	for _, scheme := range schemes {
+		servers = append(servers, map[string]interface{}{"url": fmt.Sprintf("%s://%s%s", scheme, host, basePath)})
+	}
+	return servers
+}
+
+// pathItem converts item, located at loc. Its body and formData parameters are moved to its operations, as a Path
+// Item Object of OpenAPI 3.0 has no RequestBody.
+func (c *swaggerConverter) pathItem(item map[string]interface{}, loc []string, consumes, produces []interface{}) map[string]interface{} {
+	out := make(map[string]interface{})
+	parameters, _ := item["parameters"].([]interface{})
+	var payload []interface{}
+	for i, p := range parameters {
+		resolved, ref := c.resolveParameter(p)
+		switch {
+		case resolved["in"] == "body" || resolved["in"] == "formData":
+			payload = append(payload, resolved)
+		case ref != "":
+			out["parameters"] = append(list(out["parameters"]), map[string]interface{}{"$ref": ref})
+		default:
+			out["parameters"] = append(list(out["parameters"]), c.parameter(resolved, append(slices.Clip(loc), "parameters", fmt.Sprint(i))))
+		}
+	}
+	for _, k := range slices.Sorted(maps.Keys(item)) {
+		switch {
+		case k == "$ref" || strings.HasPrefix(k, "x-"):
+			out[k] = item[k]
+		case slices.Contains(operations, k):
+			op, _ := item[k].(map[string]interface{})
+			out[k] = c.operation(op, payload, append(slices.Clip(loc), k), consumes, produces)
+		}
+	}
+	return out
+}
+
+// operation converts op, located at loc, with the body and formData parameters shared by its Path Item.
+func (c *swaggerConverter) operation(op map[string]interface{}, shared []interface{}, loc []string, consumes, produces []interface{}) map[string]interface{} {
+	out := make(map[string]interface{})
+	for _, k := range slices.Sorted(maps.Keys(op)) {
+		switch {
+		case k == "tags" || k == "summary" || k == "description" || k == "externalDocs" || k == "operationId" ||
+			k == "deprecated" || k == "security" || strings.HasPrefix(k, "x-"):
+			out[k] = op[k]
+		case k == "consumes":
+			consumes, _ = op[k].([]interface{})
+		case k == "produces":
+			produces, _ = op[k].([]interface{})
+		case k == "schemes":
+			c.warn(append(slices.Clip(loc), k), "schemes of an operation are not supported by OpenAPI and dropped")
+		}
+	}
+
+	var body map[string]interface{}
+	var form []map[string]interface{}
+	parameters, _ := op["parameters"].([]interface{})
+	for i, p := range append(slices.Clip(shared), parameters...) {
+		resolved, ref := c.resolveParameter(p)
+		switch {
+		case resolved["in"] == "body":
+			body = resolved
+		case resolved["in"] == "formData":
+			// overrides a formData parameter of the Path Item of the same name
+			form = slices.DeleteFunc(form, func(f map[string]interface{}) bool { return f["name"] == resolved["name"] })
+			form = append(form, resolved)
+		case ref != "":
+			out["parameters"] = append(list(out["parameters"]), map[string]interface{}{"$ref": ref})
+		default:
+			out["parameters"] = append(list(out["parameters"]), c.parameter(resolved, append(slices.Clip(loc), "parameters", fmt.Sprint(i-len(shared)))))
+		}
+	}
+	if body != nil {
+		out["requestBody"] = c.bodyRequest(body, consumes)
+	} else if len(form) > 0 {
+		out["requestBody"] = c.formRequest(form, consumes)
+	}
+
+	responses := make(map[string]interface{})
+	swaggerResponses, _ := op["responses"].(map[string]interface{})
+	for _, code := range slices.Sorted(maps.Keys(swaggerResponses)) {
+		r, ok := swaggerResponses[code].(map[string]interface{})
+		switch {
+		case !ok:
+			responses[code] = swaggerResponses[code] // an extension
+		case r["$ref"] != nil:
+			responses[code] = map[string]interface{}{"$ref": swaggerRefs.Replace(fmt.Sprint(r["$ref"]))}
+		default:
+			responses[code] = c.response(r, produces)
+		}
+	}
+	out["responses"] = responses
+	return out
+}
+
+// resolveParameter returns the parameter p refers to within the document, along with the reference to its component
+// if p is a reference.
+func (c *swaggerConverter) resolveParameter(p interface{}) (map[string]interface{}, string) {
+	parameter, _ := p.(map[string]interface{})
+	ref, ok := parameter["$ref"].(string)
+	if !ok {
+		return parameter, ""
+	}
+	if name, ok := strings.CutPrefix(ref, "#/parameters/"); ok {
+		parameters, _ := c.swagger["parameters"].(map[string]interface{})
+		if resolved, ok := parameters[name].(map[string]interface{}); ok {
+			return resolved, swaggerRefs.Replace(ref)
+		}
+	}
+	return parameter, ref // in another document
+}
+
+// parameter converts the query, header, path or cookie parameter p, located at loc.
+func (c *swaggerConverter) parameter(p map[string]interface{}, loc []string) map[string]interface{} {
+	out := make(map[string]interface{})
+	for _, k := range slices.Sorted(maps.Keys(p)) {
+		if k == "name" || k == "in" || k == "description" || k == "required" || k == "allowEmptyValue" || strings.HasPrefix(k, "x-") {
+			out[k] = p[k]
+		}
+	}
+	out["schema"] = schemaOf(p)
+	if p["type"] != "array" {
+		return out
+	}
+	switch p["collectionFormat"] {
+	case nil, "csv":
+		if p["in"] == "query" {
+			out["explode"] = false // the default of the form style is to explode
+		}
+	case "ssv":
+		out["style"] = "spaceDelimited"
+	case "pipes":
+		out["style"] = "pipeDelimited"
+	case "multi":
+		// the default of the form style
+	default:
+		c.warn(append(slices.Clip(loc), "collectionFormat"), "collectionFormat %q is not supported by OpenAPI and dropped", p["collectionFormat"])
+	}
+	return out
+}
+
+// schemaOf returns the schema of the type of a non-body parameter, header or items p.
+func schemaOf(p map[string]interface{}) map[string]interface{} {
+	schema := make(map[string]interface{})
+	for _, k := range slices.Sorted(maps.Keys(p)) {
+		switch k {
+		case "type", "format", "default", "maximum", "exclusiveMaximum", "minimum", "exclusiveMinimum", "maxLength",
+			"minLength", "pattern", "maxItems", "minItems", "uniqueItems", "enum", "multipleOf", "x-nullable":
+			schema[k] = p[k]
+		case "items":
+			if items, ok := p[k].(map[string]interface{}); ok {
+				schema[k] = schemaOf(items)
+			}
+		}
+	}
+	return schema
+}
+
+// bodyRequest converts the body parameter p to a RequestBody of the media types of consumes.
+func (c *swaggerConverter) bodyRequest(p map[string]interface{}, consumes []interface{}) map[string]interface{} {
+	out := make(map[string]interface{})
+	for _, k := range slices.Sorted(maps.Keys(p)) {
+		if k == "description" || k == "required" || strings.HasPrefix(k, "x-") {
+			out[k] = p[k]
+		}
+	}
+	content := make(map[string]interface{})
+	for _, mediaType := range mediaTypes(consumes, "application/json") {
+		content[mediaType] = map[string]interface{}{"schema": p["schema"]}
+	}
+	out["content"] = content
+	return out
+}
+
+// formRequest converts the formData parameters form to a RequestBody of an object with a property of each, of the form
+// media types of consumes.
+func (c *swaggerConverter) formRequest(form []map[string]interface{}, consumes []interface{}) map[string]interface{} {
+	properties := make(map[string]interface{})
+	var required []interface{}
+	mediaType := "application/x-www-form-urlencoded"
+	for _, p := range form {
+		name := fmt.Sprint(p["name"])
+		property := schemaOf(p)
+		if description, ok := p["description"]; ok {
+			property["description"] = description
+		}
+		properties[name] = property
+		if p["required"] == true {
+			required = append(required, name)
+		}
+		if p["type"] == "file" {
+			mediaType = "multipart/form-data"
+		}
+	}
+	schema := map[string]interface{}{"type": "object", "properties": properties}
+	if required != nil {
+		schema["required"] = required
+	}
+	var forms []string
+	for _, mt := range mediaTypes(consumes, mediaType) {
+		if mt == "multipart/form-data" || mt == "application/x-www-form-urlencoded" {
+			forms = append(forms, mt)
+		}
+	}
+	if forms == nil {
+		forms = []string{mediaType}
+	}
+	content := make(map[string]interface{})
+	for _, mt := range forms {
+		content[mt] = map[string]interface{}{"schema": schema}
+	}
+	return map[string]interface{}{"content": content}
+}
+
+// response converts r to a Response with content of the media types of produces.
+func (c *swaggerConverter) response(r map[string]interface{}, produces []interface{}) map[string]interface{} {
+	out := map[string]interface{}{"description": r["description"]}
+	content := make(map[string]interface{})
+	if schema, ok := r["schema"]; ok {
+		for _, mediaType := range mediaTypes(produces, "application/json") {
+			content[mediaType] = map[string]interface{}{"schema": schema}
+		}
+	}
+	examples, _ := r["examples"].(map[string]interface{})
+	for _, mediaType := range slices.Sorted(maps.Keys(examples)) {
+		m, ok := content[mediaType].(map[string]interface{})
+		if !ok {
+			m = make(map[string]interface{})
+			content[mediaType] = m
+		}
+		m["example"] = examples[mediaType]
+	}
+	if len(content) > 0 {
+		out["content"] = content
+	}
+	headers, _ := r["headers"].(map[string]interface{})
+	for _, name := range slices.Sorted(maps.Keys(headers)) {
+		h, _ := headers[name].(map[string]interface{})
+		header := map[string]interface{}{"schema": schemaOf(h)}
+		if description, ok := h["description"]; ok {
+			header["description"] = description
+		}
+		put(out, "headers", name, header)
+	}
+	for k, v := range r {
+		if strings.HasPrefix(k, "x-") {
+			out[k] = v
+		}
+	}
+	return out
+}
+
+// Swagger 2.0 OAuth2 flows by their name in OpenAPI.
+var flows = map[string]string{
+	"implicit":    "implicit",
+	"password":    "password",
+	"application": "clientCredentials",
+	"accessCode":  "authorizationCode",
+}
+
+// securityScheme converts s, located at loc, to a Security Scheme Object.
+func (c *swaggerConverter) securityScheme(s map[string]interface{}, loc []string) map[string]interface{} {
+	out := make(map[string]interface{})
+	for _, k := range slices.Sorted(maps.Keys(s)) {
+		if k == "description" || strings.HasPrefix(k, "x-") {
+			out[k] = s[k]
+		}
+	}
+	switch s["type"] {
+	case "basic":
+		out["type"], out["scheme"] = "http", "basic"
+	case "apiKey":
+		out["type"], out["name"], out["in"] = "apiKey", s["name"], s["in"]
+	case "oauth2":
+		flow := map[string]interface{}{"scopes": s["scopes"]}
+		if s["scopes"] == nil {
+			flow["scopes"] = map[string]interface{}{}
+		}
+		for _, k := range []string{"authorizationUrl", "tokenUrl"} {
+			if url, ok := s[k]; ok {
+				flow[k] = url
+			}
+		}
+		out["type"] = "oauth2"
+		name, ok := flows[fmt.Sprint(s["flow"])]
+		if !ok {
+			out["flows"] = map[string]interface{}{}
+			c.warn(append(slices.Clip(loc), "flow"), "flow %q is not an OAuth2 flow of Swagger 2.0 and dropped", s["flow"])
+			break
+		}
+		out["flows"] = map[string]interface{}{name: flow}
+	}
+	return out
+}
+
+// schema30 converts s, located at loc, from the Swagger 2.0 dialect to the OpenAPI 3.0 dialect.
+func (c *swaggerConverter) schema30(s map[string]interface{}, loc []string) {
+	if ref, ok := s["$ref"].(string); ok {
+		s["$ref"] = swaggerRefs.Replace(ref)
+	}
+	if s["type"] == "file" {
+		s["type"], s["format"] = "string", string(BinaryFormat)
+	}
+	if nullable, ok := s["x-nullable"]; ok {
+		delete(s, "x-nullable")
+		s["nullable"] = nullable
+	}
+	if propertyName, ok := s["discriminator"].(string); ok {
+		s["discriminator"] = map[string]interface{}{"propertyName": propertyName}
+	}
+}
+
+// mediaTypes returns the media types of list, or fallback if list is empty.
+func mediaTypes(list []interface{}, fallback string) []string {
+	if len(list) == 0 {
+		return []string{fallback}
+	}
+	mediaTypes := make([]string, len(list))
+	for i, mediaType := range list {
+		mediaTypes[i] = fmt.Sprint(mediaType)
+	}
+	return mediaTypes
+}
+
+// put sets the member name of the object m[key], creating it if needed.
+func put(m map[string]interface{}, key string, name string, v interface{}) {
+	object, ok := m[key].(map[string]interface{})
+	if !ok {
+		object = make(map[string]interface{})
+		m[key] = object
+	}
+	object[name] = v
+}
+
+func list(v interface{}) []interface{} {
+	l, _ := v.([]interface{})
+	return l
+}
@@ -0,0 +1,143 @@
+package oas
+
+import (
+	"encoding/json"
+	"slices"
+	"testing"
+)
+
+func TestParseSwagger(t *testing.T) {
+	data := `
+swagger: "2.0"
+info:
+  title: Pets
+  version: 1.0.0
+host: pets.example.com
+basePath: /v1
+schemes: [https]
+consumes: [application/json]
+produces: [application/json]
+paths:
+  /pets:
+    parameters:
+      - $ref: "#/parameters/limit"
+    get:
+      parameters:
+        - name: tags
+          in: query
+          type: array
+          items: {type: string}
+          collectionFormat: pipes
+      responses:
+        "200":
+          description: pets
+          headers:
+            X-Total: {type: integer, description: the total}
+          schema:
+            type: array
+            items: {$ref: "#/definitions/Pet"}
+          examples:
+            application/json: [{name: Tom}]
+        default:
+          $ref: "#/responses/Error"
+    post:
+      schemes: [http]
+      parameters:
+        - name: pet
+          in: body
+          required: true
+          schema: {$ref: "#/definitions/Pet"}
+      responses:
+        "201": {description: created}
+  /pets/{id}/photo:
+    put:
+      consumes: [multipart/form-data]
+      parameters:
+        - {name: id, in: path, required: true, type: string}
+        - {name: photo, in: formData, required: true, type: file}
+        - {name: caption, in: formData, type: string, maxLength: 140}
+      responses:
+        "204": {description: uploaded}
+parameters:
+  limit: {name: limit, in: query, type: integer, maximum: 100, exclusiveMaximum: true}
+responses:
+  Error:
+    description: error
+    schema: {type: string}
+definitions:
+  Pet:
+    type: object
+    discriminator: kind
+    required: [name, kind]
+    properties:
+      name: {type: string, x-nullable: true}
+      kind: {type: string}
+securityDefinitions:
+  basic: {type: basic}
+  token: {type: apiKey, name: X-Token, in: header}
+  oauth:
+    type: oauth2
+    flow: accessCode
+    authorizationUrl: https://example.com/authorize
+    tokenUrl: https://example.com/token
+    scopes: {read: read pets}
+  legacy: {type: oauth2, flow: hybrid, scopes: {}}
+`
+	// Act
+	doc, warnings, err := ParseSwagger([]byte(data))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if doc.Version != "3.1.0" {
+		t.Errorf("got version %q", doc.Version)
+	}
+	pets := doc.Paths["/pets"]
+	photo := doc.Paths["/pets/{id}/photo"].Put
+	tests := []struct {
+		v    interface{}
+		want string
+	}{
+		{doc.Servers, `[{"url":"https://pets.example.com/v1"}]`},
+		{pets.Parameters[0].Ref, `"#/components/parameters/limit"`},
+		{pets.Get.Parameters, `[{"name":"tags","in":"query","style":"pipeDelimited","schema":{"type":"array","items":{"type":"string"}}}]`},
+		{pets.Get.Responses["200"], `{"description":"pets","headers":{"X-Total":{"description":"the total","schema":{"type":"integer"}}},` +
+			`"content":{"application/json":{"schema":{"type":"array","items":{"$ref":"#/components/schemas/Pet"}},"example":[{"name":"Tom"}]}}}`},
+		{pets.Get.Responses["default"], `{"$ref":"#/components/responses/Error"}`},
+		{pets.Post.RequestBody, `{"content":{"application/json":{"schema":{"$ref":"#/components/schemas/Pet"}}},"required":true}`},
+		{photo.Parameters, `[{"name":"id","in":"path","required":true,"schema":{"type":"string"}}]`},
+		{photo.RequestBody, `{"content":{"multipart/form-data":{"schema":{"type":"object","required":["photo"],"properties":{` +
+			`"caption":{"type":"string","maxLength":140},"photo":{"type":"string","contentMediaType":"application/octet-stream"}}}}}}`},
+		{doc.Components.Parameters["limit"], `{"name":"limit","in":"query","schema":{"type":"integer","exclusiveMaximum":100}}`},
+		{doc.Components.Schemas["Pet"], `{"type":"object","required":["name","kind"],"properties":{` +
+			`"kind":{"type":"string"},"name":{"type":["null","string"]}},"discriminator":{"propertyName":"kind"}}`},
+		{doc.Components.SecuritySchemes["basic"], `{"type":"http","scheme":"basic"}`},
+		{doc.Components.SecuritySchemes["token"], `{"type":"apiKey","name":"X-Token","in":"header"}`},
+		{doc.Components.SecuritySchemes["oauth"], `{"type":"oauth2","flows":{"authorizationCode":{` +
+			`"authorizationUrl":"https://example.com/authorize","tokenUrl":"https://example.com/token","scopes":{"read":"read pets"}}}}`},
+		{doc.Components.SecuritySchemes["legacy"], `{"type":"oauth2","flows":{}}`},
+	}
+	for _, tt := range tests {
+		b, err := json.Marshal(tt.v)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if string(b) != tt.want {
+			t.Errorf("got %s, want %s", b, tt.want)
+		}
+	}
+	want := []Warning{
+		{"/paths/~1pets/post/schemes", "schemes of an operation are not supported by OpenAPI and dropped"},
+		{"/securityDefinitions/legacy/flow", `flow "hybrid" is not an OAuth2 flow of Swagger 2.0 and dropped`},
+	}
+	if !slices.EqualFunc(warnings, want, func(a *Warning, b Warning) bool { return *a == b }) {
+		t.Errorf("got %v, want %v", warnings, want)
+	}
+}
+
+func TestParseSwagger_version(t *testing.T) {
+	// Act
+	_, _, err := ParseSwagger([]byte(`{"openapi": "3.0.3"}`))
+	if err == nil {
+		t.Error("got nil error, want error")
+	}
+}
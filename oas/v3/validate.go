@@ -0,0 +1,460 @@
+package oas
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"maps"
+	"net/url"
+	"reflect"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/go-playground/validator/v10"
+
+	"github.com/MaiMee1/go-apispec/oas/internal/validate"
+	"github.com/MaiMee1/go-apispec/oas/jsonpointer"
+	"github.com/MaiMee1/go-apispec/oas/resolve"
+)
+
+// Error describes a violation of the OpenAPI specification at a location of the document.
+type Error struct {
+	Location jsonpointer.Ptr
+	Message  string
+}
+
+func (e *Error) Error() string {
+	return fmt.Sprintf("%q: %s", e.Location, e.Message)
+}
+
+// Errors is the list of violations found by OpenAPI.Validate, sorted by location.
+type Errors []*Error
+
+func (e Errors) Error() string {
+	b := strings.Builder{}
+	for i, err := range e {
+		if i > 0 {
+			b.WriteRune('\n')
+		}
+		b.WriteString(err.Error())
+	}
+	return b.String()
+}
+
+// Validate checks doc against the rules of the specification and returns Errors if any is violated.
+//
+// The rules are those of the OpenAPI version of doc, which must be 3.0 or 3.1. Besides constraints on individual fields,
+// it checks that operation IDs are unique, that path templates and path parameters match, that parameters are not
+// duplicated, that references can be resolved, that security requirements name defined schemes, and that map keys have
+// the right syntax, such as the runtime expressions of callback keys.
+func (doc *OpenAPI) Validate() error {
+	doc.bindScope()
+
+	var errs Errors
+	if err := validate.Struct(doc); err != nil {
+		errs = append(errs, doc.structErrors(err)...)
+	}
+	c := &checker{doc: doc, operationIds: make(map[string]jsonpointer.Ptr), operations: make(map[*Operation]bool)}
+	c.check()
+	errs = append(errs, c.errs...)
+
+	if len(errs) == 0 {
+		return nil
+	}
+	slices.SortStableFunc(errs, func(a, b *Error) int {
+		return strings.Compare(string(a.Location), string(b.Location))
+	})
+	return errs
+}
+
+// bindScope allows references of documents not created by Load to be resolved within the document.
+func (doc *OpenAPI) bindScope() {
+	if _, _, ok := resolve.Scope(doc.ctx); ok {
+		return
+	}
+	r := resolve.New(nil)
+	base := &url.URL{}
+	r.Register(base, doc)
+	resolve.Bind(doc, resolve.WithScope(context.TODO(), r, base))
+}
+
+func (doc *OpenAPI) structErrors(err error) Errors {
+	var fieldErrors validator.ValidationErrors
+	if !errors.As(err, &fieldErrors) {
+		return Errors{{Message: err.Error()}}
+	}
+	errs := make(Errors, 0, len(fieldErrors))
+	for _, fe := range fieldErrors {
+		loc := jsonpointer.New(validate.Tokens(fe.Namespace())...)
+		errs = append(errs, &Error{loc, doc.describe(loc, fe)})
+	}
+	return errs
+}
+
+// describe explains the rule of fe violated by the field at loc, naming the fields and values of its parameter as they
+// appear in JSON.
+func (doc *OpenAPI) describe(loc jsonpointer.Ptr, fe validator.FieldError) string {
+	var parent reflect.Type
+	if v, err := loc.Parent().Access(doc); err == nil {
+		for v.Kind() == reflect.Pointer || v.Kind() == reflect.Interface {
+			v = v.Elem()
+		}
+		parent = v.Type()
+	}
+	// conditions describes the pairs of fields and values of the parameter
+	conditions := func() string {
+		params := strings.Fields(fe.Param())
+		var conds []string
+		for i := 0; i+1 < len(params); i += 2 {
+			name, typ := jsonField(parent, params[i])
+			conds = append(conds, fmt.Sprintf("%q is %s", name, jsonValue(typ, params[i+1])))
+		}
+		return strings.Join(conds, " and ")
+	}
+	// fields describes the fields of the parameter
+	fields := func() string {
+		var names []string
+		for _, param := range strings.Fields(fe.Param()) {
+			name, _ := jsonField(parent, param)
+			names = append(names, strconv.Quote(name))
+		}
+		return strings.Join(names, " and ")
+	}
+
+	switch fe.Tag() {
+	case "required":
+		return "must be set"
+	case "required_without":
+		return fmt.Sprintf("must be set when %s is absent", fields())
+	case "required_without_all":
+		return fmt.Sprintf("must be set when %s are absent", fields())
+	case "required_if":
+		return fmt.Sprintf("must be set when %s", conditions())
+	case "excluded_with":
+		return fmt.Sprintf("must not be set together with %s", fields())
+	case "excluded_unless":
+		return fmt.Sprintf("must not be set unless %s", conditions())
+	case "oneof":
+		var values []string
+		for _, param := range strings.Fields(fe.Param()) {
+			values = append(values, jsonValue(fe.Type(), param))
+		}
+		return fmt.Sprintf("must be one of %s", strings.Join(values, ", "))
+	case "min":
+		switch fe.Kind() {
+		case reflect.Slice, reflect.Array, reflect.Map:
+			return fmt.Sprintf("must have at least %s items", fe.Param())
+		case reflect.String:
+			return fmt.Sprintf("must have at least %s characters", fe.Param())
+		default:
+			return fmt.Sprintf("must be at least %s", fe.Param())
+		}
+	case "url", "uri":
+		return "must be an absolute URL"
+	case "email":
+		return "must be an email address"
+	case "url_fragment":
+		return "must be a valid URL fragment"
+	case "regex":
+		return "must be a valid regular expression"
+	}
+	if fe.Param() != "" {
+		return fmt.Sprintf("must satisfy %s=%s", fe.Tag(), fe.Param())
+	}
+	return fmt.Sprintf("must satisfy %s", fe.Tag())
+}
+
+// jsonField returns the JSON name and the type of the field of t named by the validation parameter name.
+func jsonField(t reflect.Type, name string) (string, reflect.Type) {
+	if t == nil || t.Kind() != reflect.Struct {
+		return name, nil
+	}
+	sf, ok := t.FieldByName(name)
+	if !ok {
+		return name, nil
+	}
+	if tag, _, _ := strings.Cut(sf.Tag.Get("json"), ","); tag != "" && tag != "-" {
+		name = tag
+	}
+	return name, sf.Type
+}
+
+// jsonValue returns the JSON of the value of type t written as the validation parameter param.
+func jsonValue(t reflect.Type, param string) string {
+	if t == nil {
+		return strconv.Quote(param)
+	}
+	v := reflect.New(t).Elem()
+	switch t.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		i, err := strconv.ParseInt(param, 10, 64)
+		if err != nil {
+			return strconv.Quote(param)
+		}
+		v.SetInt(i)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		u, err := strconv.ParseUint(param, 10, 64)
+		if err != nil {
+			return strconv.Quote(param)
+		}
+		v.SetUint(u)
+	case reflect.Bool:
+		b, err := strconv.ParseBool(param)
+		if err != nil {
+			return strconv.Quote(param)
+		}
+		v.SetBool(b)
+	case reflect.String:
+		v.SetString(param)
+	default:
+		return strconv.Quote(param)
+	}
+	b, err := json.Marshal(v.Interface())
+	if err != nil {
+		return strconv.Quote(param)
+	}
+	return string(b)
+}
+
+var pathTemplateRe = regexp.MustCompile(`\{([^{}]+)}`)
+
+type checker struct {
+	doc          *OpenAPI
+	errs         Errors
+	operationIds map[string]jsonpointer.Ptr
+	operations   map[*Operation]bool // operations with a checked operationId, shared by path items and their references
+}
+
+func (c *checker) report(loc []string, format string, args ...any) {
+	c.errs = append(c.errs, &Error{jsonpointer.New(loc...), fmt.Sprintf(format, args...)})
+}
+
+func (c *checker) check() {
+	doc := c.doc
+	c.checkVersion()
+	for _, path := range slices.Sorted(maps.Keys(doc.Paths)) {
+		c.checkPathItem(doc.Paths[path], []string{"paths", path}, path)
+	}
+	for _, name := range slices.Sorted(maps.Keys(doc.Webhooks)) {
+		c.checkPathItem(doc.Webhooks[name], []string{"webhooks", name}, "")
+	}
+	c.checkSecurity(doc.Security, []string{"security"})
+	c.checkComponents()
+	c.checkRefs()
+}
+
+// checkVersion checks that the version of the document is supported and that the document only uses what its version
+// has.
+func (c *checker) checkVersion() {
+	doc := c.doc
+	if doc.Version == "" {
+		return // reported as required
+	}
+	if err := doc.Version.Validate(); err != nil {
+		c.report([]string{"openapi"}, "%v", err)
+		return
+	}
+	if !doc.Version.IsSupported() {
+		c.report([]string{"openapi"}, "unsupported OpenAPI version %q, want 3.0 or 3.1", doc.Version)
+		return
+	}
+	if doc.Version.Minor() > 0 {
+		return
+	}
+	if doc.Paths == nil {
+		c.report([]string{"paths"}, "paths is required by OpenAPI 3.0")
+	}
+	if doc.Webhooks != nil {
+		c.report([]string{"webhooks"}, "webhooks are not supported by OpenAPI 3.0")
+	}
+	if doc.Components.PathItems != nil {
+		c.report([]string{"components", "pathItems"}, "path items are not supported by OpenAPI 3.0 components")
+	}
+	for _, name := range slices.Sorted(maps.Keys(doc.Components.SecuritySchemes)) {
+		if doc.Components.SecuritySchemes[name].Type == MutualTLSScheme {
+			c.report([]string{"components", "securitySchemes", name, "type"}, "the mutualTLS security scheme is not supported by OpenAPI 3.0")
+		}
+	}
+}
+
+func (c *checker) checkComponents() {
+	for key, m := range c.doc.components() {
+		for _, name := range m.MapKeys() {
+			if !isValidComponentsKey(name.String()) {
+				c.report([]string{"components", key, name.String()}, "invalid components key %q, want %s", name, fieldNameRe)
+			}
+		}
+	}
+	for _, name := range slices.Sorted(maps.Keys(c.doc.Components.PathItems)) {
+		c.checkPathItem(c.doc.Components.PathItems[name], []string{"components", "pathItems", name}, "")
+	}
+	for _, name := range slices.Sorted(maps.Keys(c.doc.Components.Callbacks)) {
+		c.checkCallback(c.doc.Components.Callbacks[name], []string{"components", "callbacks", name})
+	}
+}
+
+// checkPathItem checks item at loc, which is a Paths item of template if template is not empty.
+func (c *checker) checkPathItem(item PathItem, loc []string, template string) {
+	if item.Ref != "" {
+		resolved, err := item.Resolve()
+		if err != nil {
+			return // reported by checkRefs
+		}
+		item = resolved
+	}
+
+	params := c.checkParameters(item.Parameters, append(loc[:len(loc):len(loc)], "parameters"))
+	if template != "" {
+		c.checkPathParameters(params, template)
+	}
+	for _, method := range []struct {
+		name string
+		op   *Operation
+	}{
+		{"get", item.Get}, {"put", item.Put}, {"post", item.Post}, {"delete", item.Delete},
+		{"options", item.Options}, {"head", item.Head}, {"patch", item.Patch}, {"trace", item.Trace},
+	} {
+		if method.op != nil {
+			c.checkOperation(method.op, append(loc[:len(loc):len(loc)], method.name), template, params)
+		}
+	}
+}
+
+type parameterKey struct {
+	name string
+	in   Location
+}
+
+type locatedParameter struct {
+	Parameter
+	loc []string
+}
+
+// checkParameters reports duplicate parameters and returns the resolved parameters by name and location.
+func (c *checker) checkParameters(params []Parameter, loc []string) map[parameterKey]locatedParameter {
+	m := make(map[parameterKey]locatedParameter)
+	for i, param := range params {
+		ploc := append(loc[:len(loc):len(loc)], fmt.Sprint(i))
+		if param.Ref != "" {
+			resolved, err := param.Resolve()
+			if err != nil {
+				continue // reported by checkRefs
+			}
+			param = resolved
+		}
+		key := parameterKey{param.Name, param.In}
+		if param.In == HeaderLocation {
+			// header names are case-insensitive
+			key.name = strings.ToLower(key.name)
+		}
+		if prev, ok := m[key]; ok {
+			c.report(ploc, "duplicate %s parameter %q, also defined at %q", param.In, param.Name, jsonpointer.New(prev.loc...))
+			continue
+		}
+		m[key] = locatedParameter{param, ploc}
+	}
+	return m
+}
+
+func (c *checker) checkOperation(op *Operation, loc []string, template string, pathParams map[parameterKey]locatedParameter) {
+	sub := func(tokens ...string) []string {
+		return append(loc[:len(loc):len(loc)], tokens...)
+	}
+
+	if op.OperationId != "" && !c.operations[op] {
+		c.operations[op] = true
+		if prev, ok := c.operationIds[op.OperationId]; ok {
+			c.report(sub("operationId"), "duplicate operationId %q, also used at %q", op.OperationId, prev)
+		} else {
+			c.operationIds[op.OperationId] = jsonpointer.New(sub("operationId")...)
+		}
+	}
+
+	params := c.checkParameters(op.Parameters, sub("parameters"))
+	if template != "" {
+		// operation parameters override path item parameters
+		effective := maps.Clone(pathParams)
+		maps.Copy(effective, params)
+
+		for _, match := range pathTemplateRe.FindAllStringSubmatch(template, -1) {
+			// that path parameters are required is checked by the struct rules
+			if _, ok := effective[parameterKey{match[1], PathLocation}]; !ok {
+				c.report(loc, "path parameter %q of %q is not defined", match[1], template)
+			}
+		}
+		c.checkPathParameters(params, template)
+	}
+
+	for _, code := range slices.Sorted(maps.Keys(op.Responses)) {
+		if !isValidResponsesKey(code) {
+			c.report(sub("responses", code), "invalid response key %q, want \"default\" or %s", code, httpStatusCodeRe)
+		}
+	}
+	for _, name := range slices.Sorted(maps.Keys(op.Callbacks)) {
+		c.checkCallback(op.Callbacks[name], sub("callbacks", name))
+	}
+	if op.Security != nil {
+		c.checkSecurity(op.Security, sub("security"))
+	}
+}
+
+// checkPathParameters reports path parameters in params that do not appear in template.
+func (c *checker) checkPathParameters(params map[parameterKey]locatedParameter, template string) {
+	var names []string
+	for _, match := range pathTemplateRe.FindAllStringSubmatch(template, -1) {
+		names = append(names, match[1])
+	}
+	for _, key := range slices.SortedFunc(maps.Keys(params), compareParameterKeys) {
+		if key.in == PathLocation && !slices.Contains(names, key.name) {
+			c.report(params[key].loc, "path parameter %q is not in %q", key.name, template)
+		}
+	}
+}
+
+func compareParameterKeys(a, b parameterKey) int {
+	if a.in != b.in {
+		return int(a.in) - int(b.in)
+	}
+	return strings.Compare(a.name, b.name)
+}
+
+func (c *checker) checkCallback(callback Callback, loc []string) {
+	if callback.Ref != "" {
+		resolved, err := callback.Resolve()
+		if err != nil {
+			return // reported by checkRefs
+		}
+		callback = resolved
+	}
+	for _, expression := range slices.Sorted(maps.Keys(callback.Value)) {
+		if !isValidCallbackKey(string(expression)) {
+			c.report(append(loc[:len(loc):len(loc)], string(expression)), "invalid runtime expression %q", expression)
+		}
+		c.checkPathItem(callback.Value[expression], append(loc[:len(loc):len(loc)], string(expression)), "")
+	}
+}
+
+func (c *checker) checkSecurity(requirements []SecurityRequirement, loc []string) {
+	for i, requirement := range requirements {
+		for _, name := range slices.Sorted(maps.Keys(requirement)) {
+			if _, ok := c.doc.Components.SecuritySchemes[name]; !ok {
+				c.report(append(loc[:len(loc):len(loc)], fmt.Sprint(i), name), "security scheme %q is not defined in components", name)
+			}
+		}
+	}
+}
+
+// checkRefs reports references that cannot be resolved.
+func (c *checker) checkRefs() {
+	var fn walkFunc
+	fn = func(v reflect.Value, loc []string) error {
+		if _, err := resolveValue(v); err != nil {
+			c.report(append(loc[:len(loc):len(loc)], "$ref"), "%v", err)
+		}
+		return walkFields(v, loc, fn)
+	}
+	_ = walkRefs(reflect.ValueOf(c.doc).Elem(), nil, fn)
+}
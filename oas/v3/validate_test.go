@@ -0,0 +1,113 @@
+package oas
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"testing"
+
+	"github.com/MaiMee1/go-apispec/oas/jsonpointer"
+)
+
+func TestOpenAPI_Validate(t *testing.T) {
+	const info = `"openapi":"3.1.0","info":{"title":"test","version":"1"}`
+	tests := []struct {
+		document string
+		want     []jsonpointer.Ptr
+	}{
+		{`{` + info + `}`, nil},
+		{`{` + info + `,"paths":{
+			"/a":{"get":{"operationId":"op","responses":{"200":{"description":"ok"}}}},
+			"/b":{"get":{"operationId":"op","responses":{"200":{"description":"ok"}}}}
+		}}`, []jsonpointer.Ptr{"/paths/~1b/get/operationId"}},
+		{`{` + info + `,"paths":{
+			"/a/{id}":{"get":{"responses":{"200":{"description":"ok"}}}},
+			"/b/{id}":{"get":{"parameters":[{"name":"id","in":"path"}],"responses":{"200":{"description":"ok"}}}},
+			"/c":{"parameters":[{"name":"id","in":"path","required":true}],"get":{"responses":{"200":{"description":"ok"}}}},
+			"/d/{id}":{"parameters":[{"name":"id","in":"path","required":true}],"get":{"responses":{"200":{"description":"ok"}}}}
+		}}`, []jsonpointer.Ptr{"/paths/~1a~1{id}/get", "/paths/~1b~1{id}/get/parameters/0/required", "/paths/~1c/parameters/0"}},
+		{`{` + info + `,"paths":{
+			"/e/{id}":{"get":{"parameters":[{"name":"x","in":"path","required":true},{"name":"id","in":"path","required":true}],"responses":{"200":{"description":"ok"}}}}
+		}}`, []jsonpointer.Ptr{"/paths/~1e~1{id}/get/parameters/0"}},
+		{`{` + info + `,"paths":{
+			"/a":{"get":{"parameters":[{"name":"X-Id","in":"header"},{"name":"x-id","in":"header"},{"name":"x-id","in":"query"}],"responses":{"200":{"description":"ok"}}}}
+		}}`, []jsonpointer.Ptr{"/paths/~1a/get/parameters/1"}},
+		{`{` + info + `,"paths":{
+			"/a":{"get":{"responses":{"200":{"$ref":"#/components/responses/missing"},"2XX":{"description":"ok"},"600":{"description":"bad"}}}}
+		}}`, []jsonpointer.Ptr{"/paths/~1a/get/responses/200/$ref", "/paths/~1a/get/responses/600"}},
+		{`{` + info + `,"security":[{"key":[]}],"paths":{
+			"/a":{"get":{"security":[{"key":[]},{"oauth":[]}],"responses":{"200":{"description":"ok"}}}}
+		},"components":{"securitySchemes":{"key":{"type":"apiKey","name":"key","in":"header"}}}}`,
+			[]jsonpointer.Ptr{"/paths/~1a/get/security/1/oauth"}},
+		{`{` + info + `,"components":{"schemas":{"a b":{}}}}`, []jsonpointer.Ptr{"/components/schemas/a b"}},
+		{`{` + info + `,"components":{"callbacks":{"c":{
+			"{$request.body#/url}":{},
+			"https://example.com?id={$request.query.id}&m={$method}":{},
+			"{$request.cookie}":{},
+			"https://example.com/{$url":{}
+		}}}}`, []jsonpointer.Ptr{"/components/callbacks/c/https:~1~1example.com~1{$url", "/components/callbacks/c/{$request.cookie}"}},
+		{`{` + info + `,"paths":{"/a":{"$ref":"#/components/pathItems/a"}},
+			"components":{"pathItems":{"a":{"get":{"operationId":"op","responses":{"200":{"description":"ok"}}}}}}}`, nil},
+		{`{"openapi":"3.1.0","info":{"version":"1"}}`, []jsonpointer.Ptr{"/info/title"}},
+		{`{"openapi":"3.0.3","info":{"title":"test","version":"1"}}`, []jsonpointer.Ptr{"/paths"}},
+		{`{"openapi":"3.0.3","info":{"title":"test","version":"1"},"paths":{},
+			"webhooks":{"a":{"post":{"responses":{"200":{"description":"ok"}}}}},
+			"components":{"securitySchemes":{"tls":{"type":"mutualTLS"}}}}`, []jsonpointer.Ptr{"/components/securitySchemes/tls/type", "/webhooks"}},
+	}
+	for i, tt := range tests {
+		t.Run(fmt.Sprintf("%d", i), func(t *testing.T) {
+			_, err := ParseJson([]byte(tt.document))
+			var errs Errors
+			if err != nil && !errors.As(err, &errs) {
+				t.Fatal(err)
+			}
+			var got []jsonpointer.Ptr
+			for _, e := range errs {
+				got = append(got, e.Location)
+			}
+			if !slices.Equal(got, tt.want) {
+				t.Errorf("got %q, want %q\n%v", got, tt.want, err)
+			}
+		})
+	}
+}
+
+func TestOpenAPI_Validate_message(t *testing.T) {
+	const info = `"openapi":"3.1.0","info":{"title":"test","version":"1"}`
+	tests := []struct {
+		document string
+		want     string
+	}{
+		{`{"openapi":"3.1.0","info":{"version":"1"}}`, `"/info/title": must be set`},
+		{`{` + info + `,"components":{"parameters":{"p":{"in":"query"}}}}`,
+			`"/components/parameters/p/name": must be set when "$ref" is absent`},
+		{`{` + info + `,"components":{"parameters":{"p":{"name":"p","in":"path"}}}}`,
+			`"/components/parameters/p/required": must be set when "in" is "path"`},
+		{`{` + info + `,"components":{"links":{"l":{"operationId":"op","operationRef":"#/paths/~1a/get"}}}}`, ``},
+		{`{` + info + `,"components":{"links":{"l":{"description":"l"}}}}`,
+			`"/components/links/l/operationId": must be set when "operationRef" and "$ref" are absent` + "\n" +
+				`"/components/links/l/operationRef": must be set when "operationId" and "$ref" are absent`},
+		{`{` + info + `,"components":{"examples":{"e":{"value":1,"externalValue":"https://example.com"}}}}`,
+			`"/components/examples/e/externalValue": must not be set together with "value"` + "\n" +
+				`"/components/examples/e/value": must not be set together with "externalValue"`},
+		{`{` + info + `,"components":{"securitySchemes":{"s":{"type":"apiKey","in":"query"}}}}`,
+			`"/components/securitySchemes/s/name": must be set when "type" is "apiKey"`},
+		{`{` + info + `,"components":{"securitySchemes":{"s":{"type":"http","scheme":"basic","name":"s"}}}}`,
+			`"/components/securitySchemes/s/name": must not be set unless "type" is "apiKey"`},
+		{`{` + info + `,"components":{"securitySchemes":{"s":{"type":"apiKey","name":"s","in":"path"}}}}`,
+			`"/components/securitySchemes/s/in": must be one of "query", "header", "cookie"`},
+	}
+	for i, tt := range tests {
+		t.Run(fmt.Sprintf("%d", i), func(t *testing.T) {
+			// Act
+			_, err := ParseJson([]byte(tt.document))
+			var got string
+			if err != nil {
+				got = err.Error()
+			}
+			if got != tt.want {
+				t.Errorf("got\n%s\nwant\n%s", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,124 @@
+package oas
+
+import (
+	"cmp"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// semverRe is the regular expression suggested by Semantic Versioning 2.0.0, capturing the major, minor and patch
+// versions and the pre-release.
+var semverRe = regexp.MustCompile(`^(0|[1-9]\d*)\.(0|[1-9]\d*)\.(0|[1-9]\d*)` +
+	`(?:-((?:0|[1-9]\d*|\d*[a-zA-Z-][0-9a-zA-Z-]*)(?:\.(?:0|[1-9]\d*|\d*[a-zA-Z-][0-9a-zA-Z-]*))*))?` +
+	`(?:\+[0-9a-zA-Z-]+(?:\.[0-9a-zA-Z-]+)*)?$`)
+
+// Validate returns an error if v is not a version of Semantic Versioning 2.0.0, or if its major, minor or patch
+// version does not fit in an int.
+func (v SemanticVersion) Validate() error {
+	for i := 1; i <= 3; i++ {
+		if _, err := v.number(i); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Major returns the major version of v, or 0 if v is not valid.
+func (v SemanticVersion) Major() int {
+	n, _ := v.number(1)
+	return n
+}
+
+// Minor returns the minor version of v, or 0 if v is not valid.
+func (v SemanticVersion) Minor() int {
+	n, _ := v.number(2)
+	return n
+}
+
+// Patch returns the patch version of v, or 0 if v is not valid.
+func (v SemanticVersion) Patch() int {
+	n, _ := v.number(3)
+	return n
+}
+
+// number returns the major, minor or patch version of v by i from 1 to 3, or 0 and an error if v is not valid.
+func (v SemanticVersion) number(i int) (int, error) {
+	m := semverRe.FindStringSubmatch(string(v))
+	if m == nil {
+		return 0, fmt.Errorf("invalid semantic version %q", string(v))
+	}
+	n, err := strconv.Atoi(m[i])
+	if err != nil {
+		return 0, fmt.Errorf("invalid semantic version %q: %w", string(v), err)
+	}
+	return n, nil
+}
+
+// Compare returns -1, 0 or +1 as v has a lower, the same or a higher precedence than w. Build metadata is ignored. An
+// invalid version has a lower precedence than any valid one, and invalid versions are compared as strings.
+func (v SemanticVersion) Compare(w SemanticVersion) int {
+	a, b := semverRe.FindStringSubmatch(string(v)), semverRe.FindStringSubmatch(string(w))
+	switch {
+	case a == nil && b == nil:
+		return strings.Compare(string(v), string(w))
+	case a == nil:
+		return -1
+	case b == nil:
+		return +1
+	}
+	for i := 1; i <= 3; i++ {
+		if c := compareNumeric(a[i], b[i]); c != 0 {
+			return c
+		}
+	}
+	// a version without pre-release has a higher precedence
+	switch {
+	case a[4] == b[4]:
+		return 0
+	case a[4] == "":
+		return +1
+	case b[4] == "":
+		return -1
+	}
+	x, y := strings.Split(a[4], "."), strings.Split(b[4], ".")
+	for i := 0; i < min(len(x), len(y)); i++ {
+		if c := compareIdentifier(x[i], y[i]); c != 0 {
+			return c
+		}
+	}
+	return cmp.Compare(len(x), len(y))
+}
+
+// compareNumeric compares the decimal numbers a and b without leading zeros, which may not fit in an int.
+func compareNumeric(a, b string) int {
+	if c := cmp.Compare(len(a), len(b)); c != 0 {
+		return c
+	}
+	return strings.Compare(a, b)
+}
+
+// compareIdentifier compares the pre-release identifiers a and b. Numeric identifiers have a lower precedence than
+// alphanumeric ones.
+func compareIdentifier(a, b string) int {
+	numericA, numericB := isDigits(a), isDigits(b)
+	switch {
+	case numericA && numericB:
+		return compareNumeric(a, b)
+	case numericA:
+		return -1
+	case numericB:
+		return +1
+	}
+	return strings.Compare(a, b)
+}
+
+func isDigits(s string) bool {
+	return strings.Trim(s, "0123456789") == ""
+}
+
+// IsSupported reports whether v is a version of OpenAPI 3.0 or 3.1, which are the versions this package reads.
+func (v SemanticVersion) IsSupported() bool {
+	return v.Validate() == nil && v.Major() == 3 && v.Minor() <= 1
+}
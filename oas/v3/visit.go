@@ -0,0 +1,271 @@
+package oas
+
+import (
+	"iter"
+	"maps"
+	"reflect"
+	"slices"
+	"strconv"
+
+	"github.com/MaiMee1/go-apispec/oas/jsonpointer"
+	"github.com/MaiMee1/go-apispec/oas/jsonschema/draft2020"
+	"github.com/MaiMee1/go-apispec/oas/jsonschema/oas31"
+	"github.com/MaiMee1/go-apispec/oas/ser"
+)
+
+// Action tells Walk what to do with a node after visiting it.
+type Action int8
+
+const (
+	Continue Action = iota // walk the children of the node
+	Skip                   // do not walk the children of the node
+	Remove                 // remove the node from its parent
+	Stop                   // end the walk
+)
+
+// Visitor holds the callbacks of Walk, each called with a node of its type and the JSON Pointer of its location in the
+// document. Nodes whose callback is nil are walked without being visited.
+//
+// A callback may modify or replace the node it is given in place, in which case the children of the new node are
+// walked. To prune a node, it returns Skip to leave it as is or Remove to remove it: a member of a map or an element of
+// a slice is deleted, and a field is set to its zero value.
+type Visitor struct {
+	PathItem    func(v *PathItem, loc jsonpointer.Ptr) Action
+	Operation   func(v *Operation, loc jsonpointer.Ptr) Action
+	Parameter   func(v *Parameter, loc jsonpointer.Ptr) Action
+	RequestBody func(v *RequestBody, loc jsonpointer.Ptr) Action
+	Response    func(v *Response, loc jsonpointer.Ptr) Action
+	MediaType   func(v *MediaType, loc jsonpointer.Ptr) Action
+	Header      func(v *Header, loc jsonpointer.Ptr) Action
+	Schema      func(v *Schema, loc jsonpointer.Ptr) Action
+}
+
+// Walk visits the nodes of doc depth-first, each before its children, in paths, webhooks and then components, including
+// the path items of callbacks and the subschemas of schemas. Members of maps are visited in the order of their keys.
+//
+// References are visited as they are and not resolved. A schema field of a Parameter or a MediaType which is empty is
+// not visited.
+func (doc *OpenAPI) Walk(v Visitor) {
+	w := &walker{Visitor: v}
+	walkMap(w, doc.Paths, jsonpointer.New("paths"), (*walker).pathItem)
+	walkMap(w, doc.Webhooks, jsonpointer.New("webhooks"), (*walker).pathItem)
+
+	c, loc := &doc.Components, jsonpointer.New("components")
+	walkMap(w, c.Schemas, loc.Append("schemas"), (*walker).schema)
+	walkMap(w, c.Responses, loc.Append("responses"), (*walker).response)
+	walkMap(w, c.Parameters, loc.Append("parameters"), (*walker).parameter)
+	walkMap(w, c.RequestBodies, loc.Append("requestBodies"), (*walker).requestBody)
+	walkMap(w, c.Headers, loc.Append("headers"), (*walker).header)
+	walkMap(w, c.Callbacks, loc.Append("callbacks"), (*walker).callback)
+	walkMap(w, c.PathItems, loc.Append("pathItems"), (*walker).pathItem)
+}
+
+// IterSchema returns an iterator over the schemas of the paths and webhooks of doc, including subschemas but not
+// Components. The schemas may be modified in place.
+func (doc *OpenAPI) IterSchema() iter.Seq[*oas31.Schema] {
+	return func(yield func(*oas31.Schema) bool) {
+		w := &walker{Visitor: Visitor{
+			Schema: func(v *Schema, _ jsonpointer.Ptr) Action {
+				if !yield(v) {
+					return Stop
+				}
+				return Continue
+			},
+		}}
+		walkMap(w, doc.Paths, jsonpointer.New("paths"), (*walker).pathItem)
+		walkMap(w, doc.Webhooks, jsonpointer.New("webhooks"), (*walker).pathItem)
+	}
+}
+
+type walker struct {
+	Visitor
+	stopped bool
+}
+
+// nodeFunc walks a node at loc and reports whether to remove it.
+type nodeFunc[T any] func(w *walker, v *T, loc jsonpointer.Ptr) bool
+
+// visit calls fn with v, unless it is nil or the walk has stopped, and reports whether to walk the children of v and
+// whether to remove v.
+func visit[T any](w *walker, fn func(*T, jsonpointer.Ptr) Action, v *T, loc jsonpointer.Ptr) (walk, remove bool) {
+	if w.stopped {
+		return false, false
+	}
+	if fn == nil {
+		return true, false
+	}
+	switch fn(v, loc) {
+	case Skip:
+		return false, false
+	case Remove:
+		return false, true
+	case Stop:
+		w.stopped = true
+		return false, false
+	default:
+		return true, false
+	}
+}
+
+func walkMap[K ~string, T any](w *walker, m map[K]T, loc jsonpointer.Ptr, fn nodeFunc[T]) {
+	for _, k := range slices.Sorted(maps.Keys(m)) {
+		if w.stopped {
+			return
+		}
+		// map values are not addressable, so walk a copy and put it back
+		v := m[k]
+		if fn(w, &v, loc.Append(string(k))) {
+			delete(m, k)
+		} else {
+			m[k] = v
+		}
+	}
+}
+
+func walkSlice[T any](w *walker, s *[]T, loc jsonpointer.Ptr, fn nodeFunc[T]) {
+	n := 0
+	for i := range *s {
+		if w.stopped || !fn(w, &(*s)[i], loc.Append(strconv.Itoa(i))) {
+			(*s)[n] = (*s)[i]
+			n++
+		}
+	}
+	clear((*s)[n:])
+	*s = (*s)[:n]
+}
+
+func walkPtr[T any](w *walker, p **T, loc jsonpointer.Ptr, fn nodeFunc[T]) {
+	if *p != nil && fn(w, *p, loc) {
+		*p = nil
+	}
+}
+
+func (w *walker) pathItem(v *PathItem, loc jsonpointer.Ptr) bool {
+	walk, remove := visit(w, w.PathItem, v, loc)
+	if walk {
+		walkPtr(w, &v.Get, loc.Append("get"), (*walker).operation)
+		walkPtr(w, &v.Put, loc.Append("put"), (*walker).operation)
+		walkPtr(w, &v.Post, loc.Append("post"), (*walker).operation)
+		walkPtr(w, &v.Delete, loc.Append("delete"), (*walker).operation)
+		walkPtr(w, &v.Options, loc.Append("options"), (*walker).operation)
+		walkPtr(w, &v.Head, loc.Append("head"), (*walker).operation)
+		walkPtr(w, &v.Patch, loc.Append("patch"), (*walker).operation)
+		walkPtr(w, &v.Trace, loc.Append("trace"), (*walker).operation)
+		walkSlice(w, &v.Parameters, loc.Append("parameters"), (*walker).parameter)
+	}
+	return remove
+}
+
+func (w *walker) operation(v *Operation, loc jsonpointer.Ptr) bool {
+	walk, remove := visit(w, w.Operation, v, loc)
+	if walk {
+		walkSlice(w, &v.Parameters, loc.Append("parameters"), (*walker).parameter)
+		walkPtr(w, &v.RequestBody, loc.Append("requestBody"), (*walker).requestBody)
+		walkMap(w, v.Responses, loc.Append("responses"), (*walker).response)
+		walkMap(w, v.Callbacks, loc.Append("callbacks"), (*walker).callback)
+	}
+	return remove
+}
+
+// callback walks the path items of v, which is not visited itself.
+func (w *walker) callback(v *Callback, loc jsonpointer.Ptr) bool {
+	walkMap(w, v.Value, loc, (*walker).pathItem)
+	return false
+}
+
+func (w *walker) parameter(v *Parameter, loc jsonpointer.Ptr) bool {
+	walk, remove := visit(w, w.Parameter, v, loc)
+	if walk {
+		w.schemaField(&v.Schema, loc.Append("schema"))
+		walkMap(w, v.Content, loc.Append("content"), (*walker).mediaType)
+	}
+	return remove
+}
+
+func (w *walker) requestBody(v *RequestBody, loc jsonpointer.Ptr) bool {
+	walk, remove := visit(w, w.RequestBody, v, loc)
+	if walk {
+		walkMap(w, v.Content, loc.Append("content"), (*walker).mediaType)
+	}
+	return remove
+}
+
+func (w *walker) response(v *Response, loc jsonpointer.Ptr) bool {
+	walk, remove := visit(w, w.Response, v, loc)
+	if walk {
+		walkMap(w, v.Headers, loc.Append("headers"), (*walker).header)
+		walkMap(w, v.Content, loc.Append("content"), (*walker).mediaType)
+	}
+	return remove
+}
+
+func (w *walker) mediaType(v *MediaType, loc jsonpointer.Ptr) bool {
+	walk, remove := visit(w, w.MediaType, v, loc)
+	if walk {
+		w.schemaField(&v.Schema, loc.Append("schema"))
+		for _, k := range slices.Sorted(maps.Keys(v.Encoding)) {
+			e := v.Encoding[k]
+			walkMap(w, e.Headers, loc.Append("encoding", k, "headers"), (*walker).header)
+		}
+	}
+	return remove
+}
+
+func (w *walker) header(v *Header, loc jsonpointer.Ptr) bool {
+	walk, remove := visit(w, w.Header, v, loc)
+	if walk {
+		walkPtr(w, &v.Schema, loc.Append("schema"), (*walker).schema)
+		walkMap(w, v.Content, loc.Append("content"), (*walker).mediaType)
+	}
+	return remove
+}
+
+// schemaField walks v, a Schema which is not a pointer, unless it is empty.
+func (w *walker) schemaField(v *Schema, loc jsonpointer.Ptr) {
+	if !isEmptySchema(v) && w.schema(v, loc) {
+		*v = Schema{}
+	}
+}
+
+// isEmptySchema reports whether v has no keywords, ignoring the context its references are resolved in.
+func isEmptySchema(v *Schema) bool {
+	c := *v
+	c.ReferenceMixin = draft2020.ReferenceMixin[Schema]{Ref: v.Ref, DynamicRef: v.DynamicRef}
+	return reflect.ValueOf(c).IsZero()
+}
+
+func (w *walker) schema(v *Schema, loc jsonpointer.Ptr) bool {
+	walk, remove := visit(w, w.Schema, v, loc)
+	if walk {
+		walkMap(w, v.Defs, loc.Append("$defs"), (*walker).subschema)
+		walkSlice(w, &v.AllOf, loc.Append("allOf"), (*walker).subschema)
+		walkSlice(w, &v.AnyOf, loc.Append("anyOf"), (*walker).subschema)
+		walkSlice(w, &v.OneOf, loc.Append("oneOf"), (*walker).subschema)
+		walkPtr(w, &v.If, loc.Append("if"), (*walker).schema)
+		walkPtr(w, &v.Then, loc.Append("then"), (*walker).schema)
+		walkPtr(w, &v.Else, loc.Append("else"), (*walker).schema)
+		walkPtr(w, &v.Not, loc.Append("not"), (*walker).schema)
+		walkMap(w, v.Properties, loc.Append("properties"), (*walker).subschema)
+		walkMap(w, v.PatternProperties, loc.Append("patternProperties"), (*walker).subschema)
+		walkPtr(w, &v.AdditionalProperties, loc.Append("additionalProperties"), (*walker).orSchema)
+		walkPtr(w, &v.PropertyNames, loc.Append("propertyNames"), (*walker).schema)
+		walkMap(w, v.DependentSchemas, loc.Append("dependentSchemas"), (*walker).subschema)
+		walkSlice(w, &v.PrefixItems, loc.Append("prefixItems"), (*walker).subschema)
+		walkPtr(w, &v.Items, loc.Append("items"), (*walker).orSchema)
+		walkPtr(w, &v.Contains, loc.Append("contains"), (*walker).schema)
+		walkPtr(w, &v.UnevaluatedItems, loc.Append("unevaluatedItems"), (*walker).orSchema)
+		walkPtr(w, &v.UnevaluatedProperties, loc.Append("unevaluatedProperties"), (*walker).orSchema)
+		walkPtr(w, &v.ContentSchema, loc.Append("contentSchema"), (*walker).schema)
+	}
+	return remove
+}
+
+// subschema walks the schema p points to, if any.
+func (w *walker) subschema(p **Schema, loc jsonpointer.Ptr) bool {
+	return *p != nil && w.schema(*p, loc)
+}
+
+// orSchema walks the schema of a keyword which is a boolean or a schema, if it is one.
+func (w *walker) orSchema(v *ser.Or[bool, *Schema], loc jsonpointer.Ptr) bool {
+	return w.subschema(&v.Y, loc)
+}
@@ -0,0 +1,248 @@
+package oas
+
+import (
+	"encoding/json"
+	"fmt"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/MaiMee1/go-apispec/oas/jsonpointer"
+)
+
+const walkDocument = `{
+  "openapi": "3.1.0",
+  "info": {"title": "walk", "version": "1.0.0"},
+  "paths": {
+    "/pets/{id}": {
+      "parameters": [{"name": "id", "in": "path", "required": true, "schema": {"type": "integer"}}],
+      "get": {
+        "parameters": [{"name": "q", "in": "query", "content": {"application/json": {"schema": {"type": "string"}}}}],
+        "responses": {
+          "200": {
+            "description": "ok",
+            "headers": {"X-Rate": {"schema": {"type": "integer"}}},
+            "content": {"application/json": {"schema": {"type": "array", "items": {"$ref": "#/components/schemas/Pet"}}}}
+          }
+        },
+        "callbacks": {
+          "onEvent": {"{$request.body#/url}": {"post": {"requestBody": {"$ref": "#/components/requestBodies/Event"}}}}
+        }
+      }
+    }
+  },
+  "webhooks": {"newPet": {"post": {"responses": {"200": {"description": "ok"}}}}},
+  "components": {
+    "schemas": {
+      "Pet": {
+        "properties": {"name": {"type": "string"}, "tags": {"type": "array", "prefixItems": [{"type": "string"}]}},
+        "additionalProperties": false,
+        "allOf": [{"not": {"type": "null"}}]
+      }
+    },
+    "requestBodies": {"Event": {"content": {"application/json": {"schema": {"type": "object"}}}}}
+  }
+}`
+
+func TestOpenAPI_Walk(t *testing.T) {
+	doc, err := ParseJson([]byte(walkDocument))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var got []string
+	values := make(map[string]string) // JSON encodings of the visited values by location
+	record := func(kind string, v any, loc jsonpointer.Ptr) Action {
+		got = append(got, kind+" "+loc.String())
+		b, err := json.Marshal(v)
+		if err != nil {
+			t.Fatal(err)
+		}
+		values[loc.String()] = string(b)
+		return Continue
+	}
+	// Act
+	doc.Walk(Visitor{
+		PathItem:    func(v *PathItem, loc jsonpointer.Ptr) Action { return record("PathItem", v, loc) },
+		Operation:   func(v *Operation, loc jsonpointer.Ptr) Action { return record("Operation", v, loc) },
+		Parameter:   func(v *Parameter, loc jsonpointer.Ptr) Action { return record("Parameter", v, loc) },
+		RequestBody: func(v *RequestBody, loc jsonpointer.Ptr) Action { return record("RequestBody", v, loc) },
+		Response:    func(v *Response, loc jsonpointer.Ptr) Action { return record("Response", v, loc) },
+		MediaType:   func(v *MediaType, loc jsonpointer.Ptr) Action { return record("MediaType", v, loc) },
+		Header:      func(v *Header, loc jsonpointer.Ptr) Action { return record("Header", v, loc) },
+		Schema:      func(v *Schema, loc jsonpointer.Ptr) Action { return record("Schema", v, loc) },
+	})
+
+	want := []string{
+		"PathItem /paths/~1pets~1{id}",
+		"Operation /paths/~1pets~1{id}/get",
+		"Parameter /paths/~1pets~1{id}/get/parameters/0",
+		"MediaType /paths/~1pets~1{id}/get/parameters/0/content/application~1json",
+		"Schema /paths/~1pets~1{id}/get/parameters/0/content/application~1json/schema",
+		"Response /paths/~1pets~1{id}/get/responses/200",
+		"Header /paths/~1pets~1{id}/get/responses/200/headers/X-Rate",
+		"Schema /paths/~1pets~1{id}/get/responses/200/headers/X-Rate/schema",
+		"MediaType /paths/~1pets~1{id}/get/responses/200/content/application~1json",
+		"Schema /paths/~1pets~1{id}/get/responses/200/content/application~1json/schema",
+		"Schema /paths/~1pets~1{id}/get/responses/200/content/application~1json/schema/items",
+		"PathItem /paths/~1pets~1{id}/get/callbacks/onEvent/{$request.body#~1url}",
+		"Operation /paths/~1pets~1{id}/get/callbacks/onEvent/{$request.body#~1url}/post",
+		"RequestBody /paths/~1pets~1{id}/get/callbacks/onEvent/{$request.body#~1url}/post/requestBody",
+		"Parameter /paths/~1pets~1{id}/parameters/0",
+		"Schema /paths/~1pets~1{id}/parameters/0/schema",
+		"PathItem /webhooks/newPet",
+		"Operation /webhooks/newPet/post",
+		"Response /webhooks/newPet/post/responses/200",
+		"Schema /components/schemas/Pet",
+		"Schema /components/schemas/Pet/allOf/0",
+		"Schema /components/schemas/Pet/allOf/0/not",
+		"Schema /components/schemas/Pet/properties/name",
+		"Schema /components/schemas/Pet/properties/tags",
+		"Schema /components/schemas/Pet/properties/tags/prefixItems/0",
+		"RequestBody /components/requestBodies/Event",
+		"MediaType /components/requestBodies/Event/content/application~1json",
+		"Schema /components/requestBodies/Event/content/application~1json/schema",
+	}
+	if !slices.Equal(got, want) {
+		t.Errorf("got\n%s\nwant\n%s", strings.Join(got, "\n"), strings.Join(want, "\n"))
+	}
+	for loc, want := range values {
+		v, err := jsonpointer.Ptr(loc).Access(doc)
+		if err != nil {
+			t.Errorf("got %s, which is not in the document: %v", loc, err)
+			continue
+		}
+		b, err := json.Marshal(v.Interface())
+		if err != nil {
+			t.Fatal(err)
+		}
+		if string(b) != want {
+			t.Errorf("got %s at %s, want the visited %s", b, loc, want)
+		}
+	}
+}
+
+func TestOpenAPI_Walk_action(t *testing.T) {
+	tests := []struct {
+		visitor Visitor
+		want    map[jsonpointer.Ptr]string // JSON encodings of values of the walked document, empty if absent
+	}{
+		{
+			visitor: Visitor{Parameter: func(v *Parameter, _ jsonpointer.Ptr) Action {
+				if v.In == PathLocation {
+					return Remove
+				}
+				return Continue
+			}},
+			want: map[jsonpointer.Ptr]string{
+				"/paths/~1pets~1{id}/parameters":            `[]`,
+				"/paths/~1pets~1{id}/get/parameters/0/name": `"q"`,
+			},
+		},
+		{
+			visitor: Visitor{Schema: func(v *Schema, loc jsonpointer.Ptr) Action {
+				if strings.HasPrefix(string(loc), "/components/schemas/Pet/properties/") {
+					return Remove
+				}
+				return Continue
+			}},
+			want: map[jsonpointer.Ptr]string{
+				"/components/schemas/Pet/properties":           `{}`,
+				"/components/schemas/Pet/properties/tags":      ``,
+				"/components/schemas/Pet/additionalProperties": `false`,
+			},
+		},
+		{
+			visitor: Visitor{Operation: func(v *Operation, _ jsonpointer.Ptr) Action {
+				return Skip
+			}, Schema: func(v *Schema, _ jsonpointer.Ptr) Action {
+				*v = Schema{}
+				v.Ref = "#/components/schemas/Other"
+				return Continue
+			}},
+			want: map[jsonpointer.Ptr]string{
+				"/components/schemas/Pet":                                     `{"$ref":"#/components/schemas/Other"}`,
+				"/paths/~1pets~1{id}/get/responses/200/headers/X-Rate/schema": `{"type":"integer"}`,
+				"/paths/~1pets~1{id}/parameters/0/schema":                     `{"$ref":"#/components/schemas/Other"}`,
+			},
+		},
+		{
+			visitor: Visitor{PathItem: func(v *PathItem, _ jsonpointer.Ptr) Action {
+				return Stop
+			}, Schema: func(v *Schema, _ jsonpointer.Ptr) Action {
+				*v = Schema{}
+				return Continue
+			}},
+			want: map[jsonpointer.Ptr]string{
+				"/components/schemas/Pet/additionalProperties": `false`,
+				"/paths/~1pets~1{id}/parameters/0/schema":      `{"type":"integer"}`,
+			},
+		},
+		{
+			visitor: Visitor{Response: func(v *Response, loc jsonpointer.Ptr) Action {
+				if loc.Parent().Parent().Parent() == "/webhooks/newPet" {
+					return Remove
+				}
+				return Continue
+			}, MediaType: func(v *MediaType, _ jsonpointer.Ptr) Action {
+				return Remove
+			}},
+			want: map[jsonpointer.Ptr]string{
+				"/webhooks/newPet":                              `{"post":{}}`,
+				"/components/requestBodies/Event":               `{}`,
+				"/paths/~1pets~1{id}/get/responses/200/content": `{}`,
+				"/paths/~1pets~1{id}/get/parameters/0/content":  `{}`,
+			},
+		},
+	}
+	for i, tt := range tests {
+		t.Run(fmt.Sprintf("%d", i), func(t *testing.T) {
+			doc, err := ParseJson([]byte(walkDocument))
+			if err != nil {
+				t.Fatal(err)
+			}
+			// Act
+			doc.Walk(tt.visitor)
+
+			for ptr, want := range tt.want {
+				var got string
+				if v, err := ptr.Access(doc); err == nil {
+					b, err := json.Marshal(v.Interface())
+					if err != nil {
+						t.Fatal(err)
+					}
+					got = string(b)
+				}
+				if got != want {
+					t.Errorf("got %s at %s, want %s", got, ptr, want)
+				}
+			}
+		})
+	}
+}
+
+func TestOpenAPI_IterSchema(t *testing.T) {
+	doc, err := ParseJson([]byte(walkDocument))
+	if err != nil {
+		t.Fatal(err)
+	}
+	n := 0
+	// Act
+	for schema := range doc.IterSchema() {
+		if schema.Ref == "#/components/schemas/Pet" {
+			break
+		}
+		n++
+	}
+	if n != 3 {
+		t.Errorf("got %d schemas before the reference, want 3", n)
+	}
+	for schema := range doc.IterSchema() {
+		schema.Description = "walked"
+	}
+	if doc.Components.Schemas["Pet"].Description != "" {
+		t.Error("got a schema of components")
+	}
+	if doc.Paths["/pets/{id}"].Parameters[0].Schema.Description != "walked" {
+		t.Error("got a schema of paths not modified")
+	}
+}
@@ -0,0 +1,145 @@
+package oas
+
+import (
+	"context"
+	"errors"
+	"net/url"
+	"reflect"
+	"strconv"
+	"strings"
+
+	"github.com/MaiMee1/go-apispec/oas/resolve"
+)
+
+type reference interface {
+	Context() context.Context
+}
+
+var referenceType = reflect.TypeFor[reference]()
+
+// refTarget returns the absolute URI referred to by v, a struct embedding draft2020.ReferenceMixin.
+func refTarget(v reflect.Value) (*url.URL, error) {
+	ref := v.FieldByName("Ref").String()
+	u, err := url.Parse(ref)
+	if err != nil {
+		return nil, err
+	}
+	_, base, ok := resolve.Scope(v.Addr().Interface().(reference).Context())
+	if !ok {
+		return nil, &resolve.Error{Ref: ref, Err: errors.New("no resolver in context")}
+	}
+	return base.ResolveReference(u), nil
+}
+
+// resolveValue returns an addressable copy of the value referred to by v.
+func resolveValue(v reflect.Value) (reflect.Value, error) {
+	out := v.Addr().MethodByName("Resolve").Call(nil)
+	if err, _ := out[1].Interface().(error); err != nil {
+		return reflect.Value{}, err
+	}
+	value := out[0]
+	if value.Kind() == reflect.Pointer {
+		value = value.Elem()
+	}
+	p := reflect.New(value.Type()).Elem()
+	p.Set(value)
+	return p, nil
+}
+
+// walkFunc is called by walkRefs with an addressable struct and the JSON Pointer tokens of its location.
+type walkFunc func(v reflect.Value, loc []string) error
+
+// walkRefs recursively finds structs of v embedding draft2020.ReferenceMixin with a non-empty Ref and calls fn with
+// them instead of walking their fields.
+func walkRefs(v reflect.Value, loc []string, fn walkFunc) error {
+	switch v.Kind() {
+	case reflect.Pointer:
+		if v.IsNil() {
+			return nil
+		}
+		return walkRefs(v.Elem(), loc, fn)
+	case reflect.Struct:
+		if _, ok := v.Addr().Interface().(reference); ok && v.FieldByName("Ref").String() != "" {
+			return fn(v, loc)
+		}
+		return walkFields(v, loc, fn)
+	case reflect.Slice, reflect.Array:
+		if !isStructLike(v.Type().Elem()) {
+			return nil
+		}
+		for i := 0; i < v.Len(); i++ {
+			if err := walkRefs(v.Index(i), append(loc, strconv.Itoa(i)), fn); err != nil {
+				return err
+			}
+		}
+	case reflect.Map:
+		if !isStructLike(v.Type().Elem()) {
+			return nil
+		}
+		// map values are not addressable, so walk a copy and put it back
+		it := v.MapRange()
+		for it.Next() {
+			p := reflect.New(it.Value().Type()).Elem()
+			p.Set(it.Value())
+			if err := walkRefs(p, append(loc, it.Key().String()), fn); err != nil {
+				return err
+			}
+			v.SetMapIndex(it.Key(), p)
+		}
+	default:
+	}
+	return nil
+}
+
+// walkFields calls walkRefs on the settable fields of v other than an embedded draft2020.ReferenceMixin.
+func walkFields(v reflect.Value, loc []string, fn walkFunc) error {
+	for i := 0; i < v.NumField(); i++ {
+		sf := v.Type().Field(i)
+		if sf.Anonymous && reflect.PointerTo(sf.Type).Implements(referenceType) {
+			continue
+		}
+		if f := v.Field(i); f.CanSet() && isStructLike(f.Type()) {
+			name, ok := jsonName(sf)
+			if !ok {
+				continue
+			}
+			floc := loc
+			if name != "" {
+				floc = append(loc[:len(loc):len(loc)], name)
+			}
+			if err := walkRefs(f, floc, fn); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// jsonName returns the name of sf in JSON, which is empty if its members are inlined into the parent object, and
+// false if sf is not serialized.
+func jsonName(sf reflect.StructField) (string, bool) {
+	tag := sf.Tag.Get("json")
+	if tag == "-" {
+		return "", false
+	}
+	name, opts, _ := strings.Cut(tag, ",")
+	if name == "" && (sf.Anonymous || opts == "inline") {
+		return "", true
+	}
+	if name == "" {
+		name = sf.Name
+	}
+	return name, true
+}
+
+// isStructLike reports whether values of t may contain structs.
+func isStructLike(t reflect.Type) bool {
+	switch t.Kind() {
+	case reflect.Pointer, reflect.Slice, reflect.Array, reflect.Map:
+		return isStructLike(t.Elem())
+	case reflect.Struct:
+		return true
+	default:
+		return false
+	}
+}
@@ -0,0 +1,33 @@
+package oas
+
+import (
+	"encoding/json"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/MaiMee1/go-apispec/oas/ser"
+)
+
+// MarshalYAML returns the document as a YAML node whose keys follow the same order as MarshalJSON.
+//
+//goland:noinspection GoMixedReceiverTypes
+func (doc OpenAPI) MarshalYAML() (interface{}, error) {
+	b, err := json.Marshal(doc)
+	if err != nil {
+		return nil, err
+	}
+	return ser.JsonToYaml(b)
+}
+
+// UnmarshalYAML decodes the document through its JSON representation.
+//
+// Unlike ParseYaml, it does not set up contexts for resolving references.
+//
+//goland:noinspection GoMixedReceiverTypes
+func (doc *OpenAPI) UnmarshalYAML(value *yaml.Node) error {
+	b, err := ser.YamlToJson(value)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(b, doc)
+}